@@ -1,55 +1,93 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"runtime"
-	"sync"
+	"os/signal"
+	"syscall"
 
 	"github.com/joho/godotenv"
+	"github.com/lucasBiazon/botany-back/internal/config"
 	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/jobs"
+	"github.com/lucasBiazon/botany-back/internal/repositories"
 	"github.com/lucasBiazon/botany-back/internal/routes"
 	services "github.com/lucasBiazon/botany-back/internal/service"
 )
 
 func main() {
-	// go routines
 	err := godotenv.Load(".env")
 	if err != nil {
 		fmt.Println("Error loading .env file")
 	}
-	runtime.GOMAXPROCS(1)
-	wg := &sync.WaitGroup{}
-	wg.Add(2)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	// Init database and redis
-	db, clientRedis, err := database.InitDB()
+	db, clientRedis, err := database.InitDB(cfg)
 	if err != nil {
 		log.Panic(err)
 	}
 
+	go db.Monitor(ctx, cfg.Database.HealthCheckPeriod)
+	go jobs.NewTrashPurger(repositories.NewTrashRepository(db), cfg.Trash.Retention, cfg.Trash.PurgeInterval).Run(ctx)
+	go jobs.NewHistoryCompactor(repositories.NewHistoryRepository(db), cfg.History.Retention, cfg.History.Granularity, cfg.History.PartitionsAhead, cfg.History.RollupInterval).Run(ctx)
+
 	// // Init user use cases
-	secretKey := os.Getenv("JWT_SECRET_KEY")
-	jwtService := services.NewJWTService(secretKey)
-	r, err := routes.InitializeRoutes(db, clientRedis, jwtService)
+	jwtService := services.NewJWTService(cfg.JWT.Secret, cfg.JWT.TTL)
+	r, err := routes.InitializeRoutes(ctx, db, clientRedis, jwtService, cfg)
 	if err != nil {
 		log.Panic(err)
 	}
 
 	// Start server
-	local := os.Getenv("API_PORT")
-	if local == "" {
-		local = "8080"
+	local := cfg.Server.Port
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", local),
+		Handler:           r,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
 	}
+
+	serverErr := make(chan error, 1)
 	go func() {
-		defer wg.Done()
-		for {
-			http.ListenAndServe(fmt.Sprintf(":%s", local), r)
+		log.Printf("Server running on port %s\n", local)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
 		}
+		close(serverErr)
 	}()
-	log.Printf("Server running on port %s\n", local)
 
-	wg.Wait()
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Printf("Erro no servidor HTTP: %v\n", err)
+		}
+	case <-ctx.Done():
+		log.Println("Sinal recebido, encerrando servidor")
+	}
+
+	// drena as conexões abertas antes de fechar banco e redis
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Erro ao encerrar servidor: %v\n", err)
+	}
+
+	if err := clientRedis.Close(); err != nil {
+		log.Printf("Erro ao fechar conexão com Redis: %v\n", err)
+	}
+	db.Close()
+	log.Println("Servidor encerrado")
 }
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/joho/godotenv"
+	"github.com/lucasBiazon/botany-back/internal/config"
+	"github.com/lucasBiazon/botany-back/internal/database"
+)
+
+const usage = `uso: migrate <comando> [argumento]
+
+comandos:
+  up [N]       aplica todas as migrations pendentes, ou só as próximas N
+  down [N]     desfaz as últimas N migrations (padrão 1)
+  status       mostra a versão aplicada, a última disponível e se o banco está sujo
+  version      mostra só a versão aplicada
+  force V      marca a versão V como aplicada e limpa a flag dirty, sem rodar SQL`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(".env"); err != nil {
+		fmt.Println("Error loading .env file")
+	}
+
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	db, err := database.ConnectPG(ctx, *cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := database.NewMigrate(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer m.Close()
+
+	if err := run(m, os.Args[1], os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(m *migrate.Migrate, command string, args []string) error {
+	switch command {
+	case "up":
+		n, err := optionalSteps(args, 0)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			err = m.Up()
+		} else {
+			err = m.Steps(n)
+		}
+		return report(m, err)
+	case "down":
+		n, err := optionalSteps(args, 1)
+		if err != nil {
+			return err
+		}
+		return report(m, m.Steps(-n))
+	case "status":
+		return status(m)
+	case "version":
+		version, dirty, err := m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			fmt.Println("nenhuma migration aplicada")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(formatVersion(version, dirty))
+		return nil
+	case "force":
+		if len(args) != 1 {
+			return errors.New("force exige a versão (ex: migrate force 18)")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("versão inválida: %q", args[0])
+		}
+		return report(m, m.Force(version))
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	return nil
+}
+
+func optionalSteps(args []string, fallback int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("quantidade de migrations inválida: %q", args[0])
+	}
+	return n, nil
+}
+
+// report imprime onde o banco ficou depois de um comando que altera a versão.
+func report(m *migrate.Migrate, err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("nada a fazer")
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	return status(m)
+}
+
+func status(m *migrate.Migrate) error {
+	latest, err := database.LatestMigrationVersion()
+	if err != nil {
+		return err
+	}
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Printf("aplicada: nenhuma\ndisponível: %d\n", latest)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("aplicada: %s\ndisponível: %d\n", formatVersion(version, dirty), latest)
+	if dirty {
+		fmt.Println("a última migration falhou no meio; corrija o banco e use force com a versão correta")
+	}
+	return nil
+}
+
+func formatVersion(version uint, dirty bool) string {
+	if dirty {
+		return fmt.Sprintf("%d (dirty)", version)
+	}
+	return strconv.FormatUint(uint64(version), 10)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/config"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/repositories"
+	usecases_specie "github.com/lucasBiazon/botany-back/internal/usecases/specie"
+)
+
+const usage = `uso: species <comando> [opções]
+
+comandos:
+  import [-dry-run] [-format csv|json] <arquivo>   grava o catálogo, casando pelo nome científico
+  export [-format csv|json] [arquivo]              sem arquivo, escreve na saída padrão
+
+o formato vem da extensão do arquivo quando -format não é informado`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(".env"); err != nil {
+		fmt.Fprintln(os.Stderr, "Error loading .env file")
+	}
+
+	// usa a configuração completa da API para invalidar o cache de espécies das instâncias no ar
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	primary, err := database.ConnectPG(ctx, cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db := database.NewCluster(primary, nil)
+	defer db.Close()
+	clientRedis, err := database.InitRedisClient(ctx, cfg.Redis)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer clientRedis.Close()
+
+	cacheStore := cache.NewFallback(clientRedis, cache.NewLRU(cfg.Cache.LocalSize))
+	readThrough := cache.NewReadThrough(cacheStore, cache.NewPGBus(db.Primary, cfg.Cache.InvalidationChannel))
+	repository := repositories.NewCachedSpeciesRepository(repositories.NewSpeciesRepository(db), readThrough, cfg.Cache.SpeciesTTL)
+
+	switch os.Args[1] {
+	case "import":
+		err = runImport(ctx, usecases_specie.NewImportSpecieCatalogUseCase(repository), os.Args[2:])
+	case "export":
+		err = runExport(ctx, usecases_specie.NewExportSpecieCatalogUseCase(repository), os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		var invalid *usecases_specie.CatalogValidationError
+		if errors.As(err, &invalid) {
+			fmt.Fprintln(os.Stderr, "catálogo inválido:")
+			for _, problem := range invalid.Problems {
+				fmt.Fprintln(os.Stderr, "  "+problem)
+			}
+			os.Exit(1)
+		}
+		log.Fatal(err)
+	}
+}
+
+func runImport(ctx context.Context, uc *usecases_specie.ImportSpecieCatalogUseCase, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "mostra o diff sem gravar")
+	format := flags.String("format", "", "csv ou json")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("import exige o arquivo do catálogo")
+	}
+	path := flags.Arg(0)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	items, err := usecases_specie.DecodeSpecieCatalog(file, formatFor(*format, path))
+	if err != nil {
+		return err
+	}
+	output, err := uc.Execute(ctx, usecases_specie.ImportSpecieCatalogInputDTO{Items: items, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+	printDiff(os.Stdout, output)
+	return nil
+}
+
+func runExport(ctx context.Context, uc *usecases_specie.ExportSpecieCatalogUseCase, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "", "csv ou json")
+	flags.Parse(args)
+
+	items, err := uc.Execute(ctx)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usecases_specie.EncodeSpecieCatalog(os.Stdout, formatFor(*format, ""), items)
+	}
+
+	path := flags.Arg(0)
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := usecases_specie.EncodeSpecieCatalog(file, formatFor(*format, path), items); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d espécies exportadas para %s\n", len(items), path)
+	return nil
+}
+
+func formatFor(format, path string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return usecases_specie.CatalogFormatCSV
+	}
+	return usecases_specie.CatalogFormatJSON
+}
+
+func printDiff(w io.Writer, output *usecases_specie.ImportSpecieCatalogOutputDTO) {
+	for _, name := range output.Created {
+		fmt.Fprintf(w, "+ %s\n", name)
+	}
+	for _, updated := range output.Updated {
+		fmt.Fprintf(w, "~ %s\n", updated.ScientificName)
+		for _, change := range updated.Changes {
+			fmt.Fprintf(w, "    %s: %q -> %q\n", change.Field, change.Old, change.New)
+		}
+	}
+	fmt.Fprintf(w, "%d novas, %d alteradas, %d sem alteração\n", len(output.Created), len(output.Updated), output.Unchanged)
+	if output.DryRun {
+		fmt.Fprintln(w, "simulação: nada foi gravado")
+	}
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker do Redis aberto")
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+// Breaker é um hook do go-redis que corta as chamadas ao Redis depois de
+// falhas consecutivas, evitando que cada requisição espere pelo timeout.
+type Breaker struct {
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	threshold int
+	cooldown  time.Duration
+	openedAt  time.Time
+	probing   bool
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Available indica se o Redis está sendo usado normalmente.
+func (b *Breaker) Available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == stateClosed
+}
+
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = stateHalfOpen
+		b.probing = true
+		return nil
+	case stateHalfOpen:
+		// só uma chamada de teste por vez enquanto meio aberto
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isConnectionError(err) {
+		if b.state != stateClosed {
+			log.Println("Redis disponível novamente, saindo do modo degradado")
+		}
+		b.state = stateClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == stateHalfOpen || b.failures >= b.threshold {
+		if b.state == stateClosed {
+			log.Printf("Redis indisponível, entrando em modo degradado: %v", err)
+		}
+		b.state = stateOpen
+		b.openedAt = time.Now()
+		b.probing = false
+	}
+}
+
+// isConnectionError separa falhas de infraestrutura de respostas normais do Redis.
+func isConnectionError(err error) bool {
+	if err == nil || err == redis.Nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var redisErr redis.Error
+	return !errors.As(err, &redisErr)
+}
+
+func (b *Breaker) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, b.allow()
+}
+
+func (b *Breaker) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	if errors.Is(cmd.Err(), ErrCircuitOpen) {
+		return nil
+	}
+	b.record(cmd.Err())
+	return nil
+}
+
+func (b *Breaker) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, b.allow()
+}
+
+func (b *Breaker) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil && cmd.Err() != redis.Nil {
+			err = cmd.Err()
+			break
+		}
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return nil
+	}
+	b.record(err)
+	return nil
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Event avisa as outras instâncias que as tags mudaram para version.
+type Event struct {
+	Origin  string   `json:"origin"`
+	Tags    []string `json:"tags"`
+	Version string   `json:"version"`
+}
+
+// Bus propaga invalidações entre as instâncias da API.
+type Bus interface {
+	Publish(ctx context.Context, event Event) error
+	// Listen bloqueia entregando os eventos a handle até ctx terminar.
+	Listen(ctx context.Context, handle func(Event))
+}
+
+// PGBus usa LISTEN/NOTIFY do Postgres. O Redis não serve aqui porque é
+// justamente quando ele cai que as instâncias passam a usar o cache local.
+type PGBus struct {
+	DB      *pgxpool.Pool
+	Channel string
+}
+
+func NewPGBus(db *pgxpool.Pool, channel string) *PGBus {
+	return &PGBus{
+		DB:      db,
+		Channel: channel,
+	}
+}
+
+func (b *PGBus) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := b.DB.Exec(ctx, `SELECT pg_notify($1, $2)`, b.Channel, string(payload)); err != nil {
+		return fmt.Errorf("erro ao publicar invalidação de cache: %w", err)
+	}
+	return nil
+}
+
+func (b *PGBus) Listen(ctx context.Context, handle func(Event)) {
+	backoff := time.Second
+	for {
+		err := b.listen(ctx, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Conexão de invalidação de cache perdida, reconectando em %s: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// listen segura uma conexão do pool só para o LISTEN até ela cair ou ctx terminar.
+func (b *PGBus) listen(ctx context.Context, handle func(Event)) error {
+	conn, err := b.DB.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{b.Channel}.Sanitize()); err != nil {
+		return err
+	}
+	// a conexão volta para o pool, então não pode continuar inscrita no canal
+	defer conn.Exec(context.Background(), "UNLISTEN *")
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Printf("Evento de invalidação de cache inválido: %v", err)
+			continue
+		}
+		handle(event)
+	}
+}
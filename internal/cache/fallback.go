@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	ErrMiss        = errors.New("chave não encontrada no cache")
+	ErrUnavailable = errors.New("cache indisponível")
+)
+
+// Fallback usa o Redis como armazenamento principal e um LRU local quando ele falha.
+// O LRU só recebe escritas feitas durante a indisponibilidade, então ao voltar
+// o Redis continua sendo a fonte de verdade.
+type Fallback struct {
+	RD    *redis.Client
+	Local *LRU
+}
+
+func NewFallback(rd *redis.Client, local *LRU) *Fallback {
+	return &Fallback{
+		RD:    rd,
+		Local: local,
+	}
+}
+
+func (f *Fallback) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := f.RD.Get(ctx, key).Bytes()
+	if err == nil {
+		return value, nil
+	}
+	if err == redis.Nil {
+		// pode ter sido gravado localmente enquanto o Redis estava fora
+		if local, ok := f.Local.Get(key); ok {
+			return local, nil
+		}
+		return nil, ErrMiss
+	}
+
+	if local, ok := f.Local.Get(key); ok {
+		return local, nil
+	}
+	// o valor pode existir no Redis, então quem chama decide como tratar a dúvida
+	return nil, ErrUnavailable
+}
+
+func (f *Fallback) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := f.RD.Set(ctx, key, value, ttl).Err(); err != nil {
+		if !errors.Is(err, ErrCircuitOpen) {
+			log.Printf("Erro ao gravar chave %s no Redis, usando cache local: %v", key, err)
+		}
+		f.Local.Set(key, value, ttl)
+		return nil
+	}
+	f.Local.Delete(key)
+	return nil
+}
+
+func (f *Fallback) Del(ctx context.Context, keys ...string) error {
+	f.Local.Delete(keys...)
+	if err := f.RD.Del(ctx, keys...).Err(); err != nil && !errors.Is(err, ErrCircuitOpen) {
+		log.Printf("Erro ao remover chaves do Redis: %v", err)
+	}
+	return nil
+}
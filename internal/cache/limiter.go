@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// LocalLimiter é um token bucket em memória usado quando o Redis não responde.
+// Os limites valem por instância, então são mais permissivos que o limitador distribuído.
+type LocalLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	rate      float64
+	burst     float64
+	lastPrune time.Time
+}
+
+func NewLocalLimiter(perMinute int) *LocalLimiter {
+	return &LocalLimiter{
+		buckets:   make(map[string]*bucket),
+		rate:      float64(perMinute) / 60,
+		burst:     float64(perMinute),
+		lastPrune: time.Now(),
+	}
+}
+
+// Allow consome um token da chave e, se não houver, informa quanto tempo esperar.
+func (l *LocalLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*l.rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// prune descarta buckets que já voltaram a ficar cheios para não crescer sem limite.
+func (l *LocalLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+	l.lastPrune = now
+
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > refill {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+// tagTTL precisa ser maior que qualquer TTL de entrada: se a versão de uma tag
+// expirar ela volta para "0", e nenhuma entrada gravada com esse "0" pode sobrar.
+const tagTTL = 24 * time.Hour
+
+// sharedLoadTimeout limita a carga compartilhada entre leituras concorrentes, que
+// não segue o prazo de nenhuma delas.
+const sharedLoadTimeout = 30 * time.Second
+
+// ReadThrough guarda resultados de leitura com chaves versionadas por tags.
+// Invalidar uma tag troca sua versão, o que muda a chave de todas as entradas
+// que dependem dela sem precisar procurá-las no Redis. Com Bus as outras
+// instâncias recebem a nova versão para o cache local delas.
+type ReadThrough struct {
+	Store *Fallback
+	Bus   Bus
+	// Settle é quanto uma réplica pode demorar para refletir uma escrita: leituras de
+	// tags invalidadas há menos tempo que isso são marcadas com Fresh.
+	Settle time.Duration
+	origin string
+	group  singleflight.Group
+}
+
+type freshKey struct{}
+
+// Fresh informa se load foi chamado logo depois de uma invalidação, quando só o
+// primário garante o dado novo.
+func Fresh(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshKey{}).(bool)
+	return fresh
+}
+
+func NewReadThrough(store *Fallback, bus Bus) *ReadThrough {
+	return &ReadThrough{
+		Store:  store,
+		Bus:    bus,
+		origin: uuid.NewString(),
+	}
+}
+
+func tagKey(tag string) string {
+	return "cache:tag:" + tag
+}
+
+// versions devolve a versão atual de cada tag e se alguma delas mudou há menos
+// de Settle. ok é false quando o cache não consegue responder e a leitura deve
+// ir direto ao banco.
+func (c *ReadThrough) versions(ctx context.Context, tags []string) (version string, recent bool, ok bool) {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		value, err := c.Store.Get(ctx, tagKey(tag))
+		switch {
+		case err == nil:
+			parts[i] = string(value)
+			// invalidações feitas com o Redis fora só chegaram ao cache local
+			if local, ok := c.Store.Local.Get(tagKey(tag)); ok {
+				parts[i] = newerVersion(parts[i], string(local))
+			}
+		case errors.Is(err, ErrMiss):
+			parts[i] = "0"
+		default:
+			return "", false, false
+		}
+		// a versão é o instante da invalidação em base 36
+		if nanos, err := strconv.ParseInt(parts[i], 36, 64); err == nil && time.Since(time.Unix(0, nanos)) < c.Settle {
+			recent = true
+		}
+	}
+	return strings.Join(parts, "."), recent, true
+}
+
+// newerVersion escolhe a versão mais recente; versões ilegíveis perdem.
+func newerVersion(a, b string) string {
+	an, aErr := strconv.ParseInt(a, 36, 64)
+	bn, bErr := strconv.ParseInt(b, 36, 64)
+	if aErr != nil || (bErr == nil && bn > an) {
+		return b
+	}
+	return a
+}
+
+// Invalidate descarta tudo o que foi gravado com alguma das tags.
+func (c *ReadThrough) Invalidate(ctx context.Context, tags ...string) {
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	for _, tag := range tags {
+		if err := c.Store.Set(ctx, tagKey(tag), []byte(version), tagTTL); err != nil {
+			log.Printf("Erro ao invalidar tag %s do cache: %v", tag, err)
+		}
+	}
+
+	if c.Bus == nil {
+		return
+	}
+	event := Event{Origin: c.origin, Tags: tags, Version: version}
+	if err := c.Bus.Publish(ctx, event); err != nil {
+		log.Printf("Erro ao avisar outras instâncias da invalidação: %v", err)
+	}
+}
+
+// Listen aplica as invalidações feitas pelas outras instâncias até ctx terminar.
+// Só o cache local é atualizado: o Redis é compartilhado e quem publicou já
+// gravou nele, e regravar uma versão fora de ordem poderia ressuscitar entradas velhas.
+func (c *ReadThrough) Listen(ctx context.Context) {
+	if c.Bus == nil {
+		return
+	}
+	c.Bus.Listen(ctx, func(event Event) {
+		if event.Origin == c.origin {
+			return
+		}
+		for _, tag := range event.Tags {
+			c.Store.Local.Set(tagKey(tag), []byte(event.Version), tagTTL)
+		}
+	})
+}
+
+// Fetch devolve o valor de key se ainda valer para as versões atuais de tags;
+// senão chama load uma única vez por chave, mesmo com leituras concorrentes, e
+// grava o resultado por ttl. Falhas do cache nunca impedem a leitura.
+func Fetch[T any](ctx context.Context, c *ReadThrough, key string, tags []string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	version, recent, ok := c.versions(ctx, tags)
+	if !ok {
+		return load(ctx)
+	}
+	key = "cache:" + key + ":" + version
+	if recent {
+		ctx = context.WithValue(ctx, freshKey{}, true)
+	}
+
+	if data, err := c.Store.Get(ctx, key); err == nil {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+		log.Printf("Erro ao ler entrada %s do cache, buscando no banco: %v", key, err)
+	}
+
+	// a carga serve a todos que esperam a mesma chave, então não pode ser cancelada
+	// junto com a requisição que chegou primeiro; cada uma desiste pelo próprio ctx
+	shared := c.group.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedLoadTimeout)
+		defer cancel()
+		value, err := load(loadCtx)
+		if err != nil {
+			return value, err
+		}
+		if data, err := json.Marshal(value); err == nil {
+			c.Store.Set(loadCtx, key, data, ttl)
+		}
+		return value, nil
+	})
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case result := <-shared:
+		if result.Err != nil {
+			var zero T
+			return zero, result.Err
+		}
+		return result.Val.(T), nil
+	}
+}
@@ -0,0 +1,398 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	Database  DatabaseConfig  `yaml:"database" toml:"database"`
+	Redis     RedisConfig     `yaml:"redis" toml:"redis"`
+	JWT       JWTConfig       `yaml:"jwt" toml:"jwt"`
+	Email     EmailConfig     `yaml:"email" toml:"email"`
+	Security  SecurityConfig  `yaml:"security" toml:"security"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	Cache     CacheConfig     `yaml:"cache" toml:"cache"`
+	Tokens    TokensConfig    `yaml:"tokens" toml:"tokens"`
+	Trash     TrashConfig     `yaml:"trash" toml:"trash"`
+	History   HistoryConfig   `yaml:"history" toml:"history"`
+	CareTasks CareTasksConfig `yaml:"care_tasks" toml:"care_tasks"`
+}
+
+type ServerConfig struct {
+	Port              string        `yaml:"port" toml:"port"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// prazo padrão de cada requisição; RouteTimeouts sobrescreve por grupo de rotas (ex: search, plant)
+	RequestTimeout time.Duration            `yaml:"request_timeout" toml:"request_timeout"`
+	RouteTimeouts  map[string]time.Duration `yaml:"route_timeouts" toml:"route_timeouts"`
+}
+
+// Timeout devolve o prazo configurado para o grupo de rotas.
+func (s ServerConfig) Timeout(route string) time.Duration {
+	if timeout, ok := s.RouteTimeouts[route]; ok {
+		return timeout
+	}
+	return s.RequestTimeout
+}
+
+type DatabaseConfig struct {
+	URL               string        `yaml:"url" toml:"url"`
+	MaxConns          int           `yaml:"max_conns" toml:"max_conns"`
+	MinConns          int           `yaml:"min_conns" toml:"min_conns"`
+	ConnMaxLifetime   time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	ConnMaxIdleTime   time.Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" toml:"health_check_period"`
+	// 0 desliga o cache de prepared statements, necessário atrás do PgBouncer em modo transaction
+	StatementCacheCapacity int `yaml:"statement_cache_capacity" toml:"statement_cache_capacity"`
+	// réplica opcional para as leituras; vazia, tudo vai para o primário
+	ReplicaURL string `yaml:"replica_url" toml:"replica_url"`
+	// por quanto tempo um cliente que escreveu continua lendo do primário
+	ReplicaStickyWindow time.Duration `yaml:"replica_sticky_window" toml:"replica_sticky_window"`
+	// aplica as migrations pendentes ao subir; desligado, elas rodam só pelo cmd/migrate
+	MigrateOnStart bool `yaml:"migrate_on_start" toml:"migrate_on_start"`
+}
+
+type RedisConfig struct {
+	Addr             string        `yaml:"addr" toml:"addr"`
+	Password         string        `yaml:"password" toml:"password"`
+	DB               int           `yaml:"db" toml:"db"`
+	DialTimeout      time.Duration `yaml:"dial_timeout" toml:"dial_timeout"`
+	ReadTimeout      time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	WriteTimeout     time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	BreakerThreshold int           `yaml:"breaker_threshold" toml:"breaker_threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown" toml:"breaker_cooldown"`
+}
+
+type JWTConfig struct {
+	Secret string        `yaml:"secret" toml:"secret"`
+	TTL    time.Duration `yaml:"ttl" toml:"ttl"`
+}
+
+type EmailConfig struct {
+	SMTPHost string `yaml:"smtp_host" toml:"smtp_host"`
+	SMTPPort int    `yaml:"smtp_port" toml:"smtp_port"`
+	User     string `yaml:"user" toml:"user"`
+	Password string `yaml:"password" toml:"password"`
+}
+
+type SecurityConfig struct {
+	APIKey string `yaml:"api_key" toml:"api_key"`
+	// chave das rotas /api/v1/admin; vazia, elas não são registradas
+	AdminKey string `yaml:"admin_key" toml:"admin_key"`
+}
+
+type RateLimitConfig struct {
+	RequestsPerMinute int           `yaml:"requests_per_minute" toml:"requests_per_minute"`
+	MaxFailures       int           `yaml:"max_failures" toml:"max_failures"`
+	FailWindow        time.Duration `yaml:"fail_window" toml:"fail_window"`
+	JailDuration      time.Duration `yaml:"jail_duration" toml:"jail_duration"`
+}
+
+type CacheConfig struct {
+	ListTTL    time.Duration `yaml:"list_ttl" toml:"list_ttl"`
+	ItemTTL    time.Duration `yaml:"item_ttl" toml:"item_ttl"`
+	SpeciesTTL time.Duration `yaml:"species_ttl" toml:"species_ttl"`
+	PlantTTL   time.Duration `yaml:"plant_ttl" toml:"plant_ttl"`
+	GardenTTL  time.Duration `yaml:"garden_ttl" toml:"garden_ttl"`
+	TaskTTL    time.Duration `yaml:"task_ttl" toml:"task_ttl"`
+	LocalSize  int           `yaml:"local_size" toml:"local_size"`
+	// canal do Postgres usado para avisar as outras instâncias das invalidações
+	InvalidationChannel string `yaml:"invalidation_channel" toml:"invalidation_channel"`
+}
+
+type TokensConfig struct {
+	EmailVerificationTTL time.Duration `yaml:"email_verification_ttl" toml:"email_verification_ttl"`
+	PasswordResetTTL     time.Duration `yaml:"password_reset_ttl" toml:"password_reset_ttl"`
+	// validade dos convites para um workspace
+	WorkspaceInvitationTTL time.Duration `yaml:"workspace_invitation_ttl" toml:"workspace_invitation_ttl"`
+}
+
+type TrashConfig struct {
+	// por quanto tempo um item excluído pode ser restaurado
+	Retention     time.Duration `yaml:"retention" toml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval" toml:"purge_interval"`
+}
+
+type HistoryConfig struct {
+	// registros mais antigos que isso são resumidos por Granularity (week ou month)
+	Retention      time.Duration `yaml:"retention" toml:"retention"`
+	Granularity    string        `yaml:"granularity" toml:"granularity"`
+	RollupInterval time.Duration `yaml:"rollup_interval" toml:"rollup_interval"`
+	// quantos meses de partições ficam criados à frente do atual
+	PartitionsAhead int `yaml:"partitions_ahead" toml:"partitions_ahead"`
+}
+
+type CareTasksConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// gera a tarefa quando o cuidado vence dentro desse prazo
+	Horizon  time.Duration `yaml:"horizon" toml:"horizon"`
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+}
+
+// Default traz os mesmos valores que antes estavam fixos no código.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:              "8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			RequestTimeout:    10 * time.Second,
+			RouteTimeouts: map[string]time.Duration{
+				"search": 15 * time.Second,
+			},
+		},
+		Database: DatabaseConfig{
+			MaxConns:          10,
+			MinConns:          2,
+			ConnMaxLifetime:   time.Hour,
+			ConnMaxIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+
+			StatementCacheCapacity: 512,
+			ReplicaStickyWindow:    5 * time.Second,
+			MigrateOnStart:         true,
+		},
+		Redis: RedisConfig{
+			DialTimeout:      2 * time.Second,
+			ReadTimeout:      time.Second,
+			WriteTimeout:     time.Second,
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+		},
+		JWT: JWTConfig{
+			TTL: 72 * time.Hour,
+		},
+		Email: EmailConfig{
+			SMTPHost: "smtp.gmail.com",
+			SMTPPort: 587,
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerMinute: 100,
+			MaxFailures:       5,
+			FailWindow:        time.Minute,
+			JailDuration:      5 * time.Minute,
+		},
+		Cache: CacheConfig{
+			ListTTL:    20 * time.Minute,
+			ItemTTL:    10 * time.Minute,
+			SpeciesTTL: 20 * time.Minute,
+			PlantTTL:   5 * time.Minute,
+			GardenTTL:  5 * time.Minute,
+			TaskTTL:    5 * time.Minute,
+			LocalSize:  10000,
+
+			InvalidationChannel: "cache_invalidation",
+		},
+		Tokens: TokensConfig{
+			EmailVerificationTTL: 10 * time.Minute,
+			PasswordResetTTL:     10 * time.Minute,
+
+			WorkspaceInvitationTTL: 7 * 24 * time.Hour,
+		},
+		Trash: TrashConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		History: HistoryConfig{
+			Retention:       180 * 24 * time.Hour,
+			Granularity:     "month",
+			RollupInterval:  6 * time.Hour,
+			PartitionsAhead: 3,
+		},
+		CareTasks: CareTasksConfig{
+			Enabled:  true,
+			Horizon:  24 * time.Hour,
+			Interval: 15 * time.Minute,
+		},
+	}
+}
+
+// Load monta a configuração na ordem: valores padrão, arquivo opcional (CONFIG_FILE) e variáveis de ambiente.
+func Load() (*Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadDatabase lê a mesma configuração que Load, mas só exige o necessário para
+// falar com o banco. É o que as ferramentas de linha de comando usam.
+func LoadDatabase() (*DatabaseConfig, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(cfg.Database.URL) == "" {
+		return nil, errors.New("database.url é obrigatório (DATABASE_URL)")
+	}
+	return &cfg.Database, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo de configuração %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("formato de arquivo de configuração não suportado: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao interpretar arquivo de configuração %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	required := func(value, name, env string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Errorf("%s é obrigatório (%s)", name, env))
+		}
+	}
+	positive := func(value time.Duration, name string) {
+		if value <= 0 {
+			errs = append(errs, fmt.Errorf("%s deve ser maior que zero", name))
+		}
+	}
+
+	required(c.Server.Port, "server.port", "API_PORT")
+	positive(c.Server.ReadHeaderTimeout, "server.read_header_timeout")
+	positive(c.Server.ReadTimeout, "server.read_timeout")
+	positive(c.Server.WriteTimeout, "server.write_timeout")
+	positive(c.Server.IdleTimeout, "server.idle_timeout")
+	positive(c.Server.ShutdownTimeout, "server.shutdown_timeout")
+	positive(c.Server.RequestTimeout, "server.request_timeout")
+	for route, timeout := range c.Server.RouteTimeouts {
+		positive(timeout, "server.route_timeouts."+route)
+		if timeout >= c.Server.WriteTimeout {
+			errs = append(errs, fmt.Errorf("server.route_timeouts.%s deve ser menor que server.write_timeout", route))
+		}
+	}
+	if c.Server.RequestTimeout >= c.Server.WriteTimeout {
+		errs = append(errs, errors.New("server.request_timeout deve ser menor que server.write_timeout"))
+	}
+
+	required(c.Database.URL, "database.url", "DATABASE_URL")
+	if c.Database.MaxConns <= 0 {
+		errs = append(errs, errors.New("database.max_conns deve ser maior que zero"))
+	}
+	if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+		errs = append(errs, errors.New("database.min_conns deve estar entre 0 e database.max_conns"))
+	}
+	positive(c.Database.ConnMaxLifetime, "database.conn_max_lifetime")
+	positive(c.Database.ConnMaxIdleTime, "database.conn_max_idle_time")
+	positive(c.Database.HealthCheckPeriod, "database.health_check_period")
+	if c.Database.StatementCacheCapacity < 0 {
+		errs = append(errs, errors.New("database.statement_cache_capacity não pode ser negativo"))
+	}
+	positive(c.Database.ReplicaStickyWindow, "database.replica_sticky_window")
+
+	required(c.Redis.Addr, "redis.addr", "REDIS_ADDR")
+	if c.Redis.DB < 0 {
+		errs = append(errs, errors.New("redis.db não pode ser negativo"))
+	}
+	positive(c.Redis.DialTimeout, "redis.dial_timeout")
+	positive(c.Redis.ReadTimeout, "redis.read_timeout")
+	positive(c.Redis.WriteTimeout, "redis.write_timeout")
+	if c.Redis.BreakerThreshold <= 0 {
+		errs = append(errs, errors.New("redis.breaker_threshold deve ser maior que zero"))
+	}
+	positive(c.Redis.BreakerCooldown, "redis.breaker_cooldown")
+
+	required(c.JWT.Secret, "jwt.secret", "JWT_SECRET_KEY")
+	positive(c.JWT.TTL, "jwt.ttl")
+
+	required(c.Email.SMTPHost, "email.smtp_host", "SMTP_HOST")
+	if c.Email.SMTPPort <= 0 || c.Email.SMTPPort > 65535 {
+		errs = append(errs, errors.New("email.smtp_port deve estar entre 1 e 65535"))
+	}
+	required(c.Email.User, "email.user", "EMAIL_USER")
+	required(c.Email.Password, "email.password", "EMAIL_PASSWORD")
+
+	required(c.Security.APIKey, "security.api_key", "API_KEY_SECRET")
+
+	if c.RateLimit.RequestsPerMinute <= 0 {
+		errs = append(errs, errors.New("rate_limit.requests_per_minute deve ser maior que zero"))
+	}
+	if c.RateLimit.MaxFailures <= 0 {
+		errs = append(errs, errors.New("rate_limit.max_failures deve ser maior que zero"))
+	}
+	positive(c.RateLimit.FailWindow, "rate_limit.fail_window")
+	positive(c.RateLimit.JailDuration, "rate_limit.jail_duration")
+
+	positive(c.Cache.ListTTL, "cache.list_ttl")
+	positive(c.Cache.ItemTTL, "cache.item_ttl")
+	positive(c.Cache.SpeciesTTL, "cache.species_ttl")
+	positive(c.Cache.PlantTTL, "cache.plant_ttl")
+	positive(c.Cache.GardenTTL, "cache.garden_ttl")
+	positive(c.Cache.TaskTTL, "cache.task_ttl")
+	if c.Cache.LocalSize <= 0 {
+		errs = append(errs, errors.New("cache.local_size deve ser maior que zero"))
+	}
+	required(c.Cache.InvalidationChannel, "cache.invalidation_channel", "CACHE_INVALIDATION_CHANNEL")
+
+	positive(c.Tokens.EmailVerificationTTL, "tokens.email_verification_ttl")
+	positive(c.Tokens.PasswordResetTTL, "tokens.password_reset_ttl")
+	positive(c.Tokens.WorkspaceInvitationTTL, "tokens.workspace_invitation_ttl")
+
+	positive(c.Trash.Retention, "trash.retention")
+	positive(c.Trash.PurgeInterval, "trash.purge_interval")
+
+	positive(c.History.Retention, "history.retention")
+	if c.History.Granularity != "week" && c.History.Granularity != "month" {
+		errs = append(errs, errors.New("history.granularity deve ser week ou month"))
+	}
+	positive(c.History.RollupInterval, "history.rollup_interval")
+	if c.History.PartitionsAhead <= 0 {
+		errs = append(errs, errors.New("history.partitions_ahead deve ser maior que zero"))
+	}
+
+	positive(c.CareTasks.Horizon, "care_tasks.horizon")
+	positive(c.CareTasks.Interval, "care_tasks.interval")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("configuração inválida: %w", errors.Join(errs...))
+	}
+	return nil
+}
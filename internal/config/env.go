@@ -0,0 +1,155 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadEnv sobrescreve os valores com as variáveis de ambiente definidas.
+func (c *Config) loadEnv() error {
+	var errs []error
+
+	envString("API_PORT", &c.Server.Port)
+	envDuration("SERVER_READ_HEADER_TIMEOUT", &c.Server.ReadHeaderTimeout, &errs)
+	envDuration("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout, &errs)
+	envDuration("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout, &errs)
+	envDuration("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout, &errs)
+	envDuration("SERVER_SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout, &errs)
+	envDuration("SERVER_REQUEST_TIMEOUT", &c.Server.RequestTimeout, &errs)
+	envDurationMap("SERVER_ROUTE_TIMEOUTS", &c.Server.RouteTimeouts, &errs)
+
+	envString("DATABASE_URL", &c.Database.URL)
+	envInt("DATABASE_MAX_CONNS", &c.Database.MaxConns, &errs)
+	envInt("DATABASE_MIN_CONNS", &c.Database.MinConns, &errs)
+	envDuration("DATABASE_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime, &errs)
+	envDuration("DATABASE_CONN_MAX_IDLE_TIME", &c.Database.ConnMaxIdleTime, &errs)
+	envDuration("DATABASE_HEALTH_CHECK_PERIOD", &c.Database.HealthCheckPeriod, &errs)
+	envInt("DATABASE_STATEMENT_CACHE_CAPACITY", &c.Database.StatementCacheCapacity, &errs)
+	envString("DATABASE_REPLICA_URL", &c.Database.ReplicaURL)
+	envDuration("DATABASE_REPLICA_STICKY_WINDOW", &c.Database.ReplicaStickyWindow, &errs)
+	envBool("DATABASE_MIGRATE_ON_START", &c.Database.MigrateOnStart, &errs)
+
+	envString("REDIS_ADDR", &c.Redis.Addr)
+	envString("REDIS_PASSWORD", &c.Redis.Password)
+	envInt("REDIS_DB", &c.Redis.DB, &errs)
+	envDuration("REDIS_DIAL_TIMEOUT", &c.Redis.DialTimeout, &errs)
+	envDuration("REDIS_READ_TIMEOUT", &c.Redis.ReadTimeout, &errs)
+	envDuration("REDIS_WRITE_TIMEOUT", &c.Redis.WriteTimeout, &errs)
+	envInt("REDIS_BREAKER_THRESHOLD", &c.Redis.BreakerThreshold, &errs)
+	envDuration("REDIS_BREAKER_COOLDOWN", &c.Redis.BreakerCooldown, &errs)
+
+	envString("JWT_SECRET_KEY", &c.JWT.Secret)
+	envDuration("JWT_TTL", &c.JWT.TTL, &errs)
+
+	envString("SMTP_HOST", &c.Email.SMTPHost)
+	envInt("SMTP_PORT", &c.Email.SMTPPort, &errs)
+	envString("EMAIL_USER", &c.Email.User)
+	envString("EMAIL_PASSWORD", &c.Email.Password)
+
+	envString("API_KEY_SECRET", &c.Security.APIKey)
+	envString("ADMIN_API_KEY", &c.Security.AdminKey)
+
+	envInt("RATE_LIMIT_PER_MINUTE", &c.RateLimit.RequestsPerMinute, &errs)
+	envInt("RATE_LIMIT_MAX_FAILURES", &c.RateLimit.MaxFailures, &errs)
+	envDuration("RATE_LIMIT_FAIL_WINDOW", &c.RateLimit.FailWindow, &errs)
+	envDuration("RATE_LIMIT_JAIL_DURATION", &c.RateLimit.JailDuration, &errs)
+
+	envDuration("CACHE_LIST_TTL", &c.Cache.ListTTL, &errs)
+	envDuration("CACHE_ITEM_TTL", &c.Cache.ItemTTL, &errs)
+	envDuration("CACHE_SPECIES_TTL", &c.Cache.SpeciesTTL, &errs)
+	envDuration("CACHE_PLANT_TTL", &c.Cache.PlantTTL, &errs)
+	envDuration("CACHE_GARDEN_TTL", &c.Cache.GardenTTL, &errs)
+	envDuration("CACHE_TASK_TTL", &c.Cache.TaskTTL, &errs)
+	envInt("CACHE_LOCAL_SIZE", &c.Cache.LocalSize, &errs)
+	envString("CACHE_INVALIDATION_CHANNEL", &c.Cache.InvalidationChannel)
+
+	envDuration("EMAIL_VERIFICATION_TTL", &c.Tokens.EmailVerificationTTL, &errs)
+	envDuration("PASSWORD_RESET_TTL", &c.Tokens.PasswordResetTTL, &errs)
+	envDuration("WORKSPACE_INVITATION_TTL", &c.Tokens.WorkspaceInvitationTTL, &errs)
+
+	envDuration("TRASH_RETENTION", &c.Trash.Retention, &errs)
+	envDuration("TRASH_PURGE_INTERVAL", &c.Trash.PurgeInterval, &errs)
+
+	envDuration("HISTORY_RETENTION", &c.History.Retention, &errs)
+	envString("HISTORY_GRANULARITY", &c.History.Granularity)
+	envDuration("HISTORY_ROLLUP_INTERVAL", &c.History.RollupInterval, &errs)
+	envInt("HISTORY_PARTITIONS_AHEAD", &c.History.PartitionsAhead, &errs)
+
+	envBool("CARE_TASKS_ENABLED", &c.CareTasks.Enabled, &errs)
+	envDuration("CARE_TASKS_HORIZON", &c.CareTasks.Horizon, &errs)
+	envDuration("CARE_TASKS_INTERVAL", &c.CareTasks.Interval, &errs)
+
+	return errors.Join(errs...)
+}
+
+func envString(key string, target *string) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		*target = value
+	}
+}
+
+func envInt(key string, target *int, errs *[]error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s deve ser um número inteiro: %q", key, value))
+		return
+	}
+	*target = parsed
+}
+
+func envBool(key string, target *bool, errs *[]error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s deve ser true ou false: %q", key, value))
+		return
+	}
+	*target = parsed
+}
+
+func envDuration(key string, target *time.Duration, errs *[]error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s deve ser uma duração válida (ex: 10m, 72h): %q", key, value))
+		return
+	}
+	*target = parsed
+}
+
+// envDurationMap lê pares no formato "rota=duração" separados por vírgula (ex: search=15s,plant=5s)
+// e os soma aos já configurados.
+func envDurationMap(key string, target *map[string]time.Duration, errs *[]error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	parsed := make(map[string]time.Duration, len(*target))
+	for name, duration := range *target {
+		parsed[name] = duration
+	}
+	for _, pair := range strings.Split(value, ",") {
+		name, raw, found := strings.Cut(strings.TrimSpace(pair), "=")
+		duration, err := time.ParseDuration(strings.TrimSpace(raw))
+		if !found || strings.TrimSpace(name) == "" || err != nil {
+			*errs = append(*errs, fmt.Errorf("%s deve ter o formato rota=duração (ex: search=15s): %q", key, pair))
+			return
+		}
+		parsed[strings.TrimSpace(name)] = duration
+	}
+	*target = parsed
+}
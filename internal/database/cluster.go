@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Cluster reúne o primário, que recebe as escritas, e a réplica opcional usada nas leituras.
+type Cluster struct {
+	Primary *pgxpool.Pool
+	// nil quando nenhuma réplica foi configurada
+	Replica *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+func NewCluster(primary, replica *pgxpool.Pool) *Cluster {
+	c := &Cluster{Primary: primary, Replica: replica}
+	c.healthy.Store(replica != nil)
+	return c
+}
+
+// ReplicaHealthy informa se as leituras estão indo para a réplica.
+func (c *Cluster) ReplicaHealthy() bool {
+	return c.Replica != nil && c.healthy.Load()
+}
+
+// Reader escolhe o pool de leitura: a réplica, a não ser que ela esteja fora ou que a
+// requisição precise enxergar as próprias escritas.
+func (c *Cluster) Reader(ctx context.Context) *pgxpool.Pool {
+	if !c.ReplicaHealthy() || prefersPrimary(ctx) {
+		return c.Primary
+	}
+	return c.Replica
+}
+
+// Monitor verifica a réplica a cada period até ctx terminar; enquanto ela não responde
+// as leituras voltam para o primário.
+func (c *Cluster) Monitor(ctx context.Context, period time.Duration) {
+	if c.Replica == nil {
+		return
+	}
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, period)
+		err := c.Replica.Ping(pingCtx)
+		cancel()
+
+		healthy := err == nil
+		if c.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Println("Réplica de leitura disponível novamente")
+			} else {
+				log.Printf("Réplica de leitura indisponível, lendo do primário: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Cluster) Close() {
+	if c.Replica != nil {
+		c.Replica.Close()
+	}
+	c.Primary.Close()
+}
+
+type routingKey struct{}
+
+// routing acompanha uma requisição: se ela já escreveu, ou escreveu pouco antes,
+// as leituras seguintes ficam no primário para não esbarrar no atraso da réplica.
+type routing struct {
+	primary atomic.Bool
+	once    sync.Once
+	onWrite func()
+}
+
+// WithRouting prepara o ctx da requisição. primary manda todas as leituras ao primário
+// desde o início e onWrite é chamado uma vez, na primeira escrita.
+func WithRouting(ctx context.Context, primary bool, onWrite func()) context.Context {
+	state := &routing{onWrite: onWrite}
+	state.primary.Store(primary)
+	return context.WithValue(ctx, routingKey{}, state)
+}
+
+// MarkWrite registra que a requisição do ctx escreveu no primário.
+func MarkWrite(ctx context.Context) {
+	state, _ := ctx.Value(routingKey{}).(*routing)
+	if state == nil {
+		return
+	}
+	state.primary.Store(true)
+	if state.onWrite != nil {
+		state.once.Do(state.onWrite)
+	}
+}
+
+func prefersPrimary(ctx context.Context) bool {
+	state, _ := ctx.Value(routingKey{}).(*routing)
+	return state != nil && state.primary.Load()
+}
@@ -2,25 +2,43 @@ package database
 
 import (
 	"context"
-	"database/sql"
+	"fmt"
 	"log"
-	"os"
-	"time"
 
 	"github.com/go-redis/redis/v8"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/config"
 )
 
-func InitDB() (*sql.DB, *redis.Client, error) {
-	db, err := ConnectPG()
+func InitDB(cfg *config.Config) (*Cluster, *redis.Client, error) {
+	primary, err := ConnectPG(context.Background(), cfg.Database)
 	if err != nil {
 		return nil, nil, err
 	}
+	if cfg.Database.MigrateOnStart {
+		if err := MigrateUp(context.Background(), primary); err != nil {
+			log.Fatalf("An error occurred while running migrations: %v\n", err)
+		}
+		log.Println("Migrations applied successfully")
+	}
+
+	var replica *pgxpool.Pool
+	if cfg.Database.ReplicaURL != "" {
+		replica, err = ConnectReplica(context.Background(), cfg.Database)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	db := NewCluster(primary, replica)
+	if replica != nil && replica.Ping(context.Background()) != nil {
+		// sobe lendo do primário; o Monitor passa a usar a réplica quando ela responder
+		log.Println("Réplica de leitura indisponível na inicialização, lendo do primário")
+		db.healthy.Store(false)
+	}
 
-	rd, err := InitRedisClient(context.Background())
+	rd, err := InitRedisClient(context.Background(), cfg.Redis)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -29,54 +47,66 @@ func InitDB() (*sql.DB, *redis.Client, error) {
 	return db, rd, nil
 }
 
-func ConnectPG() (*sql.DB, error) {
-
-	postgresURL := os.Getenv("DATABASE_URL")
-	if postgresURL == "" {
-		log.Fatal("POSTGRES_URL is not set")
+func ConnectPG(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := newPoolConfig(cfg.URL, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("database.url inválida: %w", err)
 	}
 
-	db, err := sql.Open("pgx", postgresURL)
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		log.Fatalf("Unable to connect to database: %v\n", err)
 	}
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(time.Hour)
 
-	if err = db.Ping(); err != nil {
+	if err = db.Ping(ctx); err != nil {
 		log.Fatalf("Failed to ping the database: %v\n", err)
 	}
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	return db, nil
+}
+
+// ConnectReplica abre o pool da réplica com os mesmos limites do primário. Ela não roda
+// migrations nem precisa responder agora: o Cluster só lê dela enquanto estiver saudável.
+func ConnectReplica(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := newPoolConfig(cfg.ReplicaURL, cfg)
 	if err != nil {
-		log.Fatalf("Could not create postgres driver: %v\n", err)
+		return nil, fmt.Errorf("database.replica_url inválida: %w", err)
 	}
+	return pgxpool.NewWithConfig(ctx, poolConfig)
+}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://internal/database/migrations",
-		"postgres", driver)
+func newPoolConfig(url string, cfg config.DatabaseConfig) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(url)
 	if err != nil {
-		log.Fatalf("Migration failed: %v\n", err)
+		return nil, err
 	}
-
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		log.Fatalf("An error occurred while running migrations: %v\n", err)
+	poolConfig.MaxConns = int32(cfg.MaxConns)
+	poolConfig.MinConns = int32(cfg.MinConns)
+	poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
+	poolConfig.MaxConnIdleTime = cfg.ConnMaxIdleTime
+	poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	poolConfig.ConnConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+	if cfg.StatementCacheCapacity == 0 {
+		// sem cache (ex.: atrás do PgBouncer em modo transaction) nada pode ficar preparado na conexão
+		poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
 	}
-
-	log.Println("Migrations applied successfully")
-	return db, nil
+	return poolConfig, nil
 }
 
-func InitRedisClient(ctx context.Context) (*redis.Client, error) {
+func InitRedisClient(ctx context.Context, cfg config.RedisConfig) (*redis.Client, error) {
 	client := redis.NewClient(&redis.Options{
-		Addr:     os.Getenv("REDIS_ADDR"),
-		Password: "",
-		DB:       0,
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 	})
-	_, err := client.Ping(ctx).Result()
-	if err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+	client.AddHook(cache.NewBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown))
+
+	// Redis só guarda cache, rate limit e tokens curtos: sem ele a API sobe em modo degradado
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("Failed to connect to Redis, starting in degraded mode: %v", err)
 	}
 
 	return client, nil
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// as migrations vão dentro do binário, então ele roda de qualquer diretório
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationLockKey identifica o advisory lock da migração na subida. É outro número
+// que o do golang-migrate, que trava só durante cada operação.
+const migrationLockKey int64 = 0x626f74616e79 // "botany"
+
+func migrationSource() (source.Driver, error) {
+	return iofs.New(migrationsFS, "migrations")
+}
+
+// NewMigrate prepara o golang-migrate sobre o pool com as migrations embutidas.
+// Quem chama fecha com Close, que devolve a conexão ao pool.
+func NewMigrate(db *pgxpool.Pool) (*migrate.Migrate, error) {
+	src, err := migrationSource()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler migrations embutidas: %w", err)
+	}
+	// o golang-migrate só fala database/sql, então usa uma visão do próprio pool
+	driver, err := postgres.WithInstance(stdlib.OpenDBFromPool(db), &postgres.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar driver de migração: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao preparar migrations: %w", err)
+	}
+	return m, nil
+}
+
+// MigrateUp aplica as migrations pendentes segurando um advisory lock durante todo
+// o processo: com várias instâncias subindo juntas, uma migra e as outras esperam
+// e encontram o banco já na última versão. Lock e migração usam a mesma conexão,
+// então a subida funciona mesmo com database.max_conns 1.
+func MigrateUp(ctx context.Context, db *pgxpool.Pool) error {
+	sqlDB := stdlib.OpenDBFromPool(db)
+	defer sqlDB.Close()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	src, err := migrationSource()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao ler migrations embutidas: %w", err)
+	}
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao criar driver de migração: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return fmt.Errorf("erro ao preparar migrations: %w", err)
+	}
+	// Close devolve a conexão, então roda depois de liberar o lock
+	defer m.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("erro ao obter lock de migração: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			log.Printf("Erro ao liberar lock de migração: %v", err)
+		}
+	}()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
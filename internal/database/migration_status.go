@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LatestMigrationVersion retorna a versão da última migration embutida no binário.
+func LatestMigrationVersion() (uint, error) {
+	src, err := migrationSource()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+// MigrationState lê a versão aplicada e a flag dirty da tabela de controle do golang-migrate.
+func MigrationState(ctx context.Context, db *pgxpool.Pool) (uint, bool, error) {
+	var version int64
+	var dirty bool
+	err := db.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, false, errors.New("nenhuma migration aplicada")
+		}
+		return 0, false, err
+	}
+	return uint(version), dirty, nil
+}
@@ -1,5 +1,5 @@
 CREATE TABLE history_plants (
-    id UUID PRIMARY KEY DEFAULT,
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     plant_id UUID NOT NULL,
     irrigation_week NUMERIC DEFAULT 0 NOT NULL,
     record_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_plants_user_health;
+DROP INDEX IF EXISTS idx_tasks_user_status;
+DROP INDEX IF EXISTS idx_plants_health_status;
+DROP INDEX IF EXISTS idx_tasks_status;
+DROP INDEX IF EXISTS idx_history_gardens_record_date;
+DROP INDEX IF EXISTS idx_history_plants_record_date;
+DROP INDEX IF EXISTS idx_task_categories_category_id;
+DROP INDEX IF EXISTS idx_tasks_user_id;
+DROP INDEX IF EXISTS idx_gardens_user_id;
+DROP INDEX IF EXISTS idx_plants_species_id;
+DROP INDEX IF EXISTS idx_plants_user_id;
@@ -0,0 +1,4 @@
+-- plants.species_id apaga em cascata: espécies que já têm plantas ficam
+DELETE FROM species s
+WHERE s.scientific_name IN ('Zea mays', 'Citrus sinensis')
+  AND NOT EXISTS (SELECT 1 FROM plants p WHERE p.species_id = s.id);
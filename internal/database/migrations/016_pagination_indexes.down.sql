@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_history_gardens_garden_record_id;
+DROP INDEX IF EXISTS idx_history_plants_plant_record_id;
+DROP INDEX IF EXISTS idx_tasks_user_urgency_id;
+DROP INDEX IF EXISTS idx_tasks_user_date_id;
+DROP INDEX IF EXISTS idx_gardens_user_name_id;
+DROP INDEX IF EXISTS idx_gardens_user_created_id;
+DROP INDEX IF EXISTS idx_plants_user_name_id;
+DROP INDEX IF EXISTS idx_plants_user_created_id;
+
+ALTER TABLE history_gardens ALTER COLUMN record_date DROP NOT NULL;
+ALTER TABLE history_gardens ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE history_plants ALTER COLUMN record_date DROP NOT NULL;
+ALTER TABLE history_plants ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE tasks ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE gardens ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE plants ALTER COLUMN created_at DROP NOT NULL;
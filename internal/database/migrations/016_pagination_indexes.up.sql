@@ -0,0 +1,25 @@
+-- colunas usadas na ordenação por keyset não podem ser nulas
+UPDATE plants SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE gardens SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE tasks SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE history_plants SET record_date = created_at WHERE record_date IS NULL;
+UPDATE history_plants SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE history_gardens SET record_date = created_at WHERE record_date IS NULL;
+UPDATE history_gardens SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+
+ALTER TABLE plants ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE gardens ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE tasks ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE history_plants ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE history_plants ALTER COLUMN record_date SET NOT NULL;
+ALTER TABLE history_gardens ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE history_gardens ALTER COLUMN record_date SET NOT NULL;
+
+CREATE INDEX idx_plants_user_created_id ON plants(user_id, created_at, id);
+CREATE INDEX idx_plants_user_name_id ON plants(user_id, plant_name, id);
+CREATE INDEX idx_gardens_user_created_id ON gardens(user_id, created_at, id);
+CREATE INDEX idx_gardens_user_name_id ON gardens(user_id, garden_name, id);
+CREATE INDEX idx_tasks_user_date_id ON tasks(user_id, date_task, id);
+CREATE INDEX idx_tasks_user_urgency_id ON tasks(user_id, urgency_level, id);
+CREATE INDEX idx_history_plants_plant_record_id ON history_plants(plant_id, record_date, id);
+CREATE INDEX idx_history_gardens_garden_record_id ON history_gardens(garden_id, record_date, id);
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_species_scientific_name_trgm;
+DROP INDEX IF EXISTS idx_species_common_name_trgm;
+DROP INDEX IF EXISTS idx_tasks_name_trgm;
+DROP INDEX IF EXISTS idx_gardens_name_trgm;
+DROP INDEX IF EXISTS idx_plants_name_trgm;
+
+ALTER TABLE history_gardens DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE history_plants DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE species DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE gardens DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE plants DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS f_unaccent(text);
@@ -0,0 +1,53 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() é STABLE; o wrapper IMMUTABLE permite usá-lo em colunas geradas e índices
+CREATE OR REPLACE FUNCTION f_unaccent(text)
+RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+ALTER TABLE plants ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('portuguese', f_unaccent(coalesce(plant_name, ''))), 'A') ||
+    setweight(to_tsvector('portuguese', f_unaccent(coalesce(plant_description, ''))), 'B')
+) STORED;
+
+ALTER TABLE gardens ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('portuguese', f_unaccent(coalesce(garden_name, ''))), 'A') ||
+    setweight(to_tsvector('portuguese', f_unaccent(coalesce(garden_location, ''))), 'B') ||
+    setweight(to_tsvector('portuguese', f_unaccent(coalesce(garden_description, ''))), 'C')
+) STORED;
+
+ALTER TABLE tasks ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('portuguese', f_unaccent(coalesce(task_name, ''))), 'A') ||
+    setweight(to_tsvector('portuguese', f_unaccent(coalesce(task_description, ''))), 'B')
+) STORED;
+
+ALTER TABLE species ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('portuguese', f_unaccent(coalesce(common_name, ''))), 'A') ||
+    setweight(to_tsvector('simple', f_unaccent(coalesce(scientific_name, ''))), 'A') ||
+    setweight(to_tsvector('portuguese', f_unaccent(coalesce(specie_description, ''))), 'B') ||
+    setweight(to_tsvector('simple', f_unaccent(coalesce(botanical_family, ''))), 'C')
+) STORED;
+
+ALTER TABLE history_plants ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('portuguese', f_unaccent(coalesce(notes, '')))
+) STORED;
+
+ALTER TABLE history_gardens ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('portuguese', f_unaccent(coalesce(notes, '')))
+) STORED;
+
+CREATE INDEX idx_plants_search ON plants USING GIN (search_vector);
+CREATE INDEX idx_gardens_search ON gardens USING GIN (search_vector);
+CREATE INDEX idx_tasks_search ON tasks USING GIN (search_vector);
+CREATE INDEX idx_species_search ON species USING GIN (search_vector);
+CREATE INDEX idx_history_plants_search ON history_plants USING GIN (search_vector);
+CREATE INDEX idx_history_gardens_search ON history_gardens USING GIN (search_vector);
+
+-- trigramas para busca aproximada e autocomplete pelos nomes
+CREATE INDEX idx_plants_name_trgm ON plants USING GIN (f_unaccent(plant_name) gin_trgm_ops);
+CREATE INDEX idx_gardens_name_trgm ON gardens USING GIN (f_unaccent(garden_name) gin_trgm_ops);
+CREATE INDEX idx_tasks_name_trgm ON tasks USING GIN (f_unaccent(task_name) gin_trgm_ops);
+CREATE INDEX idx_species_common_name_trgm ON species USING GIN (f_unaccent(common_name) gin_trgm_ops);
+CREATE INDEX idx_species_scientific_name_trgm ON species USING GIN (f_unaccent(scientific_name) gin_trgm_ops);
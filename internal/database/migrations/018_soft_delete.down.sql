@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_categories_tasks_user_deleted;
+DROP INDEX IF EXISTS idx_categories_plants_user_deleted;
+DROP INDEX IF EXISTS idx_tasks_user_deleted;
+DROP INDEX IF EXISTS idx_gardens_user_deleted;
+DROP INDEX IF EXISTS idx_plants_user_deleted;
+
+-- sem a coluna o que está na lixeira voltaria a aparecer, então sai de vez
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DELETE FROM gardens WHERE deleted_at IS NOT NULL;
+DELETE FROM plants WHERE deleted_at IS NOT NULL;
+DELETE FROM categories_tasks WHERE deleted_at IS NOT NULL;
+DELETE FROM categories_plants WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE categories_tasks DROP COLUMN deleted_at;
+ALTER TABLE categories_plants DROP COLUMN deleted_at;
+ALTER TABLE tasks DROP COLUMN deleted_at;
+ALTER TABLE gardens DROP COLUMN deleted_at;
+ALTER TABLE plants DROP COLUMN deleted_at;
@@ -0,0 +1,13 @@
+-- exclusão lógica: a linha fica na lixeira até ser restaurada ou expurgada
+ALTER TABLE plants ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE gardens ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE categories_plants ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE categories_tasks ADD COLUMN deleted_at TIMESTAMP;
+
+-- só as linhas na lixeira entram nos índices usados pela listagem e pelo expurgo
+CREATE INDEX idx_plants_user_deleted ON plants(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_gardens_user_deleted ON gardens(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_tasks_user_deleted ON tasks(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_categories_plants_user_deleted ON categories_plants(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_categories_tasks_user_deleted ON categories_tasks(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE tasks DROP COLUMN version;
+ALTER TABLE gardens DROP COLUMN version;
+ALTER TABLE plants DROP COLUMN version;
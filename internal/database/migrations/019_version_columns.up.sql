@@ -0,0 +1,4 @@
+-- controle de concorrência otimista: cada atualização incrementa a versão
+ALTER TABLE plants ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE gardens ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+ALTER TABLE species DROP CONSTRAINT IF EXISTS species_scientific_name_key;
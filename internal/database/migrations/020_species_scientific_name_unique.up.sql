@@ -0,0 +1,2 @@
+-- o nome científico é a chave do catálogo entre ambientes: o import faz upsert por ele
+ALTER TABLE species ADD CONSTRAINT species_scientific_name_key UNIQUE (scientific_name);
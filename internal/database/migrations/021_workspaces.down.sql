@@ -0,0 +1,19 @@
+-- o conteúdo volta a pertencer só ao autor
+DROP INDEX IF EXISTS idx_categories_tasks_workspace;
+DROP INDEX IF EXISTS idx_categories_plants_workspace;
+DROP INDEX IF EXISTS idx_tasks_workspace;
+DROP INDEX IF EXISTS idx_plants_workspace;
+DROP INDEX IF EXISTS idx_gardens_workspace;
+
+ALTER TABLE categories_tasks DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE categories_plants DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE plants DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE gardens DROP COLUMN IF EXISTS workspace_id;
+
+DROP TRIGGER IF EXISTS trigger_create_personal_workspace ON users;
+DROP FUNCTION IF EXISTS create_personal_workspace();
+
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
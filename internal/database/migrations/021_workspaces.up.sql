@@ -0,0 +1,90 @@
+-- workspaces agrupam jardins, plantas, tarefas e categorias de quem cuida junto;
+-- todo usuário tem um pessoal, que não pode ser excluído nem compartilhado
+CREATE TABLE workspaces (
+    id UUID PRIMARY KEY,
+    workspace_name VARCHAR(50) NOT NULL,
+    personal_of UUID UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER trigger_update_timestamp_workspaces
+BEFORE UPDATE ON workspaces
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+CREATE TABLE workspace_members (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    member_role VARCHAR(10) NOT NULL CHECK (member_role IN ('owner', 'editor', 'viewer')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+-- as consultas de autorização partem do usuário
+CREATE INDEX idx_workspace_members_user ON workspace_members(user_id, workspace_id, member_role);
+
+CREATE TABLE workspace_invitations (
+    id UUID PRIMARY KEY,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email VARCHAR(50) NOT NULL,
+    member_role VARCHAR(10) NOT NULL CHECK (member_role IN ('owner', 'editor', 'viewer')),
+    invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- um convite pendente por email em cada workspace; reconvidar substitui o anterior
+CREATE UNIQUE INDEX idx_workspace_invitations_pending
+    ON workspace_invitations(workspace_id, lower(email)) WHERE accepted_at IS NULL;
+CREATE INDEX idx_workspace_invitations_email ON workspace_invitations(lower(email)) WHERE accepted_at IS NULL;
+
+-- novos usuários ganham o workspace pessoal no mesmo insert
+CREATE OR REPLACE FUNCTION create_personal_workspace()
+RETURNS TRIGGER AS $$
+DECLARE
+    workspace UUID := gen_random_uuid();
+BEGIN
+    INSERT INTO workspaces (id, workspace_name, personal_of) VALUES (workspace, 'Pessoal', NEW.id);
+    INSERT INTO workspace_members (workspace_id, user_id, member_role) VALUES (workspace, NEW.id, 'owner');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_create_personal_workspace
+AFTER INSERT ON users
+FOR EACH ROW
+EXECUTE FUNCTION create_personal_workspace();
+
+-- usuários que já existem
+INSERT INTO workspaces (id, workspace_name, personal_of)
+SELECT gen_random_uuid(), 'Pessoal', u.id FROM users u;
+
+INSERT INTO workspace_members (workspace_id, user_id, member_role)
+SELECT w.id, w.personal_of, 'owner' FROM workspaces w;
+
+-- user_id continua como autor; quem pode ver e alterar passa a ser decidido pelo workspace
+ALTER TABLE gardens ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE plants ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE tasks ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE categories_plants ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE categories_tasks ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE gardens x SET workspace_id = w.id FROM workspaces w WHERE w.personal_of = x.user_id;
+UPDATE plants x SET workspace_id = w.id FROM workspaces w WHERE w.personal_of = x.user_id;
+UPDATE tasks x SET workspace_id = w.id FROM workspaces w WHERE w.personal_of = x.user_id;
+UPDATE categories_plants x SET workspace_id = w.id FROM workspaces w WHERE w.personal_of = x.user_id;
+UPDATE categories_tasks x SET workspace_id = w.id FROM workspaces w WHERE w.personal_of = x.user_id;
+
+ALTER TABLE gardens ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE plants ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE tasks ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE categories_plants ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE categories_tasks ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX idx_gardens_workspace ON gardens(workspace_id);
+CREATE INDEX idx_plants_workspace ON plants(workspace_id);
+CREATE INDEX idx_tasks_workspace ON tasks(workspace_id);
+CREATE INDEX idx_categories_plants_workspace ON categories_plants(workspace_id);
+CREATE INDEX idx_categories_tasks_workspace ON categories_tasks(workspace_id);
@@ -0,0 +1,87 @@
+-- volta para tabelas comuns; os resumos não podem ser desfeitos e são descartados
+DROP VIEW IF EXISTS history_gardens_timeline;
+DROP VIEW IF EXISTS history_plants_timeline;
+DROP TABLE IF EXISTS history_gardens_rollups;
+DROP TABLE IF EXISTS history_plants_rollups;
+
+CREATE TABLE history_plants_plain (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    plant_id UUID NOT NULL,
+    irrigation_week NUMERIC DEFAULT 0 NOT NULL,
+    record_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    height NUMERIC(10, 2),
+    width NUMERIC(10, 2),
+    health_status VARCHAR(50) DEFAULT 'Saudável',
+    irrigation BOOLEAN DEFAULT FALSE,
+    fertilization BOOLEAN DEFAULT FALSE,
+    sun_exposure NUMERIC NOT NULL,
+    fertilization_week NUMERIC DEFAULT 0 NOT NULL,
+    notes TEXT,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('portuguese', f_unaccent(coalesce(notes, '')))
+    ) STORED,
+    CONSTRAINT fk_plant_log FOREIGN KEY (plant_id) REFERENCES plants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_log FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO history_plants_plain (
+    id, plant_id, irrigation_week, record_date, height, width, health_status,
+    irrigation, fertilization, sun_exposure, fertilization_week, notes, user_id, created_at
+)
+SELECT id, plant_id, irrigation_week, record_date, height, width, health_status,
+    irrigation, fertilization, sun_exposure, fertilization_week, notes, user_id, created_at
+FROM history_plants;
+
+DROP TABLE history_plants;
+ALTER TABLE history_plants_plain RENAME TO history_plants;
+ALTER INDEX history_plants_plain_pkey RENAME TO history_plants_pkey;
+
+CREATE INDEX idx_record_date_plants ON history_plants(record_date);
+CREATE INDEX idx_history_plants_record_date ON history_plants(record_date);
+CREATE INDEX idx_history_plants_plant_record_id ON history_plants(plant_id, record_date, id);
+CREATE INDEX idx_history_plants_search ON history_plants USING GIN (search_vector);
+
+CREATE TABLE history_gardens_plain (
+    id UUID PRIMARY KEY,
+    garden_id UUID NOT NULL,
+    garden_location VARCHAR(50) NOT NULL,
+    total_area NUMERIC(10, 3) NOT NULL,
+    record_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    height NUMERIC(10, 2),
+    width NUMERIC(10, 2),
+    health_status VARCHAR(50) DEFAULT 'Saudável',
+    irrigation BOOLEAN DEFAULT FALSE,
+    fertilization BOOLEAN DEFAULT FALSE,
+    irrigation_week NUMERIC DEFAULT 0 NOT NULL,
+    sun_exposure NUMERIC NOT NULL,
+    fertilization_week NUMERIC DEFAULT 0 NOT NULL,
+    notes TEXT,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('portuguese', f_unaccent(coalesce(notes, '')))
+    ) STORED,
+    CONSTRAINT fk_garden_log FOREIGN KEY (garden_id) REFERENCES gardens(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_log FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO history_gardens_plain (
+    id, garden_id, garden_location, total_area, record_date, height, width, health_status,
+    irrigation, fertilization, irrigation_week, sun_exposure, fertilization_week, notes, user_id, created_at
+)
+SELECT id, garden_id, garden_location, total_area, record_date, height, width, health_status,
+    irrigation, fertilization, irrigation_week, sun_exposure, fertilization_week, notes, user_id, created_at
+FROM history_gardens;
+
+DROP TABLE history_gardens;
+ALTER TABLE history_gardens_plain RENAME TO history_gardens;
+ALTER INDEX history_gardens_plain_pkey RENAME TO history_gardens_pkey;
+
+CREATE INDEX idx_record_date_gardens ON history_gardens(record_date);
+CREATE INDEX idx_history_gardens_record_date ON history_gardens(record_date);
+CREATE INDEX idx_history_gardens_garden_record_id ON history_gardens(garden_id, record_date, id);
+CREATE INDEX idx_history_gardens_search ON history_gardens USING GIN (search_vector);
+
+DROP FUNCTION IF EXISTS ensure_history_partition(TEXT, DATE);
@@ -0,0 +1,211 @@
+-- cria a partição mensal de parent que contém month_start. Se a partição default já
+-- tiver linhas desse mês a criação falharia; nesse caso as linhas continuam na default.
+CREATE OR REPLACE FUNCTION ensure_history_partition(parent TEXT, month_start DATE)
+RETURNS BOOLEAN AS $$
+DECLARE
+    first_day DATE := date_trunc('month', month_start)::date;
+    partition_name TEXT := parent || '_p' || to_char(first_day, 'YYYYMM');
+BEGIN
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN FALSE;
+    END IF;
+    EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+        partition_name, parent, first_day, (first_day + INTERVAL '1 month')::date);
+    RETURN TRUE;
+EXCEPTION WHEN check_violation THEN
+    RAISE NOTICE 'partição % não criada: a default já tem registros desse mês', partition_name;
+    RETURN FALSE;
+END;
+$$ LANGUAGE plpgsql;
+
+-- history_plants passa a ser particionada por mês de record_date
+ALTER TABLE history_plants RENAME TO history_plants_old;
+ALTER INDEX history_plants_pkey RENAME TO history_plants_old_pkey;
+
+CREATE TABLE history_plants (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    plant_id UUID NOT NULL,
+    irrigation_week NUMERIC DEFAULT 0 NOT NULL,
+    record_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    height NUMERIC(10, 2),
+    width NUMERIC(10, 2),
+    health_status VARCHAR(50) DEFAULT 'Saudável',
+    irrigation BOOLEAN DEFAULT FALSE,
+    fertilization BOOLEAN DEFAULT FALSE,
+    sun_exposure NUMERIC NOT NULL,
+    fertilization_week NUMERIC DEFAULT 0 NOT NULL,
+    notes TEXT,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('portuguese', f_unaccent(coalesce(notes, '')))
+    ) STORED,
+    PRIMARY KEY (id, record_date),
+    CONSTRAINT fk_plant_log FOREIGN KEY (plant_id) REFERENCES plants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_log FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) PARTITION BY RANGE (record_date);
+
+SELECT ensure_history_partition('history_plants', m::date)
+FROM generate_series(
+    date_trunc('month', LEAST(COALESCE((SELECT MIN(record_date) FROM history_plants_old), LOCALTIMESTAMP), LOCALTIMESTAMP)),
+    date_trunc('month', LOCALTIMESTAMP) + INTERVAL '3 months',
+    INTERVAL '1 month'
+) AS m;
+CREATE TABLE history_plants_default PARTITION OF history_plants DEFAULT;
+
+INSERT INTO history_plants (
+    id, plant_id, irrigation_week, record_date, height, width, health_status,
+    irrigation, fertilization, sun_exposure, fertilization_week, notes, user_id, created_at
+)
+SELECT id, plant_id, irrigation_week, record_date, height, width, health_status,
+    irrigation, fertilization, sun_exposure, fertilization_week, notes, user_id, created_at
+FROM history_plants_old;
+
+DROP TABLE history_plants_old;
+
+CREATE INDEX idx_history_plants_record_date ON history_plants(record_date);
+CREATE INDEX idx_history_plants_plant_record_id ON history_plants(plant_id, record_date, id);
+CREATE INDEX idx_history_plants_search ON history_plants USING GIN (search_vector);
+
+-- history_gardens, mesma coisa
+ALTER TABLE history_gardens RENAME TO history_gardens_old;
+ALTER INDEX history_gardens_pkey RENAME TO history_gardens_old_pkey;
+
+CREATE TABLE history_gardens (
+    id UUID NOT NULL,
+    garden_id UUID NOT NULL,
+    garden_location VARCHAR(50) NOT NULL,
+    total_area NUMERIC(10, 3) NOT NULL,
+    record_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    height NUMERIC(10, 2),
+    width NUMERIC(10, 2),
+    health_status VARCHAR(50) DEFAULT 'Saudável',
+    irrigation BOOLEAN DEFAULT FALSE,
+    fertilization BOOLEAN DEFAULT FALSE,
+    irrigation_week NUMERIC DEFAULT 0 NOT NULL,
+    sun_exposure NUMERIC NOT NULL,
+    fertilization_week NUMERIC DEFAULT 0 NOT NULL,
+    notes TEXT,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('portuguese', f_unaccent(coalesce(notes, '')))
+    ) STORED,
+    PRIMARY KEY (id, record_date),
+    CONSTRAINT fk_garden_log FOREIGN KEY (garden_id) REFERENCES gardens(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_log FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) PARTITION BY RANGE (record_date);
+
+SELECT ensure_history_partition('history_gardens', m::date)
+FROM generate_series(
+    date_trunc('month', LEAST(COALESCE((SELECT MIN(record_date) FROM history_gardens_old), LOCALTIMESTAMP), LOCALTIMESTAMP)),
+    date_trunc('month', LOCALTIMESTAMP) + INTERVAL '3 months',
+    INTERVAL '1 month'
+) AS m;
+CREATE TABLE history_gardens_default PARTITION OF history_gardens DEFAULT;
+
+INSERT INTO history_gardens (
+    id, garden_id, garden_location, total_area, record_date, height, width, health_status,
+    irrigation, fertilization, irrigation_week, sun_exposure, fertilization_week, notes, user_id, created_at
+)
+SELECT id, garden_id, garden_location, total_area, record_date, height, width, health_status,
+    irrigation, fertilization, irrigation_week, sun_exposure, fertilization_week, notes, user_id, created_at
+FROM history_gardens_old;
+
+DROP TABLE history_gardens_old;
+
+CREATE INDEX idx_history_gardens_record_date ON history_gardens(record_date);
+CREATE INDEX idx_history_gardens_garden_record_id ON history_gardens(garden_id, record_date, id);
+CREATE INDEX idx_history_gardens_search ON history_gardens USING GIN (search_vector);
+
+-- resumos semanais ou mensais do histórico que saiu da retenção
+CREATE TABLE history_plants_rollups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    plant_id UUID NOT NULL REFERENCES plants(id) ON DELETE CASCADE,
+    granularity VARCHAR(10) NOT NULL CHECK (granularity IN ('week', 'month')),
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    samples INTEGER NOT NULL,
+    min_height NUMERIC(10, 2),
+    max_height NUMERIC(10, 2),
+    avg_height NUMERIC(10, 2),
+    min_width NUMERIC(10, 2),
+    max_width NUMERIC(10, 2),
+    avg_width NUMERIC(10, 2),
+    irrigation_count INTEGER NOT NULL DEFAULT 0,
+    fertilization_count INTEGER NOT NULL DEFAULT 0,
+    -- o último estado registrado no período
+    health_status VARCHAR(50),
+    irrigation_week NUMERIC NOT NULL DEFAULT 0,
+    sun_exposure NUMERIC NOT NULL DEFAULT 0,
+    fertilization_week NUMERIC NOT NULL DEFAULT 0,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    rolled_up_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (plant_id, granularity, period_start)
+);
+
+CREATE INDEX idx_history_plants_rollups_plant_period_id ON history_plants_rollups(plant_id, period_start, id);
+
+CREATE TABLE history_gardens_rollups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    garden_id UUID NOT NULL REFERENCES gardens(id) ON DELETE CASCADE,
+    granularity VARCHAR(10) NOT NULL CHECK (granularity IN ('week', 'month')),
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    samples INTEGER NOT NULL,
+    garden_location VARCHAR(50) NOT NULL,
+    total_area NUMERIC(10, 3) NOT NULL,
+    min_height NUMERIC(10, 2),
+    max_height NUMERIC(10, 2),
+    avg_height NUMERIC(10, 2),
+    min_width NUMERIC(10, 2),
+    max_width NUMERIC(10, 2),
+    avg_width NUMERIC(10, 2),
+    irrigation_count INTEGER NOT NULL DEFAULT 0,
+    fertilization_count INTEGER NOT NULL DEFAULT 0,
+    health_status VARCHAR(50),
+    irrigation_week NUMERIC NOT NULL DEFAULT 0,
+    sun_exposure NUMERIC NOT NULL DEFAULT 0,
+    fertilization_week NUMERIC NOT NULL DEFAULT 0,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    rolled_up_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (garden_id, granularity, period_start)
+);
+
+CREATE INDEX idx_history_gardens_rollups_garden_period_id ON history_gardens_rollups(garden_id, period_start, id);
+
+-- leitura única do histórico: registros brutos e resumos na mesma linha do tempo.
+-- Nos resumos record_date é o início do período e height/width são as médias.
+CREATE VIEW history_plants_timeline AS
+SELECT id, plant_id, irrigation_week, record_date, height, width, health_status,
+    irrigation, fertilization, sun_exposure, fertilization_week, notes, user_id, created_at,
+    'raw'::VARCHAR(10) AS granularity, NULL::TIMESTAMP AS period_end, NULL::INTEGER AS samples,
+    NULL::NUMERIC(10, 2) AS min_height, NULL::NUMERIC(10, 2) AS max_height,
+    NULL::NUMERIC(10, 2) AS min_width, NULL::NUMERIC(10, 2) AS max_width,
+    NULL::INTEGER AS irrigation_count, NULL::INTEGER AS fertilization_count
+FROM history_plants
+UNION ALL
+SELECT id, plant_id, round(irrigation_week), period_start, avg_height, avg_width, health_status,
+    irrigation_count > 0, fertilization_count > 0, sun_exposure, fertilization_week, '', user_id, created_at,
+    granularity, period_end, samples,
+    min_height, max_height, min_width, max_width,
+    irrigation_count, fertilization_count
+FROM history_plants_rollups;
+
+CREATE VIEW history_gardens_timeline AS
+SELECT id, garden_id, garden_location, total_area, record_date, height, width, health_status,
+    irrigation, fertilization, irrigation_week, sun_exposure, fertilization_week, notes, user_id, created_at,
+    'raw'::VARCHAR(10) AS granularity, NULL::TIMESTAMP AS period_end, NULL::INTEGER AS samples,
+    NULL::NUMERIC(10, 2) AS min_height, NULL::NUMERIC(10, 2) AS max_height,
+    NULL::NUMERIC(10, 2) AS min_width, NULL::NUMERIC(10, 2) AS max_width,
+    NULL::INTEGER AS irrigation_count, NULL::INTEGER AS fertilization_count
+FROM history_gardens
+UNION ALL
+SELECT id, garden_id, garden_location, total_area, period_start, avg_height, avg_width, health_status,
+    irrigation_count > 0, fertilization_count > 0, round(irrigation_week), sun_exposure, fertilization_week, '', user_id, created_at,
+    granularity, period_end, samples,
+    min_height, max_height, min_width, max_width,
+    irrigation_count, fertilization_count
+FROM history_gardens_rollups;
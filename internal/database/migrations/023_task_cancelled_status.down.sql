@@ -0,0 +1,11 @@
+-- o Postgres não remove valores de enum; o tipo é recriado sem 'cancelled'
+UPDATE tasks SET task_status = 'completed' WHERE task_status = 'cancelled';
+
+ALTER TYPE task_status_enum RENAME TO task_status_enum_old;
+CREATE TYPE task_status_enum AS ENUM ('pending', 'in_progress', 'completed');
+
+ALTER TABLE tasks ALTER COLUMN task_status DROP DEFAULT;
+ALTER TABLE tasks ALTER COLUMN task_status TYPE task_status_enum USING task_status::text::task_status_enum;
+ALTER TABLE tasks ALTER COLUMN task_status SET DEFAULT 'pending';
+
+DROP TYPE task_status_enum_old;
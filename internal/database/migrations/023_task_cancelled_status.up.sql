@@ -0,0 +1,2 @@
+-- fica numa migration própria: o valor novo só pode ser usado depois do commit
+ALTER TYPE task_status_enum ADD VALUE IF NOT EXISTS 'cancelled';
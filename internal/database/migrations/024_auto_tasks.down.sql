@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_tasks_care_pending;
+DROP INDEX IF EXISTS idx_tasks_care_window;
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_tasks_care;
+ALTER TABLE tasks DROP COLUMN IF EXISTS care_window;
+ALTER TABLE tasks DROP COLUMN IF EXISTS care_type;
+ALTER TABLE tasks DROP COLUMN IF EXISTS care_subject_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS care_subject_type;
+
+ALTER TABLE gardens DROP COLUMN IF EXISTS auto_tasks;
+ALTER TABLE plants DROP COLUMN IF EXISTS auto_tasks;
@@ -0,0 +1,19 @@
+-- cada planta e jardim pode desligar a geração automática de tarefas de cuidado
+ALTER TABLE plants ADD COLUMN auto_tasks BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE gardens ADD COLUMN auto_tasks BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- origem das tarefas geradas; ficam nulas nas criadas pelo usuário
+ALTER TABLE tasks ADD COLUMN care_subject_type VARCHAR(10) CHECK (care_subject_type IN ('plant', 'garden'));
+ALTER TABLE tasks ADD COLUMN care_subject_id UUID;
+ALTER TABLE tasks ADD COLUMN care_type VARCHAR(20) CHECK (care_type IN ('irrigation', 'fertilization'));
+ALTER TABLE tasks ADD COLUMN care_window DATE;
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_care CHECK (
+    (care_subject_type IS NULL AND care_subject_id IS NULL AND care_type IS NULL AND care_window IS NULL)
+    OR (care_subject_type IS NOT NULL AND care_subject_id IS NOT NULL AND care_type IS NOT NULL AND care_window IS NOT NULL)
+);
+
+-- no máximo uma tarefa ativa por cuidado e dia previsto; canceladas não contam
+CREATE UNIQUE INDEX idx_tasks_care_window ON tasks(care_subject_id, care_type, care_window)
+    WHERE care_type IS NOT NULL AND task_status <> 'cancelled';
+CREATE INDEX idx_tasks_care_pending ON tasks(care_subject_id)
+    WHERE care_type IS NOT NULL AND task_status = 'pending';
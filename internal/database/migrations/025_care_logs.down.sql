@@ -0,0 +1,40 @@
+-- uma view não perde colunas com CREATE OR REPLACE; recria as de 022
+DROP VIEW IF EXISTS history_gardens_timeline;
+DROP VIEW IF EXISTS history_plants_timeline;
+
+ALTER TABLE history_gardens DROP COLUMN IF EXISTS amount;
+ALTER TABLE history_gardens DROP COLUMN IF EXISTS care_type;
+ALTER TABLE history_plants DROP COLUMN IF EXISTS amount;
+ALTER TABLE history_plants DROP COLUMN IF EXISTS care_type;
+
+CREATE VIEW history_plants_timeline AS
+SELECT id, plant_id, irrigation_week, record_date, height, width, health_status,
+    irrigation, fertilization, sun_exposure, fertilization_week, notes, user_id, created_at,
+    'raw'::VARCHAR(10) AS granularity, NULL::TIMESTAMP AS period_end, NULL::INTEGER AS samples,
+    NULL::NUMERIC(10, 2) AS min_height, NULL::NUMERIC(10, 2) AS max_height,
+    NULL::NUMERIC(10, 2) AS min_width, NULL::NUMERIC(10, 2) AS max_width,
+    NULL::INTEGER AS irrigation_count, NULL::INTEGER AS fertilization_count
+FROM history_plants
+UNION ALL
+SELECT id, plant_id, round(irrigation_week), period_start, avg_height, avg_width, health_status,
+    irrigation_count > 0, fertilization_count > 0, sun_exposure, fertilization_week, '', user_id, created_at,
+    granularity, period_end, samples,
+    min_height, max_height, min_width, max_width,
+    irrigation_count, fertilization_count
+FROM history_plants_rollups;
+
+CREATE VIEW history_gardens_timeline AS
+SELECT id, garden_id, garden_location, total_area, record_date, height, width, health_status,
+    irrigation, fertilization, irrigation_week, sun_exposure, fertilization_week, notes, user_id, created_at,
+    'raw'::VARCHAR(10) AS granularity, NULL::TIMESTAMP AS period_end, NULL::INTEGER AS samples,
+    NULL::NUMERIC(10, 2) AS min_height, NULL::NUMERIC(10, 2) AS max_height,
+    NULL::NUMERIC(10, 2) AS min_width, NULL::NUMERIC(10, 2) AS max_width,
+    NULL::INTEGER AS irrigation_count, NULL::INTEGER AS fertilization_count
+FROM history_gardens
+UNION ALL
+SELECT id, garden_id, garden_location, total_area, period_start, avg_height, avg_width, health_status,
+    irrigation_count > 0, fertilization_count > 0, round(irrigation_week), sun_exposure, fertilization_week, '', user_id, created_at,
+    granularity, period_end, samples,
+    min_height, max_height, min_width, max_width,
+    irrigation_count, fertilization_count
+FROM history_gardens_rollups;
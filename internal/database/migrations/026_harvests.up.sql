@@ -0,0 +1,20 @@
+-- colheitas ficam no workspace da planta; estimated_harvest_date é a previsão da
+-- planta no momento do registro, usada para medir a precisão das estimativas
+CREATE TABLE harvests (
+    id UUID PRIMARY KEY,
+    plant_id UUID NOT NULL REFERENCES plants(id) ON DELETE CASCADE,
+    garden_id UUID REFERENCES gardens(id) ON DELETE SET NULL,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    harvest_date TIMESTAMP NOT NULL,
+    quantity NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    unit VARCHAR(10) NOT NULL CHECK (unit IN ('g', 'kg', 'ml', 'l', 'unit')),
+    quality_grade CHAR(1) CHECK (quality_grade IN ('A', 'B', 'C')),
+    notes TEXT NOT NULL DEFAULT '',
+    estimated_harvest_date TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_harvests_plant_date ON harvests(plant_id, harvest_date);
+CREATE INDEX idx_harvests_garden ON harvests(garden_id) WHERE garden_id IS NOT NULL;
+CREATE INDEX idx_harvests_workspace_date ON harvests(workspace_id, harvest_date);
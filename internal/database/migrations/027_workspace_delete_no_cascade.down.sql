@@ -0,0 +1,12 @@
+ALTER TABLE gardens DROP CONSTRAINT gardens_workspace_id_fkey,
+    ADD CONSTRAINT gardens_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE plants DROP CONSTRAINT plants_workspace_id_fkey,
+    ADD CONSTRAINT plants_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE tasks DROP CONSTRAINT tasks_workspace_id_fkey,
+    ADD CONSTRAINT tasks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE categories_plants DROP CONSTRAINT categories_plants_workspace_id_fkey,
+    ADD CONSTRAINT categories_plants_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE categories_tasks DROP CONSTRAINT categories_tasks_workspace_id_fkey,
+    ADD CONSTRAINT categories_tasks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE harvests DROP CONSTRAINT harvests_workspace_id_fkey,
+    ADD CONSTRAINT harvests_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
@@ -0,0 +1,15 @@
+-- excluir um workspace não apaga mais o conteúdo em cascata: o repositório devolve
+-- cada item para a lixeira do autor antes. NO ACTION é conferido no fim do comando,
+-- então excluir a conta ainda leva junto o workspace pessoal e o que o usuário criou
+ALTER TABLE gardens DROP CONSTRAINT gardens_workspace_id_fkey,
+    ADD CONSTRAINT gardens_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
+ALTER TABLE plants DROP CONSTRAINT plants_workspace_id_fkey,
+    ADD CONSTRAINT plants_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
+ALTER TABLE tasks DROP CONSTRAINT tasks_workspace_id_fkey,
+    ADD CONSTRAINT tasks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
+ALTER TABLE categories_plants DROP CONSTRAINT categories_plants_workspace_id_fkey,
+    ADD CONSTRAINT categories_plants_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
+ALTER TABLE categories_tasks DROP CONSTRAINT categories_tasks_workspace_id_fkey,
+    ADD CONSTRAINT categories_tasks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
+ALTER TABLE harvests DROP CONSTRAINT harvests_workspace_id_fkey,
+    ADD CONSTRAINT harvests_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
@@ -0,0 +1,301 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CareTypeIrrigation    = "irrigation"
+	CareTypeFertilization = "fertilization"
+	CareTypePruning       = "pruning"
+	CareTypeMeasurement   = "measurement"
+
+	CareSubjectPlant  = "plant"
+	CareSubjectGarden = "garden"
+
+	// NeutralCareWeight é o peso da espécie que não muda a frequência informada.
+	// Jardins não têm espécie e usam sempre esse peso.
+	NeutralCareWeight = 0.5
+)
+
+const careDay = 24 * time.Hour
+
+const (
+	// MaxCareLogNotes é o tamanho máximo das observações de um cuidado registrado.
+	MaxCareLogNotes = 500
+	// MaxCareLogBatch é quantos itens um mesmo registro de cuidado pode alcançar.
+	MaxCareLogBatch = 100
+)
+
+var (
+	ErrInvalidCareType     = errors.New("tipo de cuidado inválido: use irrigation, fertilization, pruning ou measurement")
+	ErrMissingMeasurement  = errors.New("informe a altura ou a largura medida")
+	ErrInvalidMeasurement  = errors.New("altura e largura não podem ser negativas")
+	ErrInvalidCareAmount   = errors.New("a quantidade não pode ser negativa")
+	ErrCareInFuture        = errors.New("a data do cuidado não pode estar no futuro")
+	ErrCareNotesTooLong    = errors.New("as observações devem ter até 500 caracteres")
+	ErrCareSubjectNotFound = errors.New("planta ou jardim não encontrado")
+	ErrMissingCareIds      = errors.New("informe ao menos um id")
+	ErrTooManyCareIds      = errors.New("um registro de cuidado alcança no máximo 100 itens")
+	ErrInvalidCareId       = errors.New("id inválido")
+)
+
+// CareLog é um cuidado feito em uma ou mais plantas ou jardins. Amount é a
+// quantidade aplicada (água ou adubo) e Height/Width são as medidas depois do
+// cuidado; os campos nil não mudam o registro.
+type CareLog struct {
+	CareType    string
+	PerformedAt time.Time
+	Amount      *float64
+	Height      *float64
+	Width       *float64
+	Notes       string
+	UserId      string
+}
+
+// NewCareLog valida o cuidado. Sem performedAt, o cuidado é registrado agora. Medidas
+// só valem para poda e medição, e a medição precisa de ao menos uma delas.
+func NewCareLog(careType, userId, notes string, performedAt *time.Time, amount, height, width *float64) (*CareLog, error) {
+	now := time.Now()
+	entry := &CareLog{
+		CareType:    careType,
+		PerformedAt: now,
+		Amount:      amount,
+		Notes:       notes,
+		UserId:      userId,
+	}
+	if performedAt != nil && !performedAt.IsZero() {
+		if performedAt.After(now.Add(time.Minute)) {
+			return nil, ErrCareInFuture
+		}
+		entry.PerformedAt = *performedAt
+	}
+
+	switch careType {
+	case CareTypeIrrigation, CareTypeFertilization:
+	case CareTypeMeasurement:
+		if height == nil && width == nil {
+			return nil, ErrMissingMeasurement
+		}
+		fallthrough
+	case CareTypePruning:
+		if (height != nil && *height < 0) || (width != nil && *width < 0) {
+			return nil, ErrInvalidMeasurement
+		}
+		entry.Height, entry.Width = height, width
+	default:
+		return nil, ErrInvalidCareType
+	}
+
+	if amount != nil && *amount < 0 {
+		return nil, ErrInvalidCareAmount
+	}
+	if utf8.RuneCountInString(notes) > MaxCareLogNotes {
+		return nil, ErrCareNotesTooLong
+	}
+	return entry, nil
+}
+
+// CareLogIds valida os ids de um registro de cuidado e remove os repetidos.
+func CareLogIds(ids []string) ([]string, error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCareId, id)
+		}
+		id = parsed.String()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	switch {
+	case len(unique) == 0:
+		return nil, ErrMissingCareIds
+	case len(unique) > MaxCareLogBatch:
+		return nil, ErrTooManyCareIds
+	}
+	return unique, nil
+}
+
+// CareSubject é uma planta ou um jardim com o que é preciso para calcular os cuidados.
+type CareSubject struct {
+	Type                string
+	Id                  string
+	Name                string
+	UserId              string
+	WorkspaceId         string
+	LastIrrigation      time.Time
+	LastFertilization   time.Time
+	IrrigationWeek      float64
+	FertilizationWeek   float64
+	IrrigationWeight    float64
+	FertilizationWeight float64
+}
+
+// CareItem é um cuidado com data prevista. DaysOverdue só é positivo em atraso;
+// DaysUntilDue só é positivo no que ainda vai vencer.
+type CareItem struct {
+	SubjectType  string    `json:"subject_type"`
+	SubjectId    string    `json:"subject_id"`
+	SubjectName  string    `json:"subject_name"`
+	WorkspaceId  string    `json:"workspace_id"`
+	CareType     string    `json:"care_type"`
+	LastDone     time.Time `json:"last_done"`
+	NextDue      time.Time `json:"next_due"`
+	IntervalDays float64   `json:"interval_days"`
+	Overdue      bool      `json:"overdue"`
+	DaysOverdue  int       `json:"days_overdue"`
+	DaysUntilDue int       `json:"days_until_due"`
+}
+
+// CareTask é a tarefa gerada para um cuidado. Window é o dia previsto: existe no
+// máximo uma tarefa não cancelada por item, cuidado e janela.
+type CareTask struct {
+	Task     *Task
+	CareType string
+	Window   time.Time
+}
+
+type CareRepository interface {
+	// FindSubjects devolve as plantas e os jardins ativos dos workspaces do usuário.
+	FindSubjects(ctx context.Context, userId string) ([]*CareSubject, error)
+	// FindAutoTaskSubjects devolve, de todos os usuários, as plantas e os jardins ativos com geração de tarefas ligada.
+	FindAutoTaskSubjects(ctx context.Context) ([]*CareSubject, error)
+	// SyncTasks cancela as tarefas pendentes geradas para subject cuja janela não está em
+	// windows (cuidado -> dia previsto) e cria as de tasks que ainda não existem.
+	// Devolve quantas foram criadas e canceladas.
+	SyncTasks(ctx context.Context, subject *CareSubject, windows map[string]time.Time, tasks []*CareTask) (created, cancelled int64, err error)
+	// CancelOrphanTasks cancela as tarefas pendentes geradas para itens excluídos ou com a
+	// geração desligada e devolve os donos das tarefas canceladas.
+	CancelOrphanTasks(ctx context.Context) ([]string, error)
+}
+
+// CareInterval é o tempo entre dois cuidados para perWeek vezes por semana, ajustado
+// pelo peso da espécie (0 a 1): 0.5 mantém a frequência, 1 aumenta em 50% e 0 reduz
+// pela metade. Devolve false quando não há frequência definida.
+func CareInterval(perWeek, weight float64) (time.Duration, bool) {
+	if perWeek <= 0 {
+		return 0, false
+	}
+	weight = math.Min(math.Max(weight, 0), 1)
+	perWeek *= 1 + (weight - NeutralCareWeight)
+	return time.Duration(float64(7*careDay) / perWeek), true
+}
+
+// Schedule calcula o próximo irrigar e adubar do item em relação a now. Cuidados sem
+// frequência definida ficam de fora.
+func (s *CareSubject) Schedule(now time.Time) []*CareItem {
+	cares := []struct {
+		careType string
+		last     time.Time
+		perWeek  float64
+		weight   float64
+	}{
+		{CareTypeIrrigation, s.LastIrrigation, s.IrrigationWeek, s.IrrigationWeight},
+		{CareTypeFertilization, s.LastFertilization, s.FertilizationWeek, s.FertilizationWeight},
+	}
+
+	items := make([]*CareItem, 0, len(cares))
+	for _, care := range cares {
+		interval, ok := CareInterval(care.perWeek, care.weight)
+		if !ok {
+			continue
+		}
+		item := &CareItem{
+			SubjectType:  s.Type,
+			SubjectId:    s.Id,
+			SubjectName:  s.Name,
+			WorkspaceId:  s.WorkspaceId,
+			CareType:     care.careType,
+			LastDone:     care.last,
+			NextDue:      care.last.Add(interval),
+			IntervalDays: math.Round(interval.Hours()/24*100) / 100,
+		}
+		if late := now.Sub(item.NextDue); late > 0 {
+			item.Overdue = true
+			item.DaysOverdue = int(late / careDay)
+		} else {
+			item.DaysUntilDue = int(-late / careDay)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// Window é o dia previsto do cuidado, usado para não gerar duas tarefas iguais.
+func (i *CareItem) Window() time.Time {
+	y, m, d := i.NextDue.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Urgency vai de 1 a 5, como nas tarefas: irrigação pesa mais que adubação e o
+// atraso sobe a urgência.
+func (i *CareItem) Urgency() int {
+	urgency := 2
+	if i.CareType == CareTypeIrrigation {
+		urgency = 3
+	}
+	switch {
+	case i.DaysOverdue >= 2:
+		urgency += 2
+	case i.Overdue:
+		urgency++
+	}
+	return min(urgency, 5)
+}
+
+// NewCareTask monta a tarefa pendente do cuidado, no workspace e em nome do autor do item.
+func NewCareTask(subject *CareSubject, item *CareItem) (*CareTask, error) {
+	action, care := "Irrigar", "Irrigação"
+	if item.CareType == CareTypeFertilization {
+		action, care = "Adubar", "Adubação"
+	}
+	owner := "da planta"
+	var plantsId, gardensId []string
+	if subject.Type == CareSubjectGarden {
+		owner = "do jardim"
+		gardensId = []string{subject.Id}
+	} else {
+		plantsId = []string{subject.Id}
+	}
+
+	task, err := NewTask(
+		truncateRunes(action+" "+subject.Name, 50),
+		truncateRunes(care+" prevista pelo agendamento "+owner+" "+subject.Name, 100),
+		item.NextDue,
+		item.Urgency(),
+		TaskStatusPending,
+		subject.UserId,
+		nil,
+		gardensId,
+		plantsId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	task.WorkspaceId = subject.WorkspaceId
+
+	return &CareTask{
+		Task:     task,
+		CareType: item.CareType,
+		Window:   item.Window(),
+	}, nil
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
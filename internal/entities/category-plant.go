@@ -13,6 +13,7 @@ type CategoryPlant struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	UserId      string    `json:"user_id"`
+	WorkspaceId string    `json:"workspace_id"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
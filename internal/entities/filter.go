@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// Filtros das buscas combináveis. Campos vazios ou nil não restringem a busca.
+
+type PlantFilter struct {
+	Name                 string
+	HealthStatus         string
+	PlantStatus          string
+	Species              string
+	Category             string
+	Garden               string
+	PlantedFrom          *time.Time
+	PlantedTo            *time.Time
+	LastIrrigationBefore *time.Time
+}
+
+type GardenFilter struct {
+	Name                 string
+	Location             string
+	Category             string
+	Plant                string
+	PlantedFrom          *time.Time
+	PlantedTo            *time.Time
+	LastIrrigationBefore *time.Time
+}
+
+type TaskFilter struct {
+	Name         string
+	Category     string
+	Plant        string
+	Garden       string
+	Statuses     []string
+	UrgencyMin   *int
+	UrgencyMax   *int
+	TaskDateFrom *time.Time
+	TaskDateTo   *time.Time
+}
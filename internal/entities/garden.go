@@ -11,6 +11,7 @@ import (
 type Garden struct {
 	Id                string    `json:"id"`
 	UserId            string    `json:"user_id"`
+	WorkspaceId       string    `json:"workspace_id"`
 	GardenName        string    `json:"garden_name"`
 	GardenDescription string    `json:"garden_description"`
 	GardenLocation    string    `json:"garden_location"`
@@ -27,11 +28,14 @@ type Garden struct {
 	UpdatedAt         time.Time `json:"updated_at"`
 	CategoriesPlantId []string  `json:"categories_plant"`
 	PlantsId          []string  `json:"plants_id"`
+	Version           int       `json:"version,omitempty"`
+	AutoTasks         bool      `json:"auto_tasks"`
 }
 
 type GardenOutputDTO struct {
 	Id                string          `json:"id"`
 	UserId            string          `json:"user_id"`
+	WorkspaceId       string          `json:"workspace_id"`
 	GardenName        string          `json:"garden_name"`
 	GardenDescription string          `json:"garden_description"`
 	GardenLocation    string          `json:"garden_location"`
@@ -48,6 +52,8 @@ type GardenOutputDTO struct {
 	UpdatedAt         time.Time       `json:"updated_at"`
 	CategoriesPlant   []CategoryPlant `json:"categories_plant"`
 	Plants            []Plant         `json:"plants"`
+	Version           int             `json:"version"`
+	AutoTasks         bool            `json:"auto_tasks"`
 }
 
 type HistoryGarden struct {
@@ -67,19 +73,32 @@ type HistoryGarden struct {
 	Notes             string    `json:"notes"`
 	UserID            string    `json:"user_id"`
 	CreatedAt         time.Time `json:"created_at"`
+	// preenchidos só nos registros feitos pelo registro de cuidados
+	CareType string   `json:"care_type,omitempty"`
+	Amount   *float64 `json:"amount,omitempty"`
+	// preenchido só nos registros que vieram de um resumo
+	Rollup *HistoryRollup `json:"rollup,omitempty"`
 }
 
 type GardenRepository interface {
 	Create(ctx context.Context, garden *Garden) (string, error)
 	FindByID(ctx context.Context, userId, id string) (*GardenOutputDTO, error)
-	FindByName(ctx context.Context, userId, name string) ([]*GardenOutputDTO, error)
-	FindByLocation(ctx context.Context, userId, location string) ([]*GardenOutputDTO, error)
-	FindByCategoryName(ctx context.Context, userId, categoryName string) ([]*GardenOutputDTO, error)
-	FindAll(ctx context.Context, userId string) ([]*GardenOutputDTO, error)
+	FindByName(ctx context.Context, userId, name string, page PageRequest) (*Page[*GardenOutputDTO], error)
+	// NameInUse diz se outro registro visível para o usuário já tem o nome, sem diferenciar maiúsculas.
+	NameInUse(ctx context.Context, userId, name, exceptId string) (bool, error)
+	FindByLocation(ctx context.Context, userId, location string, page PageRequest) (*Page[*GardenOutputDTO], error)
+	FindByCategoryName(ctx context.Context, userId, categoryName string, page PageRequest) (*Page[*GardenOutputDTO], error)
+	FindAll(ctx context.Context, userId string, page PageRequest) (*Page[*GardenOutputDTO], error)
+	Search(ctx context.Context, userId string, filter GardenFilter, page PageRequest) (*Page[*GardenOutputDTO], error)
 	Update(ctx context.Context, garden *Garden) error
 	Delete(ctx context.Context, userId, id string) error
 	CreateHistory(ctx context.Context, garden *HistoryGarden) error
-	FindAllHistoryByGardenID(ctx context.Context, gardenID string) ([]*HistoryGarden, error)
+	FindAllHistoryByGardenID(ctx context.Context, userId, gardenID string, page PageRequest) (*Page[*HistoryGarden], error)
+	// SetAutoTasks liga ou desliga a geração de tarefas; desligada, cancela as pendentes já geradas.
+	SetAutoTasks(ctx context.Context, userId, id string, enabled bool) error
+	// LogCare aplica care em todos os ids e grava o histórico com os valores novos, numa
+	// só transação: se algum id não existir ou não puder ser editado, nada é gravado.
+	LogCare(ctx context.Context, care *CareLog, ids []string) error
 }
 
 func NewGarden(
@@ -0,0 +1,365 @@
+package entities
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	GrowthStatusStunted = "stunted"
+	GrowthStatusNormal  = "normal"
+	GrowthStatusFast    = "fast"
+	// GrowthStatusUnknown é usado sem medições ou sem médias da espécie para comparar.
+	GrowthStatusUnknown = "unknown"
+
+	GrowthCurveLogistic = "logistic"
+	GrowthCurveLinear   = "linear"
+
+	// abaixo de GrowthStuntedRatio ou acima de GrowthFastRatio da média da espécie
+	// para a idade, o crescimento é sinalizado
+	GrowthStuntedRatio = 0.75
+	GrowthFastRatio    = 1.25
+)
+
+// speciesGrowthShare é quanto do tamanho médio a espécie atinge no tempo de colheita.
+const speciesGrowthShare = 0.95
+
+// GrowthSample é uma medição de altura e largura; zero é medição ausente.
+type GrowthSample struct {
+	Date   time.Time
+	Height float64
+	Width  float64
+}
+
+// GrowthPoint é uma medição da série, com a taxa de crescimento por dia desde a
+// anterior e o esperado para a espécie na mesma idade.
+type GrowthPoint struct {
+	Date           time.Time `json:"date"`
+	AgeDays        float64   `json:"age_days"`
+	Height         float64   `json:"height"`
+	Width          float64   `json:"width"`
+	HeightRate     float64   `json:"height_rate"`
+	WidthRate      float64   `json:"width_rate"`
+	ExpectedHeight float64   `json:"expected_height,omitempty"`
+	ExpectedWidth  float64   `json:"expected_width,omitempty"`
+}
+
+// GrowthCurve é uma curva de tamanho pela idade em dias. A logística usa Capacity,
+// Rate e MidpointDays; a linear usa Intercept e Rate. R2 só vem nas curvas ajustadas.
+type GrowthCurve struct {
+	Model        string  `json:"model"`
+	Capacity     float64 `json:"capacity,omitempty"`
+	Rate         float64 `json:"rate"`
+	MidpointDays float64 `json:"midpoint_days,omitempty"`
+	Intercept    float64 `json:"intercept,omitempty"`
+	R2           float64 `json:"r2,omitempty"`
+}
+
+// GrowthComparison compara a última medição com a média da espécie para a idade.
+type GrowthComparison struct {
+	AgeDays        float64 `json:"age_days"`
+	Height         float64 `json:"height"`
+	Width          float64 `json:"width"`
+	ExpectedHeight float64 `json:"expected_height"`
+	ExpectedWidth  float64 `json:"expected_width"`
+	Ratio          float64 `json:"ratio"`
+	Status         string  `json:"status"`
+}
+
+// GrowthProjection é o tamanho previsto para a data estimada de colheita.
+type GrowthProjection struct {
+	Date           time.Time `json:"date"`
+	AgeDays        float64   `json:"age_days"`
+	Height         float64   `json:"height"`
+	Width          float64   `json:"width"`
+	ExpectedHeight float64   `json:"expected_height,omitempty"`
+	ExpectedWidth  float64   `json:"expected_width,omitempty"`
+}
+
+type GrowthAnalytics struct {
+	PlantId              string            `json:"plant_id"`
+	SpeciesId            string            `json:"species_id"`
+	PlantingDate         time.Time         `json:"planting_date"`
+	EstimatedHarvestDate time.Time         `json:"estimated_harvest_date"`
+	Points               []*GrowthPoint    `json:"points"`
+	HeightCurve          *GrowthCurve      `json:"height_curve,omitempty"`
+	WidthCurve           *GrowthCurve      `json:"width_curve,omitempty"`
+	ExpectedHeightCurve  *GrowthCurve      `json:"expected_height_curve,omitempty"`
+	ExpectedWidthCurve   *GrowthCurve      `json:"expected_width_curve,omitempty"`
+	Comparison           *GrowthComparison `json:"comparison"`
+	Projection           *GrowthProjection `json:"projection,omitempty"`
+}
+
+// At devolve o tamanho da curva na idade em dias, nunca negativo.
+func (c *GrowthCurve) At(ageDays float64) float64 {
+	if c == nil {
+		return 0
+	}
+	var value float64
+	if c.Model == GrowthCurveLogistic {
+		value = c.Capacity / (1 + math.Exp(-c.Rate*(ageDays-c.MidpointDays)))
+	} else {
+		value = c.Intercept + c.Rate*ageDays
+	}
+	return math.Max(value, 0)
+}
+
+// SpeciesGrowthCurve é a curva logística esperada para uma espécie que chega a
+// speciesGrowthShare de average em harvestDays, com o meio do crescimento na metade
+// desse tempo. Devolve nil sem média ou sem tempo de colheita.
+func SpeciesGrowthCurve(average float64, harvestDays int) *GrowthCurve {
+	if average <= 0 || harvestDays <= 0 {
+		return nil
+	}
+	midpoint := float64(harvestDays) / 2
+	return &GrowthCurve{
+		Model:        GrowthCurveLogistic,
+		Capacity:     average,
+		Rate:         math.Log(speciesGrowthShare/(1-speciesGrowthShare)) / midpoint,
+		MidpointDays: midpoint,
+	}
+}
+
+// FitGrowthCurve ajusta uma curva às medições (idade em dias, tamanho). Com capacity,
+// tenta a logística com esse teto, ou um pouco acima do maior valor medido se ele já
+// passou; sem capacity ou quando a logística não descreve as medições, usa uma reta.
+// Precisa de ao menos duas idades diferentes.
+func FitGrowthCurve(ages, values []float64, capacity float64) *GrowthCurve {
+	var xs, ys []float64
+	var largest float64
+	for i := range ages {
+		if values[i] <= 0 || ages[i] < 0 {
+			continue
+		}
+		xs = append(xs, ages[i])
+		ys = append(ys, values[i])
+		largest = math.Max(largest, values[i])
+	}
+
+	if capacity > 0 && len(xs) >= 3 {
+		capacity = math.Max(capacity, largest*1.05)
+		// ln(K/v - 1) = r*t0 - r*t é linear na idade
+		linearized := make([]float64, len(ys))
+		for i, v := range ys {
+			linearized[i] = math.Log(capacity/v - 1)
+		}
+		if intercept, slope, ok := linearFit(xs, linearized); ok && slope < 0 {
+			curve := &GrowthCurve{
+				Model:        GrowthCurveLogistic,
+				Capacity:     capacity,
+				Rate:         -slope,
+				MidpointDays: intercept / -slope,
+			}
+			curve.R2 = rSquared(curve, xs, ys)
+			if curve.R2 >= 0 {
+				return curve
+			}
+		}
+	}
+
+	intercept, slope, ok := linearFit(xs, ys)
+	if !ok {
+		return nil
+	}
+	curve := &GrowthCurve{Model: GrowthCurveLinear, Intercept: intercept, Rate: slope}
+	curve.R2 = rSquared(curve, xs, ys)
+	return curve
+}
+
+// linearFit é a regressão de mínimos quadrados y = a + b*x.
+func linearFit(xs, ys []float64) (a, b float64, ok bool) {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0, 0, false
+	}
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	den := n*sxx - sx*sx
+	if den == 0 {
+		return 0, 0, false
+	}
+	b = (n*sxy - sx*sy) / den
+	a = (sy - b*sx) / n
+	return a, b, true
+}
+
+func rSquared(curve *GrowthCurve, xs, ys []float64) float64 {
+	var mean float64
+	for _, y := range ys {
+		mean += y
+	}
+	mean /= float64(len(ys))
+	var residual, total float64
+	for i := range xs {
+		residual += math.Pow(ys[i]-curve.At(xs[i]), 2)
+		total += math.Pow(ys[i]-mean, 2)
+	}
+	if total == 0 {
+		return 1
+	}
+	return round2(1 - residual/total)
+}
+
+// AnalyzeGrowth monta a série de crescimento da planta a partir das medições, ajusta as
+// curvas, compara com a média da espécie e projeta o tamanho na colheita. O tamanho
+// atual da planta entra como a medição de now. specie pode ser nil.
+func AnalyzeGrowth(plant *PlantWithCategory, specie *Specie, samples []*GrowthSample, now time.Time) *GrowthAnalytics {
+	analytics := &GrowthAnalytics{
+		PlantId:              plant.PlantId,
+		SpeciesId:            plant.SpeciesId,
+		PlantingDate:         plant.PlantingDate,
+		EstimatedHarvestDate: plant.EstimatedHarvestDate,
+		Points:               make([]*GrowthPoint, 0, len(samples)+1),
+		Comparison:           &GrowthComparison{Status: GrowthStatusUnknown},
+	}
+	if specie != nil {
+		analytics.ExpectedHeightCurve = SpeciesGrowthCurve(specie.AverageHeight, specie.HarvestTime)
+		analytics.ExpectedWidthCurve = SpeciesGrowthCurve(specie.AverageWidth, specie.HarvestTime)
+	}
+	ageOf := func(date time.Time) float64 {
+		return round2(date.Sub(plant.PlantingDate).Hours() / 24)
+	}
+
+	// samples pode vir compartilhado do cache, então a ordenação é feita numa cópia
+	all := make([]*GrowthSample, 0, len(samples)+1)
+	all = append(all, samples...)
+	all = append(all, &GrowthSample{Date: now, Height: plant.CurrentHeight, Width: plant.CurrentWidth})
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Date.Before(all[j].Date) })
+
+	// medições do mesmo dia valem como uma só, a mais recente
+	measured := make([]*GrowthSample, 0, len(all))
+	for _, sample := range all {
+		if sample.Height <= 0 && sample.Width <= 0 {
+			continue
+		}
+		if n := len(measured); n > 0 && sample.Date.Sub(measured[n-1].Date) < careDay {
+			measured[n-1] = sample
+			continue
+		}
+		measured = append(measured, sample)
+	}
+
+	var ages, heights, widths []float64
+	for _, sample := range measured {
+		age := ageOf(sample.Date)
+		point := &GrowthPoint{
+			Date:           sample.Date,
+			AgeDays:        age,
+			Height:         sample.Height,
+			Width:          sample.Width,
+			ExpectedHeight: round2(analytics.ExpectedHeightCurve.At(age)),
+			ExpectedWidth:  round2(analytics.ExpectedWidthCurve.At(age)),
+		}
+		if n := len(analytics.Points); n > 0 {
+			point.HeightRate, point.WidthRate = growthRates(analytics.Points[n-1], point)
+		}
+		analytics.Points = append(analytics.Points, point)
+		ages, heights, widths = append(ages, age), append(heights, sample.Height), append(widths, sample.Width)
+	}
+
+	var expectedHeight, expectedWidth float64
+	if specie != nil {
+		expectedHeight, expectedWidth = specie.AverageHeight, specie.AverageWidth
+	}
+	analytics.HeightCurve = FitGrowthCurve(ages, heights, expectedHeight)
+	analytics.WidthCurve = FitGrowthCurve(ages, widths, expectedWidth)
+
+	if n := len(analytics.Points); n > 0 {
+		analytics.Comparison = compareGrowth(analytics.Points[n-1])
+	}
+	analytics.Projection = projectGrowth(analytics, ageOf)
+	return analytics
+}
+
+func growthRates(previous, point *GrowthPoint) (float64, float64) {
+	days := point.Date.Sub(previous.Date).Hours() / 24
+	if days <= 0 {
+		return 0, 0
+	}
+	var heightRate, widthRate float64
+	if previous.Height > 0 && point.Height > 0 {
+		heightRate = roundRate((point.Height - previous.Height) / days)
+	}
+	if previous.Width > 0 && point.Width > 0 {
+		widthRate = roundRate((point.Width - previous.Width) / days)
+	}
+	return heightRate, widthRate
+}
+
+// compareGrowth usa a média das razões medida/esperada de altura e largura.
+func compareGrowth(last *GrowthPoint) *GrowthComparison {
+	comparison := &GrowthComparison{
+		AgeDays:        last.AgeDays,
+		Height:         last.Height,
+		Width:          last.Width,
+		ExpectedHeight: last.ExpectedHeight,
+		ExpectedWidth:  last.ExpectedWidth,
+		Status:         GrowthStatusUnknown,
+	}
+	var sum, n float64
+	if last.Height > 0 && last.ExpectedHeight > 0 {
+		sum += last.Height / last.ExpectedHeight
+		n++
+	}
+	if last.Width > 0 && last.ExpectedWidth > 0 {
+		sum += last.Width / last.ExpectedWidth
+		n++
+	}
+	if n == 0 {
+		return comparison
+	}
+	comparison.Ratio = round2(sum / n)
+	switch {
+	case comparison.Ratio < GrowthStuntedRatio:
+		comparison.Status = GrowthStatusStunted
+	case comparison.Ratio > GrowthFastRatio:
+		comparison.Status = GrowthStatusFast
+	default:
+		comparison.Status = GrowthStatusNormal
+	}
+	return comparison
+}
+
+// projectGrowth usa as curvas ajustadas; sem elas, aplica a razão atual à curva da
+// espécie. Sem data de colheita ou sem base para projetar, devolve nil.
+func projectGrowth(analytics *GrowthAnalytics, ageOf func(time.Time) float64) *GrowthProjection {
+	harvest := analytics.EstimatedHarvestDate
+	if harvest.IsZero() {
+		return nil
+	}
+	age := ageOf(harvest)
+	projection := &GrowthProjection{
+		Date:           harvest,
+		AgeDays:        age,
+		ExpectedHeight: round2(analytics.ExpectedHeightCurve.At(age)),
+		ExpectedWidth:  round2(analytics.ExpectedWidthCurve.At(age)),
+	}
+	ratio := analytics.Comparison.Ratio
+	project := func(fitted *GrowthCurve, expected float64) float64 {
+		if fitted != nil {
+			return round2(fitted.At(age))
+		}
+		return round2(expected * ratio)
+	}
+	projection.Height = project(analytics.HeightCurve, projection.ExpectedHeight)
+	projection.Width = project(analytics.WidthCurve, projection.ExpectedWidth)
+	if projection.Height == 0 && projection.Width == 0 {
+		return nil
+	}
+	return projection
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// roundRate guarda mais casas porque o crescimento de um dia costuma ser pequeno.
+func roundRate(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
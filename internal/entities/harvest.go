@@ -0,0 +1,166 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HarvestUnitGram       = "g"
+	HarvestUnitKilogram   = "kg"
+	HarvestUnitMilliliter = "ml"
+	HarvestUnitLiter      = "l"
+	HarvestUnitCount      = "unit"
+
+	HarvestGroupPlant   = "plant"
+	HarvestGroupGarden  = "garden"
+	HarvestGroupSpecies = "species"
+	HarvestGroupSeason  = "season"
+
+	// HarvestOnTimeDays é a folga, em dias, para a colheita contar como feita na data estimada.
+	HarvestOnTimeDays = 7
+	// MaxHarvestNotes é o tamanho máximo das observações de uma colheita.
+	MaxHarvestNotes = 500
+)
+
+var (
+	ErrMissingHarvestPlant    = errors.New("plant_id é obrigatório")
+	ErrInvalidHarvestQuantity = errors.New("a quantidade colhida deve ser maior que zero")
+	ErrInvalidHarvestUnit     = errors.New("unidade inválida: use g, kg, ml, l ou unit")
+	ErrInvalidHarvestGrade    = errors.New("classificação inválida: use A, B ou C")
+	ErrHarvestInFuture        = errors.New("a data da colheita não pode estar no futuro")
+	ErrHarvestNotesTooLong    = errors.New("as observações devem ter até 500 caracteres")
+	ErrInvalidPlantStatus     = errors.New("o status da planta deve ter até 50 caracteres")
+	ErrInvalidHarvestGroup    = errors.New("agrupamento inválido: use plant, garden, species ou season")
+	ErrHarvestNotFound        = errors.New("colheita não encontrada")
+	ErrHarvestGardenNotFound  = errors.New("jardim não encontrado no workspace da planta")
+)
+
+// Harvest é uma colheita de uma planta, opcionalmente feita num jardim. A data
+// estimada é a da planta no momento do registro e serve para medir a precisão.
+type Harvest struct {
+	Id                   string     `json:"id"`
+	PlantId              string     `json:"plant_id"`
+	GardenId             string     `json:"garden_id,omitempty"`
+	WorkspaceId          string     `json:"workspace_id"`
+	UserId               string     `json:"user_id"`
+	HarvestDate          time.Time  `json:"harvest_date"`
+	Quantity             float64    `json:"quantity"`
+	Unit                 string     `json:"unit"`
+	QualityGrade         string     `json:"quality_grade,omitempty"`
+	Notes                string     `json:"notes"`
+	EstimatedHarvestDate *time.Time `json:"estimated_harvest_date,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+type HarvestFilter struct {
+	PlantId  string
+	GardenId string
+	From     *time.Time
+	To       *time.Time
+}
+
+type HarvestStatsQuery struct {
+	GroupBy string
+	From    *time.Time
+	To      *time.Time
+}
+
+// HarvestYield soma as colheitas de um grupo numa unidade base: gramas viram kg,
+// mililitros viram litros e unidades continuam contadas.
+type HarvestYield struct {
+	Unit     string  `json:"unit"`
+	Harvests int     `json:"harvests"`
+	Total    float64 `json:"total"`
+	Average  float64 `json:"average"`
+}
+
+// HarvestAccuracy compara a primeira colheita de cada planta com a data estimada.
+// AvgDelayDays positivo é atraso; OnTimeRatio é a parte colhida dentro de HarvestOnTimeDays.
+type HarvestAccuracy struct {
+	Samples         int     `json:"samples"`
+	AvgDelayDays    float64 `json:"avg_delay_days"`
+	AvgAbsDelayDays float64 `json:"avg_abs_delay_days"`
+	OnTimeRatio     float64 `json:"on_time_ratio"`
+}
+
+type HarvestStats struct {
+	Key          string           `json:"key"`
+	Label        string           `json:"label"`
+	Harvests     int              `json:"harvests"`
+	FirstHarvest time.Time        `json:"first_harvest"`
+	LastHarvest  time.Time        `json:"last_harvest"`
+	Yields       []*HarvestYield  `json:"yields"`
+	Accuracy     *HarvestAccuracy `json:"accuracy,omitempty"`
+}
+
+type HarvestRepository interface {
+	// Create grava a colheita no workspace da planta. Com plantStatus, muda também o
+	// status da planta na mesma transação.
+	Create(ctx context.Context, harvest *Harvest, plantStatus string) error
+	FindAll(ctx context.Context, userId string, filter HarvestFilter, page PageRequest) (*Page[*Harvest], error)
+	Delete(ctx context.Context, userId, id string) error
+	Stats(ctx context.Context, userId string, query HarvestStatsQuery) ([]*HarvestStats, error)
+}
+
+// NewHarvest valida a colheita. Sem date, a colheita é registrada agora.
+func NewHarvest(plantId, gardenId, userId, unit, qualityGrade, notes string, date *time.Time, quantity float64) (*Harvest, error) {
+	if plantId == "" {
+		return nil, ErrMissingHarvestPlant
+	}
+	if quantity <= 0 {
+		return nil, ErrInvalidHarvestQuantity
+	}
+	if !IsHarvestUnit(unit) {
+		return nil, ErrInvalidHarvestUnit
+	}
+	switch qualityGrade {
+	case "", "A", "B", "C":
+	default:
+		return nil, ErrInvalidHarvestGrade
+	}
+	if utf8.RuneCountInString(notes) > MaxHarvestNotes {
+		return nil, ErrHarvestNotesTooLong
+	}
+
+	now := time.Now()
+	harvestDate := now
+	if date != nil && !date.IsZero() {
+		if date.After(now.Add(time.Minute)) {
+			return nil, ErrHarvestInFuture
+		}
+		harvestDate = *date
+	}
+
+	return &Harvest{
+		Id:           uuid.New().String(),
+		PlantId:      plantId,
+		GardenId:     gardenId,
+		UserId:       userId,
+		HarvestDate:  harvestDate,
+		Quantity:     quantity,
+		Unit:         unit,
+		QualityGrade: qualityGrade,
+		Notes:        notes,
+	}, nil
+}
+
+func IsHarvestUnit(unit string) bool {
+	switch unit {
+	case HarvestUnitGram, HarvestUnitKilogram, HarvestUnitMilliliter, HarvestUnitLiter, HarvestUnitCount:
+		return true
+	}
+	return false
+}
+
+func IsHarvestGroup(groupBy string) bool {
+	switch groupBy {
+	case HarvestGroupPlant, HarvestGroupGarden, HarvestGroupSpecies, HarvestGroupSeason:
+		return true
+	}
+	return false
+}
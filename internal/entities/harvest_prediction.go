@@ -0,0 +1,178 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	HarvestConfidenceHigh   = "high"
+	HarvestConfidenceMedium = "medium"
+	HarvestConfidenceLow    = "low"
+)
+
+// ErrHarvestPredictionUnavailable indica espécie sem tempo de colheita cadastrado.
+var ErrHarvestPredictionUnavailable = errors.New("a espécie não tem tempo de colheita para prever a data")
+
+// parte do ciclo que a margem da previsão ocupa antes de qualquer ajuste
+const (
+	harvestBaseMargin    = 0.2
+	harvestGrowthMargin  = 0.1
+	harvestSeasonMargin  = 0.05
+	harvestSeasonDelay   = 0.15
+	harvestMaxGrowthSkew = 0.5
+)
+
+// HarvestPrediction é a data de colheita prevista com a faixa em que ela deve cair.
+// Os atrasos são em dias sobre o ciclo da espécie; negativos adiantam a colheita.
+type HarvestPrediction struct {
+	PlantId         string    `json:"plant_id,omitempty"`
+	SpeciesId       string    `json:"species_id"`
+	PlantingDate    time.Time `json:"planting_date"`
+	EstimatedDate   time.Time `json:"estimated_date"`
+	EarliestDate    time.Time `json:"earliest_date"`
+	LatestDate      time.Time `json:"latest_date"`
+	MarginDays      int       `json:"margin_days"`
+	Confidence      string    `json:"confidence"`
+	HarvestDays     int       `json:"harvest_days"`
+	InSeason        *bool     `json:"in_season,omitempty"`
+	SeasonDelayDays int       `json:"season_delay_days"`
+	GrowthDelayDays int       `json:"growth_delay_days"`
+	GrowthStatus    string    `json:"growth_status"`
+}
+
+// PredictHarvest prevê a colheita pelo ciclo da espécie a partir do plantio. Plantar
+// fora da época da espécie atrasa o ciclo, e o tamanho medido diz se a planta está
+// adiantada ou atrasada em relação à curva esperada. Quanto mais perto da colheita e
+// mais medições, menor a faixa.
+func PredictHarvest(plant *PlantWithCategory, specie *Specie, samples []*GrowthSample, now time.Time) (*HarvestPrediction, error) {
+	if specie == nil || specie.HarvestTime <= 0 {
+		return nil, ErrHarvestPredictionUnavailable
+	}
+	days := float64(specie.HarvestTime)
+	prediction := &HarvestPrediction{
+		PlantId:      plant.PlantId,
+		SpeciesId:    plant.SpeciesId,
+		PlantingDate: plant.PlantingDate,
+		HarvestDays:  specie.HarvestTime,
+		GrowthStatus: GrowthStatusUnknown,
+	}
+	margin := harvestBaseMargin
+
+	// sem época reconhecível a previsão só fica menos precisa
+	if distance, ok := seasonDistance(specie.PlantingSeason, plant.PlantingDate); ok {
+		inSeason := distance == 0
+		prediction.InSeason = &inSeason
+		prediction.SeasonDelayDays = int(math.Round(days * harvestSeasonDelay * float64(distance)))
+		margin += harvestSeasonMargin * float64(distance)
+	} else {
+		margin += harvestSeasonMargin
+	}
+
+	growth := AnalyzeGrowth(plant, specie, samples, now)
+	prediction.GrowthStatus = growth.Comparison.Status
+	if delay, weight := growthDelay(growth, days); weight > 0 {
+		prediction.GrowthDelayDays = int(math.Round(delay * weight))
+		margin -= harvestGrowthMargin * weight
+	}
+
+	total := specie.HarvestTime + prediction.SeasonDelayDays + prediction.GrowthDelayDays
+	prediction.EstimatedDate = plant.PlantingDate.AddDate(0, 0, total)
+
+	// a faixa encolhe até a metade conforme a colheita se aproxima
+	remaining := prediction.EstimatedDate.Sub(now).Hours() / 24
+	share := math.Min(math.Max(remaining/float64(total), 0), 1)
+	marginDays := math.Max(math.Round(days*margin*(0.5+0.5*share)), 1)
+	prediction.MarginDays = int(marginDays)
+	prediction.EarliestDate = prediction.EstimatedDate.AddDate(0, 0, -prediction.MarginDays)
+	prediction.LatestDate = prediction.EstimatedDate.AddDate(0, 0, prediction.MarginDays)
+
+	switch ratio := marginDays / days; {
+	case ratio <= 0.1:
+		prediction.Confidence = HarvestConfidenceHigh
+	case ratio <= 0.2:
+		prediction.Confidence = HarvestConfidenceMedium
+	default:
+		prediction.Confidence = HarvestConfidenceLow
+	}
+	return prediction, nil
+}
+
+// growthDelay compara a idade da planta com a idade em que a curva da espécie chega
+// ao tamanho medido. Devolve o atraso em dias e o peso da medição, que só é total a
+// partir da metade do ciclo porque no início o tamanho diz pouco sobre o ritmo.
+func growthDelay(growth *GrowthAnalytics, days float64) (float64, float64) {
+	n := len(growth.Points)
+	if n == 0 {
+		return 0, 0
+	}
+	last := growth.Points[n-1]
+	if last.AgeDays <= 0 {
+		return 0, 0
+	}
+
+	var sum, count float64
+	for _, dim := range []struct {
+		curve *GrowthCurve
+		value float64
+	}{
+		{growth.ExpectedHeightCurve, last.Height},
+		{growth.ExpectedWidthCurve, last.Width},
+	} {
+		if dim.curve == nil || dim.value <= 0 {
+			continue
+		}
+		sum += last.AgeDays - growthAge(dim.curve, dim.value, days)
+		count++
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	limit := days * harvestMaxGrowthSkew
+	delay := math.Min(math.Max(sum/count, -limit), limit)
+	return delay, math.Min(last.AgeDays/(days/2), 1)
+}
+
+// growthAge inverte a curva logística: a idade em que ela atinge value. Quem já
+// passou do tamanho de colheita conta como no fim do ciclo.
+func growthAge(curve *GrowthCurve, value, days float64) float64 {
+	if value >= curve.Capacity*speciesGrowthShare {
+		return days
+	}
+	age := curve.MidpointDays - math.Log(curve.Capacity/value-1)/curve.Rate
+	return math.Max(age, 0)
+}
+
+// estações do hemisfério sul na ordem do ano, começando pela primavera
+var plantingSeasons = map[string]int{
+	"primavera": 0,
+	"verão":     1,
+	"verao":     1,
+	"outono":    2,
+	"inverno":   3,
+}
+
+// seasonDistance conta quantas estações separam o plantio da época da espécie,
+// escrita como "Primavera/Verão". Sem época reconhecível, ok é falso.
+func seasonDistance(plantingSeason string, plantingDate time.Time) (int, bool) {
+	season := strings.ToLower(strings.TrimSpace(plantingSeason))
+	if strings.Contains(season, "ano todo") || strings.Contains(season, "todo o ano") {
+		return 0, true
+	}
+
+	planted := (int(plantingDate.Month()) + 3) % 12 / 3
+	distance, ok := 4, false
+	for _, name := range strings.FieldsFunc(season, func(r rune) bool {
+		return r == '/' || r == ',' || r == ' '
+	}) {
+		index, known := plantingSeasons[name]
+		if !known {
+			continue
+		}
+		d := (index - planted + 4) % 4
+		distance, ok = min(distance, d, 4-d), true
+	}
+	return distance, ok
+}
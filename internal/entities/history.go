@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	HistoryGranularityRaw   = "raw"
+	HistoryGranularityWeek  = "week"
+	HistoryGranularityMonth = "month"
+)
+
+// HistoryRollup resume os registros de um período que já saiu da retenção do
+// histórico. As médias de altura e largura ficam em Height e Width do registro.
+type HistoryRollup struct {
+	Granularity        string    `json:"granularity"`
+	PeriodEnd          time.Time `json:"period_end"`
+	Samples            int       `json:"samples"`
+	MinHeight          float64   `json:"min_height"`
+	MaxHeight          float64   `json:"max_height"`
+	MinWidth           float64   `json:"min_width"`
+	MaxWidth           float64   `json:"max_width"`
+	IrrigationCount    int       `json:"irrigation_count"`
+	FertilizationCount int       `json:"fertilization_count"`
+}
+
+type HistoryRepository interface {
+	// EnsurePartitions cria as partições mensais do mês atual até months meses à frente e devolve quantas foram criadas.
+	EnsurePartitions(ctx context.Context, months int) (int, error)
+	// Rollup resume por granularity o histórico mais antigo que retention, apaga os registros
+	// resumidos e devolve quantos períodos foram gravados.
+	Rollup(ctx context.Context, retention time.Duration, granularity string) (int64, error)
+}
+
+// IsHistoryGranularity informa se granularity é um dos períodos aceitos nos resumos.
+func IsHistoryGranularity(granularity string) bool {
+	return granularity == HistoryGranularityWeek || granularity == HistoryGranularityMonth
+}
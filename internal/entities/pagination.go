@@ -0,0 +1,40 @@
+package entities
+
+import "errors"
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("cursor inválido")
+	ErrInvalidSort   = errors.New("campo de ordenação inválido")
+)
+
+// PageRequest descreve uma página de uma listagem ordenada por keyset.
+// Cursor é o next_cursor devolvido pela página anterior.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	Sort   string
+	Order  string
+}
+
+// Normalize aplica o limite padrão e o teto de itens por página.
+func (p PageRequest) Normalize() PageRequest {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	return p
+}
+
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/google/uuid"
 )
 
+var ErrPlantNotFound = errors.New("planta não encontrada")
+
 type Plant struct {
 	Id                   string    `json:"id"`
 	PlantName            string    `json:"plant_name"`
@@ -24,10 +26,14 @@ type Plant struct {
 	SunExposure          float64   `json:"sun_exposure"`
 	FertilizationWeek    float64   `json:"fertilization_week"`
 	UserId               string    `json:"user_id"`
+	WorkspaceId          string    `json:"workspace_id"`
 	SpeciesId            string    `json:"species_id"`
 	CreatedAt            time.Time `json:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at"`
 	CategoriesPlant      []string  `json:"categories_plant"`
+	Version              int       `json:"version,omitempty"`
+	// gera tarefas de irrigação e adubação pelo agendamento de cuidados
+	AutoTasks bool `json:"auto_tasks"`
 }
 
 type PlantWithCategory struct {
@@ -46,10 +52,13 @@ type PlantWithCategory struct {
 	SunExposure          float64
 	FertilizationWeek    float64
 	UserId               string
+	WorkspaceId          string
 	SpeciesId            string
 	PlantCreatedAt       time.Time
 	PlantUpdatedAt       time.Time
 	Category             []CategoryPlant
+	Version              int
+	AutoTasks            bool
 }
 
 type HistoryPlant struct {
@@ -67,19 +76,34 @@ type HistoryPlant struct {
 	Notes             string    `json:"notes"`
 	UserID            string    `json:"user_id"`
 	CreatedAt         time.Time `json:"created_at"`
+	// preenchidos só nos registros feitos pelo registro de cuidados
+	CareType string   `json:"care_type,omitempty"`
+	Amount   *float64 `json:"amount,omitempty"`
+	// preenchido só nos registros que vieram de um resumo
+	Rollup *HistoryRollup `json:"rollup,omitempty"`
 }
 
 type PlantRepository interface {
 	Create(ctx context.Context, plant *Plant) (string, error)
 	FindByID(ctx context.Context, userId, id string) (*PlantWithCategory, error)
-	FindBySpeciesName(ctx context.Context, userId, speciesId string) ([]*PlantWithCategory, error)
-	FindByCategoryName(ctx context.Context, userId, categoryName string) ([]*PlantWithCategory, error)
-	FindByName(ctx context.Context, userId, name string) ([]*PlantWithCategory, error)
-	FindAll(ctx context.Context, userId string) ([]*PlantWithCategory, error)
+	FindBySpeciesName(ctx context.Context, userId, speciesId string, page PageRequest) (*Page[*PlantWithCategory], error)
+	FindByCategoryName(ctx context.Context, userId, categoryName string, page PageRequest) (*Page[*PlantWithCategory], error)
+	FindByName(ctx context.Context, userId, name string, page PageRequest) (*Page[*PlantWithCategory], error)
+	// NameInUse diz se outro registro visível para o usuário já tem o nome, sem diferenciar maiúsculas.
+	NameInUse(ctx context.Context, userId, name, exceptId string) (bool, error)
+	FindAll(ctx context.Context, userId string, page PageRequest) (*Page[*PlantWithCategory], error)
+	Search(ctx context.Context, userId string, filter PlantFilter, page PageRequest) (*Page[*PlantWithCategory], error)
 	Update(ctx context.Context, plant *Plant) error
 	Delete(ctx context.Context, userId, id string) error
 	CreateHistory(ctx context.Context, plant *HistoryPlant) error
-	FindAllHistoryByPlantID(ctx context.Context, plantID string) ([]*HistoryPlant, error)
+	FindAllHistoryByPlantID(ctx context.Context, userId, plantID string, page PageRequest) (*Page[*HistoryPlant], error)
+	// FindGrowthSamples devolve as medições do histórico da planta, inclusive as dos resumos, em ordem de data.
+	FindGrowthSamples(ctx context.Context, plantID string) ([]*GrowthSample, error)
+	// SetAutoTasks liga ou desliga a geração de tarefas; desligada, cancela as pendentes já geradas.
+	SetAutoTasks(ctx context.Context, userId, id string, enabled bool) error
+	// LogCare aplica care em todos os ids e grava o histórico com os valores novos, numa
+	// só transação: se algum id não existir ou não puder ser editado, nada é gravado.
+	LogCare(ctx context.Context, care *CareLog, ids []string) error
 }
 
 func NewPlant(
@@ -0,0 +1,28 @@
+package entities
+
+import "context"
+
+const (
+	SearchTypePlant      = "plant"
+	SearchTypeGarden     = "garden"
+	SearchTypeTask       = "task"
+	SearchTypePlantNote  = "plant_note"
+	SearchTypeGardenNote = "garden_note"
+	SearchTypeSpecies    = "species"
+)
+
+// SearchResult é um item da busca unificada. Em notas, ParentId aponta para a
+// planta ou o jardim do histórico.
+type SearchResult struct {
+	Type     string  `json:"type"`
+	Id       string  `json:"id"`
+	Title    string  `json:"title"`
+	Snippet  string  `json:"snippet,omitempty"`
+	ParentId string  `json:"parent_id,omitempty"`
+	Rank     float64 `json:"rank"`
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, userId, query string, limit int) ([]*SearchResult, error)
+	Autocomplete(ctx context.Context, userId, prefix string, limit int) ([]*SearchResult, error)
+}
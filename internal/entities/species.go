@@ -2,9 +2,12 @@ package entities
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var ErrSpecieNotFound = errors.New("espécie não encontrada")
+
 type Specie struct {
 	ID                  string    `json:"id" db:"id"`
 	CommonName          string    `json:"common_name" db:"common_name"`
@@ -31,4 +34,6 @@ type SpecieRepository interface {
 	FindAll(ctx context.Context) ([]*Specie, error)
 	FindById(ctx context.Context, id string) (*Specie, error)
 	FindByName(ctx context.Context, commonName string) ([]*Specie, error)
+	// Upsert grava o catálogo casando pelo nome científico; quem já existe mantém o id.
+	Upsert(ctx context.Context, species []*Specie) error
 }
@@ -8,6 +8,11 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	TaskStatusPending   = "pending"
+	TaskStatusCancelled = "cancelled"
+)
+
 type Task struct {
 	Id           string    `json:"id"`
 	Name         string    `json:"name"`
@@ -16,11 +21,13 @@ type Task struct {
 	UrgencyLevel int       `json:"urgency_level"`
 	TaskStatus   string    `json:"task_status"`
 	UserId       string    `json:"user_id"`
+	WorkspaceId  string    `json:"workspace_id"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	CategoriesId []string  `json:"categories_id"`
 	GardensId    []string  `json:"gardens_id"`
 	PlantsId     []string  `json:"plants_id"`
+	Version      int       `json:"version,omitempty"`
 }
 
 type TaskOutputDTO struct {
@@ -31,11 +38,13 @@ type TaskOutputDTO struct {
 	UrgencyLevel int            `json:"urgency_level"`
 	TaskStatus   string         `json:"task_status"`
 	UserId       string         `json:"user_id"`
+	WorkspaceId  string         `json:"workspace_id"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	Categories   []CategoryTask `json:"categories"`
 	Gardens      []Garden       `json:"gardens"`
 	Plants       []Plant        `json:"plants"`
+	Version      int            `json:"version"`
 }
 
 type TaskRepository interface {
@@ -43,11 +52,14 @@ type TaskRepository interface {
 	Update(ctx context.Context, task *Task) error
 	Delete(ctx context.Context, userId, id string) error
 	FindByID(ctx context.Context, userId, id string) (*TaskOutputDTO, error)
-	FindByCategoryName(ctx context.Context, userId, categoryName string) ([]*TaskOutputDTO, error)
-	FindByName(ctx context.Context, userId, name string) ([]*TaskOutputDTO, error)
-	FindAll(ctx context.Context, userId string) ([]*TaskOutputDTO, error)
-	FindByStatus(ctx context.Context, userId, status string) ([]*TaskOutputDTO, error)
-	FindByUrgencyLevel(ctx context.Context, userId string, urgencyLevel int) ([]*TaskOutputDTO, error)
+	FindByCategoryName(ctx context.Context, userId, categoryName string, page PageRequest) (*Page[*TaskOutputDTO], error)
+	FindByName(ctx context.Context, userId, name string, page PageRequest) (*Page[*TaskOutputDTO], error)
+	// NameInUse diz se outro registro visível para o usuário já tem o nome, sem diferenciar maiúsculas.
+	NameInUse(ctx context.Context, userId, name, exceptId string) (bool, error)
+	FindAll(ctx context.Context, userId string, page PageRequest) (*Page[*TaskOutputDTO], error)
+	FindByStatus(ctx context.Context, userId, status string, page PageRequest) (*Page[*TaskOutputDTO], error)
+	FindByUrgencyLevel(ctx context.Context, userId string, urgencyLevel int, page PageRequest) (*Page[*TaskOutputDTO], error)
+	Search(ctx context.Context, userId string, filter TaskFilter, page PageRequest) (*Page[*TaskOutputDTO], error)
 }
 
 func NewTask(
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	TrashTypePlant         = "plant"
+	TrashTypeGarden        = "garden"
+	TrashTypeTask          = "task"
+	TrashTypeCategoryPlant = "category_plant"
+	TrashTypeCategoryTask  = "category_task"
+)
+
+var ErrTrashItemNotFound = errors.New("item não encontrado na lixeira")
+
+// TrashItem é um registro excluído que ainda pode ser restaurado até o expurgo.
+type TrashItem struct {
+	Type      string    `json:"type"`
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashRepository interface {
+	List(ctx context.Context, userId string) ([]*TrashItem, error)
+	Restore(ctx context.Context, userId, itemType, id string) error
+	// Purge apaga de vez o que está na lixeira há mais de retention e devolve quantos registros saíram.
+	Purge(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// IsTrashType informa se itemType é um dos tipos que vão para a lixeira.
+func IsTrashType(itemType string) bool {
+	switch itemType {
+	case TrashTypePlant, TrashTypeGarden, TrashTypeTask, TrashTypeCategoryPlant, TrashTypeCategoryTask:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+package entities
+
+import "context"
+
+// UnitOfWork agrupa chamadas de repositórios em uma única transação.
+// Os repositórios usam a transação carregada no ctx recebido por fn.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
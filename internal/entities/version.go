@@ -0,0 +1,10 @@
+package entities
+
+import "errors"
+
+var (
+	// ErrVersionConflict indica que o registro mudou desde a versão que o cliente leu.
+	ErrVersionConflict = errors.New("o registro foi alterado por outra requisição; recarregue e tente novamente")
+	// ErrVersionRequired indica uma atualização sem If-Match nem version.
+	ErrVersionRequired = errors.New("informe a versão lida no cabeçalho If-Match ou no campo version")
+)
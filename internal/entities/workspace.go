@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Papéis de um membro: owner administra membros e convites, editor altera o
+// conteúdo e viewer só consulta.
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleEditor = "editor"
+	WorkspaceRoleViewer = "viewer"
+)
+
+var (
+	ErrWorkspaceNotFound    = errors.New("workspace não encontrado")
+	ErrWorkspaceForbidden   = errors.New("seu papel no workspace não permite esta alteração")
+	ErrPersonalWorkspace    = errors.New("o workspace pessoal não pode ser compartilhado nem excluído")
+	ErrLastWorkspaceOwner   = errors.New("o workspace precisa continuar com ao menos um owner")
+	ErrMemberNotFound       = errors.New("membro não encontrado no workspace")
+	ErrInvitationNotFound   = errors.New("convite não encontrado ou expirado")
+	ErrInvalidWorkspaceRole = errors.New("papel inválido: use owner, editor ou viewer")
+	ErrInvalidWorkspaceName = errors.New("nome do workspace é obrigatório e deve ter até 50 caracteres")
+	ErrInvalidInviteEmail   = errors.New("email do convite inválido")
+)
+
+func IsWorkspaceRole(role string) bool {
+	switch role {
+	case WorkspaceRoleOwner, WorkspaceRoleEditor, WorkspaceRoleViewer:
+		return true
+	}
+	return false
+}
+
+type Workspace struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Personal bool   `json:"personal"`
+	// papel de quem consultou
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewWorkspace(name string) (*Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > 50 {
+		return nil, ErrInvalidWorkspaceName
+	}
+	return &Workspace{
+		Id:   uuid.New().String(),
+		Name: name,
+	}, nil
+}
+
+type WorkspaceMember struct {
+	UserId    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WorkspaceInvitation struct {
+	Id            string    `json:"id"`
+	WorkspaceId   string    `json:"workspace_id"`
+	WorkspaceName string    `json:"workspace_name"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	InvitedBy     string    `json:"invited_by"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func NewWorkspaceInvitation(workspaceId, email, role string) (*WorkspaceInvitation, error) {
+	email = strings.TrimSpace(email)
+	if email == "" || len(email) > 50 || !isEmailValid(email) {
+		return nil, ErrInvalidInviteEmail
+	}
+	if !IsWorkspaceRole(role) {
+		return nil, ErrInvalidWorkspaceRole
+	}
+	return &WorkspaceInvitation{
+		Id:          uuid.New().String(),
+		WorkspaceId: workspaceId,
+		Email:       email,
+		Role:        role,
+	}, nil
+}
+
+// WorkspaceRepository recebe sempre quem está agindo e confere o papel dele no
+// próprio SQL, devolvendo ErrWorkspaceForbidden quando não basta.
+type WorkspaceRepository interface {
+	Create(ctx context.Context, userId string, workspace *Workspace) (string, error)
+	FindAll(ctx context.Context, userId string) ([]*Workspace, error)
+	FindByID(ctx context.Context, userId, id string) (*Workspace, error)
+	Update(ctx context.Context, userId string, workspace *Workspace) error
+	Delete(ctx context.Context, userId, id string) error
+	FindMembers(ctx context.Context, userId, workspaceId string) ([]*WorkspaceMember, error)
+	UpdateMemberRole(ctx context.Context, userId, workspaceId, memberId, role string) error
+	RemoveMember(ctx context.Context, userId, workspaceId, memberId string) error
+	// CreateInvitation substitui o convite pendente para o mesmo email e preenche ExpiresAt.
+	CreateInvitation(ctx context.Context, userId string, invitation *WorkspaceInvitation) (string, error)
+	FindInvitationsByEmail(ctx context.Context, email string) ([]*WorkspaceInvitation, error)
+	AcceptInvitation(ctx context.Context, userId, email, invitationId string) (string, error)
+	// CoMembers lista o próprio usuário e todos que dividem algum workspace com ele:
+	// quem precisa deixar de ver em cache o que ele acabou de gravar.
+	CoMembers(ctx context.Context, userId string) ([]string, error)
+}
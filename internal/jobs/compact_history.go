@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// HistoryCompactor mantém as partições do histórico e resume em Granularity o que
+// passou de Retention.
+type HistoryCompactor struct {
+	Repository      entities.HistoryRepository
+	Retention       time.Duration
+	Granularity     string
+	PartitionsAhead int
+	Interval        time.Duration
+}
+
+func NewHistoryCompactor(repository entities.HistoryRepository, retention time.Duration, granularity string, partitionsAhead int, interval time.Duration) *HistoryCompactor {
+	return &HistoryCompactor{
+		Repository:      repository,
+		Retention:       retention,
+		Granularity:     granularity,
+		PartitionsAhead: partitionsAhead,
+		Interval:        interval,
+	}
+}
+
+// Run cria as partições que faltam e resume o histórico na partida e depois a cada
+// Interval, até ctx terminar. Entre instâncias o resumo é serializado no banco.
+func (c *HistoryCompactor) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := c.Repository.EnsurePartitions(ctx, c.PartitionsAhead)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Printf("Erro ao criar partições do histórico: %v", err)
+		case created > 0:
+			log.Printf("Partições do histórico criadas: %d", created)
+		}
+
+		periods, err := c.Repository.Rollup(ctx, c.Retention, c.Granularity)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Printf("Erro ao resumir o histórico: %v", err)
+		case periods > 0:
+			log.Printf("Histórico resumido: %d períodos gravados", periods)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// CareTaskGenerator cria as tarefas de irrigação e adubação que vencem dentro de
+// Horizon e cancela as pendentes que o agendamento não prevê mais.
+type CareTaskGenerator struct {
+	Repository entities.CareRepository
+	Horizon    time.Duration
+	Interval   time.Duration
+}
+
+func NewCareTaskGenerator(repository entities.CareRepository, horizon, interval time.Duration) *CareTaskGenerator {
+	return &CareTaskGenerator{
+		Repository: repository,
+		Horizon:    horizon,
+		Interval:   interval,
+	}
+}
+
+// Run gera na partida e depois a cada Interval, até ctx terminar. Rodar em várias
+// instâncias é seguro: o banco aceita uma tarefa ativa por cuidado e janela.
+func (g *CareTaskGenerator) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.Interval)
+	defer ticker.Stop()
+
+	for {
+		created, cancelled, err := g.generate(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Printf("Erro ao gerar tarefas de cuidado: %v", err)
+		case created+cancelled > 0:
+			log.Printf("Tarefas de cuidado: %d criadas, %d canceladas", created, cancelled)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *CareTaskGenerator) generate(ctx context.Context) (int64, int64, error) {
+	orphans, err := g.Repository.CancelOrphanTasks(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	cancelled := int64(len(orphans))
+
+	subjects, err := g.Repository.FindAutoTaskSubjects(ctx)
+	if err != nil {
+		return 0, cancelled, err
+	}
+
+	var created int64
+	now := time.Now()
+	limit := now.Add(g.Horizon)
+	for _, subject := range subjects {
+		windows := make(map[string]time.Time)
+		tasks := make([]*entities.CareTask, 0)
+		for _, item := range subject.Schedule(now) {
+			windows[item.CareType] = item.Window()
+			if item.NextDue.After(limit) {
+				continue
+			}
+			task, err := entities.NewCareTask(subject, item)
+			if err != nil {
+				log.Printf("Erro ao montar tarefa de cuidado de %s: %v", subject.Id, err)
+				continue
+			}
+			tasks = append(tasks, task)
+		}
+
+		c, x, err := g.Repository.SyncTasks(ctx, subject, windows, tasks)
+		if err != nil {
+			if ctx.Err() != nil {
+				return created, cancelled, err
+			}
+			// um item com problema não impede os outros
+			log.Printf("Erro ao sincronizar tarefas de cuidado de %s: %v", subject.Id, err)
+			continue
+		}
+		created += c
+		cancelled += x
+	}
+	return created, cancelled, nil
+}
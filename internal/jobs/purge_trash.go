@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// TrashPurger apaga de vez o que está na lixeira há mais que Retention.
+type TrashPurger struct {
+	Repository entities.TrashRepository
+	Retention  time.Duration
+	Interval   time.Duration
+}
+
+func NewTrashPurger(repository entities.TrashRepository, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		Repository: repository,
+		Retention:  retention,
+		Interval:   interval,
+	}
+}
+
+// Run expurga na partida e depois a cada Interval, até ctx terminar. Rodar em
+// várias instâncias ao mesmo tempo é seguro: o DELETE só pega o que ainda existe.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.Repository.Purge(ctx, p.Retention)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Printf("Erro ao expurgar a lixeira: %v", err)
+		case purged > 0:
+			log.Printf("Lixeira expurgada: %d registros removidos", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
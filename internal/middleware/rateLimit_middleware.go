@@ -1,31 +1,33 @@
 package middleware
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/go-redis/redis_rate/v9"
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/config"
 )
 
-var (
-	ctx = context.Background()
-)
+func RateLimitMiddleware(redisClient *redis.Client, cfg config.RateLimitConfig) func(http.Handler) http.Handler {
+	limiter := redis_rate.NewLimiter(redisClient)
+	localLimiter := cache.NewLocalLimiter(cfg.RequestsPerMinute)
 
-func RateLimitMiddleware(redisClient *redis.Client) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			limiter := redis_rate.NewLimiter(redisClient)
 			clientIP := r.RemoteAddr
 
 			rateLimitKey := fmt.Sprintf("ratelimit:%s", clientIP)
 			jailKey := fmt.Sprintf("jail:%s", clientIP)
 
-			jailStatus, err := redisClient.Get(ctx, jailKey).Result()
+			jailStatus, err := redisClient.Get(r.Context(), jailKey).Result()
 			if err != nil && err != redis.Nil {
-				http.Error(w, "Erro no sistema de Jail", http.StatusInternalServerError)
+				logRedisFailure(err)
+				localRateLimit(localLimiter, clientIP, w, r, next)
 				return
 			}
 
@@ -34,20 +36,21 @@ func RateLimitMiddleware(redisClient *redis.Client) func(http.Handler) http.Hand
 				return
 			}
 
-			res, err := limiter.Allow(ctx, rateLimitKey, redis_rate.PerMinute(100))
+			res, err := limiter.Allow(r.Context(), rateLimitKey, redis_rate.PerMinute(cfg.RequestsPerMinute))
 			if err != nil {
-				http.Error(w, "Erro no Rate Limiter", http.StatusInternalServerError)
+				logRedisFailure(err)
+				localRateLimit(localLimiter, clientIP, w, r, next)
 				return
 			}
 
 			if res.Allowed == 0 {
 				failCountKey := fmt.Sprintf("failcount:%s", clientIP)
-				failCount, _ := redisClient.Incr(ctx, failCountKey).Result()
+				failCount, _ := redisClient.Incr(r.Context(), failCountKey).Result()
 
-				redisClient.Expire(ctx, failCountKey, time.Minute)
+				redisClient.Expire(r.Context(), failCountKey, cfg.FailWindow)
 
-				if failCount > 5 {
-					redisClient.Set(ctx, jailKey, "1", 5*time.Minute)
+				if failCount > int64(cfg.MaxFailures) {
+					redisClient.Set(r.Context(), jailKey, "1", cfg.JailDuration)
 					http.Error(w, "Você está bloqueado temporariamente devido a excesso de requisições", http.StatusForbidden)
 					return
 				}
@@ -57,9 +60,27 @@ func RateLimitMiddleware(redisClient *redis.Client) func(http.Handler) http.Hand
 				return
 			}
 
-			redisClient.Del(ctx, fmt.Sprintf("failcount:%s", clientIP))
+			redisClient.Del(r.Context(), fmt.Sprintf("failcount:%s", clientIP))
 
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// localRateLimit mantém algum controle por instância enquanto o Redis está fora.
+func localRateLimit(limiter *cache.LocalLimiter, clientIP string, w http.ResponseWriter, r *http.Request, next http.Handler) {
+	allowed, retryAfter := limiter.Allow(clientIP)
+	if !allowed {
+		w.Header().Set("Retry-After", fmt.Sprintf("%d", int(retryAfter/time.Second)+1))
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		return
+	}
+	next.ServeHTTP(w, r)
+}
+
+func logRedisFailure(err error) {
+	// com o circuito aberto o erro se repete a cada requisição, então só registra a falha real
+	if !errors.Is(err, cache.ErrCircuitOpen) {
+		log.Printf("Erro no Rate Limiter, usando limite local: %v", err)
+	}
+}
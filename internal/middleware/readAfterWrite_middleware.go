@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/database"
+)
+
+// ReadAfterWriteMiddleware mantém no primário as leituras de quem escreveu há menos de window,
+// para o cliente não deixar de ver o que acabou de gravar por causa do atraso da réplica.
+// A marca fica no cache compartilhado, então vale para qualquer instância.
+func ReadAfterWriteMiddleware(store *cache.Fallback, window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := stickyKey(r)
+			_, err := store.Get(r.Context(), key)
+			sticky := err == nil
+
+			ctx := database.WithRouting(r.Context(), sticky, func() {
+				if err := store.Set(context.WithoutCancel(r.Context()), key, []byte("1"), window); err != nil {
+					log.Printf("Erro ao marcar leitura no primário: %v", err)
+				}
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// stickyKey identifica o cliente pelo token, ou pelo endereço nas rotas sem autenticação.
+func stickyKey(r *http.Request) string {
+	identity := r.Header.Get("Authorization")
+	if identity == "" {
+		identity = r.RemoteAddr
+	}
+	sum := sha1.Sum([]byte(identity))
+	return "sticky:" + hex.EncodeToString(sum[:])
+}
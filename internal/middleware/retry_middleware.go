@@ -1,25 +1,44 @@
 package middleware
 
 import (
+	"bytes"
 	"errors"
 	"log"
 	"net/http"
 	"time"
 )
 
+// RetryMiddleware repete leituras que falharam com 5xx. Escritas não são repetidas para
+// não gravar duas vezes, e 504 é final porque o TimeoutMiddleware já gastou o prazo.
 func RetryMiddleware(maxRetries int, delay time.Duration) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !retryableMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			var err error
 			for i := 0; i <= maxRetries; i++ {
-				recorder := newResponseRecorder(w)
+				recorder := newResponseRecorder()
 				next.ServeHTTP(recorder, r)
 
+				// prazo estourado ou cliente desconectado não melhora com outra tentativa
+				if r.Context().Err() != nil || recorder.statusCode == http.StatusGatewayTimeout {
+					recorder.flush(w)
+					return
+				}
+
 				if recorder.statusCode >= 500 {
 					err = errors.New("requisição falhou, tentando novamente")
-					time.Sleep(delay)
+					select {
+					case <-time.After(delay):
+					case <-r.Context().Done():
+						return
+					}
 					continue
 				}
+				recorder.flush(w)
 				return
 			}
 			log.Printf("Falha após %d tentativas: %v", maxRetries, err)
@@ -28,11 +47,47 @@ func RetryMiddleware(maxRetries int, delay time.Duration) func(http.Handler) htt
 	}
 }
 
+func retryableMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// responseRecorder guarda a resposta de uma tentativa; só a que vale chega ao cliente.
 type responseRecorder struct {
-	http.ResponseWriter
-	statusCode int
+	header      http.Header
+	body        bytes.Buffer
+	statusCode  int
+	wroteHeader bool
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header), statusCode: http.StatusOK}
+}
+
+func (rr *responseRecorder) Header() http.Header {
+	return rr.header
 }
 
-func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
-	return &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if rr.wroteHeader {
+		return
+	}
+	rr.wroteHeader = true
+	rr.statusCode = statusCode
+}
+
+func (rr *responseRecorder) Write(data []byte) (int, error) {
+	rr.wroteHeader = true
+	return rr.body.Write(data)
+}
+
+func (rr *responseRecorder) flush(w http.ResponseWriter) {
+	for key, values := range rr.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(rr.statusCode)
+	w.Write(rr.body.Bytes())
 }
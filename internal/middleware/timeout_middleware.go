@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/utils"
+)
+
+// TimeoutMiddleware coloca um prazo no contexto da requisição, que segue até as consultas do banco.
+// Se o prazo estourar antes da resposta, devolve 504 e descarta o que o handler escrever depois.
+func TimeoutMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			tw := &timeoutWriter{header: make(http.Header), statusCode: http.StatusOK}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+			case <-ctx.Done():
+			}
+
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.closed = true
+
+			// o handler pode ter terminado com o erro do banco causado pelo prazo
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				utils.JsonResponse(w, http.StatusGatewayTimeout, "error", "Tempo limite da requisição excedido", nil)
+				return
+			}
+			if ctx.Err() != nil {
+				// cliente desistiu, não há para quem responder
+				return
+			}
+
+			for key, values := range tw.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(tw.statusCode)
+			w.Write(tw.body.Bytes())
+		})
+	}
+}
+
+// timeoutWriter guarda a resposta até o handler terminar dentro do prazo.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	statusCode  int
+	wroteHeader bool
+	closed      bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(statusCode int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.closed || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.statusCode = statusCode
+}
+
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.closed {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.body.Write(data)
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// CachedCareRepository não guarda os cuidados, que dependem da hora da consulta;
+// só invalida as listagens de tarefas quando o gerador cria ou cancela alguma.
+type CachedCareRepository struct {
+	entities.CareRepository
+	Cache      *cache.ReadThrough
+	Workspaces entities.WorkspaceRepository
+}
+
+func NewCachedCareRepository(repository entities.CareRepository, c *cache.ReadThrough, workspaces entities.WorkspaceRepository) *CachedCareRepository {
+	return &CachedCareRepository{
+		CareRepository: repository,
+		Cache:          c,
+		Workspaces:     workspaces,
+	}
+}
+
+func (r *CachedCareRepository) SyncTasks(ctx context.Context, subject *entities.CareSubject, windows map[string]time.Time, tasks []*entities.CareTask) (int64, int64, error) {
+	created, cancelled, err := r.CareRepository.SyncTasks(ctx, subject, windows, tasks)
+	if err != nil {
+		return 0, 0, err
+	}
+	if created+cancelled > 0 {
+		invalidate(ctx, r.Cache, userTags(audienceOf(ctx, r.Workspaces, subject.UserId), "tasks")...)
+	}
+	return created, cancelled, nil
+}
+
+func (r *CachedCareRepository) CancelOrphanTasks(ctx context.Context) ([]string, error) {
+	owners, err := r.CareRepository.CancelOrphanTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(owners))
+	for _, owner := range owners {
+		if seen[owner] {
+			continue
+		}
+		seen[owner] = true
+		invalidate(ctx, r.Cache, userTags(audienceOf(ctx, r.Workspaces, owner), "tasks")...)
+	}
+	return owners, nil
+}
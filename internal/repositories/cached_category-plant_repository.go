@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// CachedCategoryPlantRepository guarda as leituras de categorias de plantas no
+// cache; o resto do comportamento é o do repositório embutido.
+type CachedCategoryPlantRepository struct {
+	entities.CategoryPlantRepository
+	Cache      *cache.ReadThrough
+	ListTTL    time.Duration
+	ItemTTL    time.Duration
+	Workspaces entities.WorkspaceRepository
+}
+
+func NewCachedCategoryPlantRepository(repository entities.CategoryPlantRepository, c *cache.ReadThrough, listTTL, itemTTL time.Duration, workspaces entities.WorkspaceRepository) *CachedCategoryPlantRepository {
+	return &CachedCategoryPlantRepository{
+		CategoryPlantRepository: repository,
+		Cache:                   c,
+		ListTTL:                 listTTL,
+		ItemTTL:                 itemTTL,
+		Workspaces:              workspaces,
+	}
+}
+
+// categoryPlantWriteTags inclui plantas e jardins porque as duas listagens trazem as categorias.
+func categoryPlantWriteTags(userIds ...string) []string {
+	return userTags(userIds, "categories_plants", "plants", "gardens")
+}
+
+func (r *CachedCategoryPlantRepository) Create(ctx context.Context, category *entities.CategoryPlant) (string, error) {
+	id, err := r.CategoryPlantRepository.Create(ctx, category)
+	if err != nil {
+		return "", err
+	}
+	invalidate(ctx, r.Cache, userTags(audienceOf(ctx, r.Workspaces, category.UserId), "categories_plants")...)
+	return id, nil
+}
+
+func (r *CachedCategoryPlantRepository) Update(ctx context.Context, category *entities.CategoryPlant) error {
+	if err := r.CategoryPlantRepository.Update(ctx, category); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, categoryPlantWriteTags(audienceOf(ctx, r.Workspaces, category.UserId)...)...)
+	return nil
+}
+
+func (r *CachedCategoryPlantRepository) Delete(ctx context.Context, userId, id string) error {
+	if err := r.CategoryPlantRepository.Delete(ctx, userId, id); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, categoryPlantWriteTags(audienceOf(ctx, r.Workspaces, userId)...)...)
+	return nil
+}
+
+func (r *CachedCategoryPlantRepository) FindById(ctx context.Context, userId, id string) (*entities.CategoryPlant, error) {
+	return readThrough(ctx, r.Cache, cacheKey("categories_plants:id", userId, id), []string{userTag("categories_plants", userId)}, r.ItemTTL,
+		func(ctx context.Context) (*entities.CategoryPlant, error) {
+			return r.CategoryPlantRepository.FindById(ctx, userId, id)
+		})
+}
+
+func (r *CachedCategoryPlantRepository) FindAll(ctx context.Context, userId string) ([]*entities.CategoryPlant, error) {
+	return readThrough(ctx, r.Cache, cacheKey("categories_plants:all", userId), []string{userTag("categories_plants", userId)}, r.ListTTL,
+		func(ctx context.Context) ([]*entities.CategoryPlant, error) {
+			return r.CategoryPlantRepository.FindAll(ctx, userId)
+		})
+}
+
+func (r *CachedCategoryPlantRepository) FindByName(ctx context.Context, userId, name string) ([]*entities.CategoryPlant, error) {
+	return readThrough(ctx, r.Cache, cacheKey("categories_plants:name", userId, name), []string{userTag("categories_plants", userId)}, r.ListTTL,
+		func(ctx context.Context) ([]*entities.CategoryPlant, error) {
+			return r.CategoryPlantRepository.FindByName(ctx, userId, name)
+		})
+}
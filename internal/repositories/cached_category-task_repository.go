@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// CachedCategoryTaskRepository guarda as leituras de categorias de tarefas no
+// cache; o resto do comportamento é o do repositório embutido.
+type CachedCategoryTaskRepository struct {
+	entities.CategoryTaskRepository
+	Cache      *cache.ReadThrough
+	ListTTL    time.Duration
+	ItemTTL    time.Duration
+	Workspaces entities.WorkspaceRepository
+}
+
+func NewCachedCategoryTaskRepository(repository entities.CategoryTaskRepository, c *cache.ReadThrough, listTTL, itemTTL time.Duration, workspaces entities.WorkspaceRepository) *CachedCategoryTaskRepository {
+	return &CachedCategoryTaskRepository{
+		CategoryTaskRepository: repository,
+		Cache:                  c,
+		ListTTL:                listTTL,
+		ItemTTL:                itemTTL,
+		Workspaces:             workspaces,
+	}
+}
+
+// categoryTaskWriteTags inclui tarefas porque a listagem delas traz as categorias.
+func categoryTaskWriteTags(userIds ...string) []string {
+	return userTags(userIds, "categories_tasks", "tasks")
+}
+
+func (r *CachedCategoryTaskRepository) Create(ctx context.Context, category *entities.CategoryTask) (string, error) {
+	id, err := r.CategoryTaskRepository.Create(ctx, category)
+	if err != nil {
+		return "", err
+	}
+	invalidate(ctx, r.Cache, userTags(audienceOf(ctx, r.Workspaces, category.UserId), "categories_tasks")...)
+	return id, nil
+}
+
+func (r *CachedCategoryTaskRepository) Update(ctx context.Context, category *entities.CategoryTask) error {
+	if err := r.CategoryTaskRepository.Update(ctx, category); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, categoryTaskWriteTags(audienceOf(ctx, r.Workspaces, category.UserId)...)...)
+	return nil
+}
+
+func (r *CachedCategoryTaskRepository) Delete(ctx context.Context, userId, id string) error {
+	if err := r.CategoryTaskRepository.Delete(ctx, userId, id); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, categoryTaskWriteTags(audienceOf(ctx, r.Workspaces, userId)...)...)
+	return nil
+}
+
+func (r *CachedCategoryTaskRepository) FindById(ctx context.Context, userId, id string) (*entities.CategoryTask, error) {
+	return readThrough(ctx, r.Cache, cacheKey("categories_tasks:id", userId, id), []string{userTag("categories_tasks", userId)}, r.ItemTTL,
+		func(ctx context.Context) (*entities.CategoryTask, error) {
+			return r.CategoryTaskRepository.FindById(ctx, userId, id)
+		})
+}
+
+func (r *CachedCategoryTaskRepository) FindAll(ctx context.Context, userId string) ([]*entities.CategoryTask, error) {
+	return readThrough(ctx, r.Cache, cacheKey("categories_tasks:all", userId), []string{userTag("categories_tasks", userId)}, r.ListTTL,
+		func(ctx context.Context) ([]*entities.CategoryTask, error) {
+			return r.CategoryTaskRepository.FindAll(ctx, userId)
+		})
+}
+
+func (r *CachedCategoryTaskRepository) FindByName(ctx context.Context, userId, name string) ([]*entities.CategoryTask, error) {
+	return readThrough(ctx, r.Cache, cacheKey("categories_tasks:name", userId, name), []string{userTag("categories_tasks", userId)}, r.ListTTL,
+		func(ctx context.Context) ([]*entities.CategoryTask, error) {
+			return r.CategoryTaskRepository.FindByName(ctx, userId, name)
+		})
+}
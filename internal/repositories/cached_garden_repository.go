@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// CachedGardenRepository guarda as leituras de jardins no cache; o resto do
+// comportamento é o do repositório embutido.
+type CachedGardenRepository struct {
+	entities.GardenRepository
+	Cache      *cache.ReadThrough
+	TTL        time.Duration
+	Workspaces entities.WorkspaceRepository
+}
+
+func NewCachedGardenRepository(repository entities.GardenRepository, c *cache.ReadThrough, ttl time.Duration, workspaces entities.WorkspaceRepository) *CachedGardenRepository {
+	return &CachedGardenRepository{
+		GardenRepository: repository,
+		Cache:            c,
+		TTL:              ttl,
+		Workspaces:       workspaces,
+	}
+}
+
+type gardenPage = *entities.Page[*entities.GardenOutputDTO]
+
+func (r *CachedGardenRepository) list(ctx context.Context, userId, key string, load func(ctx context.Context) (gardenPage, error)) (gardenPage, error) {
+	return readThrough(ctx, r.Cache, key, []string{userTag("gardens", userId)}, r.TTL, load)
+}
+
+// gardenWriteTags inclui plantas e tarefas porque as duas listagens filtram ou exibem jardins
+// vinculados, e colheitas porque as estatísticas agrupam pelo nome do jardim.
+func gardenWriteTags(userIds ...string) []string {
+	return userTags(userIds, "gardens", "plants", "tasks", "harvests")
+}
+
+func (r *CachedGardenRepository) Create(ctx context.Context, garden *entities.Garden) (string, error) {
+	id, err := r.GardenRepository.Create(ctx, garden)
+	if err != nil {
+		return "", err
+	}
+	invalidate(ctx, r.Cache, gardenWriteTags(audienceOf(ctx, r.Workspaces, garden.UserId)...)...)
+	return id, nil
+}
+
+func (r *CachedGardenRepository) Update(ctx context.Context, garden *entities.Garden) error {
+	if err := r.GardenRepository.Update(ctx, garden); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, gardenWriteTags(audienceOf(ctx, r.Workspaces, garden.UserId)...)...)
+	return nil
+}
+
+func (r *CachedGardenRepository) Delete(ctx context.Context, userId, id string) error {
+	if err := r.GardenRepository.Delete(ctx, userId, id); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, append(gardenWriteTags(audienceOf(ctx, r.Workspaces, userId)...), "history_gardens:"+id)...)
+	return nil
+}
+
+func (r *CachedGardenRepository) FindByID(ctx context.Context, userId, id string) (*entities.GardenOutputDTO, error) {
+	return readThrough(ctx, r.Cache, cacheKey("gardens:id", userId, id), []string{userTag("gardens", userId)}, r.TTL,
+		func(ctx context.Context) (*entities.GardenOutputDTO, error) {
+			return r.GardenRepository.FindByID(ctx, userId, id)
+		})
+}
+
+func (r *CachedGardenRepository) FindByName(ctx context.Context, userId, name string, page entities.PageRequest) (gardenPage, error) {
+	return r.list(ctx, userId, cacheKey("gardens:name", userId, name, page), func(ctx context.Context) (gardenPage, error) {
+		return r.GardenRepository.FindByName(ctx, userId, name, page)
+	})
+}
+
+func (r *CachedGardenRepository) FindByLocation(ctx context.Context, userId, location string, page entities.PageRequest) (gardenPage, error) {
+	return r.list(ctx, userId, cacheKey("gardens:location", userId, location, page), func(ctx context.Context) (gardenPage, error) {
+		return r.GardenRepository.FindByLocation(ctx, userId, location, page)
+	})
+}
+
+func (r *CachedGardenRepository) FindByCategoryName(ctx context.Context, userId, categoryName string, page entities.PageRequest) (gardenPage, error) {
+	return r.list(ctx, userId, cacheKey("gardens:category", userId, categoryName, page), func(ctx context.Context) (gardenPage, error) {
+		return r.GardenRepository.FindByCategoryName(ctx, userId, categoryName, page)
+	})
+}
+
+func (r *CachedGardenRepository) FindAll(ctx context.Context, userId string, page entities.PageRequest) (gardenPage, error) {
+	return r.list(ctx, userId, cacheKey("gardens:all", userId, page), func(ctx context.Context) (gardenPage, error) {
+		return r.GardenRepository.FindAll(ctx, userId, page)
+	})
+}
+
+func (r *CachedGardenRepository) Search(ctx context.Context, userId string, filter entities.GardenFilter, page entities.PageRequest) (gardenPage, error) {
+	return r.list(ctx, userId, cacheKey("gardens:search", userId, filter, page), func(ctx context.Context) (gardenPage, error) {
+		return r.GardenRepository.Search(ctx, userId, filter, page)
+	})
+}
+
+func (r *CachedGardenRepository) CreateHistory(ctx context.Context, history *entities.HistoryGarden) error {
+	if err := r.GardenRepository.CreateHistory(ctx, history); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, "history_gardens:"+history.GardenID)
+	return nil
+}
+
+// o acesso ao histórico depende do workspace, então a página fica também na tag de jardins do usuário
+func (r *CachedGardenRepository) FindAllHistoryByGardenID(ctx context.Context, userId, gardenID string, page entities.PageRequest) (*entities.Page[*entities.HistoryGarden], error) {
+	tags := []string{"history_gardens:" + gardenID, userTag("gardens", userId)}
+	return readThrough(ctx, r.Cache, cacheKey("history_gardens", userId, gardenID, page), tags, r.TTL,
+		func(ctx context.Context) (*entities.Page[*entities.HistoryGarden], error) {
+			return r.GardenRepository.FindAllHistoryByGardenID(ctx, userId, gardenID, page)
+		})
+}
+
+func (r *CachedGardenRepository) SetAutoTasks(ctx context.Context, userId, id string, enabled bool) error {
+	if err := r.GardenRepository.SetAutoTasks(ctx, userId, id, enabled); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, gardenWriteTags(audienceOf(ctx, r.Workspaces, userId)...)...)
+	return nil
+}
+
+func (r *CachedGardenRepository) LogCare(ctx context.Context, care *entities.CareLog, ids []string) error {
+	if err := r.GardenRepository.LogCare(ctx, care, ids); err != nil {
+		return err
+	}
+	tags := gardenWriteTags(audienceOf(ctx, r.Workspaces, care.UserId)...)
+	for _, id := range ids {
+		tags = append(tags, "history_gardens:"+id)
+	}
+	invalidate(ctx, r.Cache, tags...)
+	return nil
+}
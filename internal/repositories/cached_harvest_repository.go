@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// CachedHarvestRepository guarda as listagens e as estatísticas de colheitas no cache.
+type CachedHarvestRepository struct {
+	entities.HarvestRepository
+	Cache      *cache.ReadThrough
+	TTL        time.Duration
+	Workspaces entities.WorkspaceRepository
+}
+
+func NewCachedHarvestRepository(repository entities.HarvestRepository, c *cache.ReadThrough, ttl time.Duration, workspaces entities.WorkspaceRepository) *CachedHarvestRepository {
+	return &CachedHarvestRepository{
+		HarvestRepository: repository,
+		Cache:             c,
+		TTL:               ttl,
+		Workspaces:        workspaces,
+	}
+}
+
+func (r *CachedHarvestRepository) Create(ctx context.Context, harvest *entities.Harvest, plantStatus string) error {
+	if err := r.HarvestRepository.Create(ctx, harvest, plantStatus); err != nil {
+		return err
+	}
+	users := audienceOf(ctx, r.Workspaces, harvest.UserId)
+	tags := userTags(users, "harvests")
+	if plantStatus != "" {
+		tags = plantWriteTags(users...)
+	}
+	invalidate(ctx, r.Cache, tags...)
+	return nil
+}
+
+func (r *CachedHarvestRepository) Delete(ctx context.Context, userId, id string) error {
+	if err := r.HarvestRepository.Delete(ctx, userId, id); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, userTags(audienceOf(ctx, r.Workspaces, userId), "harvests")...)
+	return nil
+}
+
+func (r *CachedHarvestRepository) FindAll(ctx context.Context, userId string, filter entities.HarvestFilter, page entities.PageRequest) (*entities.Page[*entities.Harvest], error) {
+	return readThrough(ctx, r.Cache, cacheKey("harvests:all", userId, filter, page), []string{userTag("harvests", userId)}, r.TTL,
+		func(ctx context.Context) (*entities.Page[*entities.Harvest], error) {
+			return r.HarvestRepository.FindAll(ctx, userId, filter, page)
+		})
+}
+
+// Stats também depende dos nomes das espécies, que mudam com a importação do catálogo.
+func (r *CachedHarvestRepository) Stats(ctx context.Context, userId string, query entities.HarvestStatsQuery) ([]*entities.HarvestStats, error) {
+	tags := []string{userTag("harvests", userId), speciesTag}
+	return readThrough(ctx, r.Cache, cacheKey("harvests:stats", userId, query), tags, r.TTL,
+		func(ctx context.Context) ([]*entities.HarvestStats, error) {
+			return r.HarvestRepository.Stats(ctx, userId, query)
+		})
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// CachedPlantRepository guarda as leituras de plantas no cache; o resto do
+// comportamento é o do repositório embutido.
+type CachedPlantRepository struct {
+	entities.PlantRepository
+	Cache      *cache.ReadThrough
+	TTL        time.Duration
+	Workspaces entities.WorkspaceRepository
+}
+
+func NewCachedPlantRepository(repository entities.PlantRepository, c *cache.ReadThrough, ttl time.Duration, workspaces entities.WorkspaceRepository) *CachedPlantRepository {
+	return &CachedPlantRepository{
+		PlantRepository: repository,
+		Cache:           c,
+		TTL:             ttl,
+		Workspaces:      workspaces,
+	}
+}
+
+type plantPage = *entities.Page[*entities.PlantWithCategory]
+
+func (r *CachedPlantRepository) list(ctx context.Context, userId, key string, load func(ctx context.Context) (plantPage, error)) (plantPage, error) {
+	return readThrough(ctx, r.Cache, key, []string{userTag("plants", userId)}, r.TTL, load)
+}
+
+// plantWriteTags inclui jardins e tarefas porque as duas listagens trazem as plantas vinculadas,
+// e colheitas porque elas somem junto com a planta que vai para a lixeira.
+func plantWriteTags(userIds ...string) []string {
+	return userTags(userIds, "plants", "gardens", "tasks", "harvests")
+}
+
+func (r *CachedPlantRepository) Create(ctx context.Context, plant *entities.Plant) (string, error) {
+	id, err := r.PlantRepository.Create(ctx, plant)
+	if err != nil {
+		return "", err
+	}
+	invalidate(ctx, r.Cache, plantWriteTags(audienceOf(ctx, r.Workspaces, plant.UserId)...)...)
+	return id, nil
+}
+
+func (r *CachedPlantRepository) Update(ctx context.Context, plant *entities.Plant) error {
+	if err := r.PlantRepository.Update(ctx, plant); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, plantWriteTags(audienceOf(ctx, r.Workspaces, plant.UserId)...)...)
+	return nil
+}
+
+func (r *CachedPlantRepository) Delete(ctx context.Context, userId, id string) error {
+	if err := r.PlantRepository.Delete(ctx, userId, id); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, append(plantWriteTags(audienceOf(ctx, r.Workspaces, userId)...), "history_plants:"+id)...)
+	return nil
+}
+
+func (r *CachedPlantRepository) FindByID(ctx context.Context, userId, id string) (*entities.PlantWithCategory, error) {
+	return readThrough(ctx, r.Cache, cacheKey("plants:id", userId, id), []string{userTag("plants", userId)}, r.TTL,
+		func(ctx context.Context) (*entities.PlantWithCategory, error) {
+			return r.PlantRepository.FindByID(ctx, userId, id)
+		})
+}
+
+func (r *CachedPlantRepository) FindBySpeciesName(ctx context.Context, userId, speciesName string, page entities.PageRequest) (plantPage, error) {
+	return r.list(ctx, userId, cacheKey("plants:species", userId, speciesName, page), func(ctx context.Context) (plantPage, error) {
+		return r.PlantRepository.FindBySpeciesName(ctx, userId, speciesName, page)
+	})
+}
+
+func (r *CachedPlantRepository) FindByCategoryName(ctx context.Context, userId, categoryName string, page entities.PageRequest) (plantPage, error) {
+	return r.list(ctx, userId, cacheKey("plants:category", userId, categoryName, page), func(ctx context.Context) (plantPage, error) {
+		return r.PlantRepository.FindByCategoryName(ctx, userId, categoryName, page)
+	})
+}
+
+func (r *CachedPlantRepository) FindByName(ctx context.Context, userId, name string, page entities.PageRequest) (plantPage, error) {
+	return r.list(ctx, userId, cacheKey("plants:name", userId, name, page), func(ctx context.Context) (plantPage, error) {
+		return r.PlantRepository.FindByName(ctx, userId, name, page)
+	})
+}
+
+func (r *CachedPlantRepository) FindAll(ctx context.Context, userId string, page entities.PageRequest) (plantPage, error) {
+	return r.list(ctx, userId, cacheKey("plants:all", userId, page), func(ctx context.Context) (plantPage, error) {
+		return r.PlantRepository.FindAll(ctx, userId, page)
+	})
+}
+
+func (r *CachedPlantRepository) Search(ctx context.Context, userId string, filter entities.PlantFilter, page entities.PageRequest) (plantPage, error) {
+	return r.list(ctx, userId, cacheKey("plants:search", userId, filter, page), func(ctx context.Context) (plantPage, error) {
+		return r.PlantRepository.Search(ctx, userId, filter, page)
+	})
+}
+
+func (r *CachedPlantRepository) CreateHistory(ctx context.Context, history *entities.HistoryPlant) error {
+	if err := r.PlantRepository.CreateHistory(ctx, history); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, "history_plants:"+history.PlantID)
+	return nil
+}
+
+// o acesso ao histórico depende do workspace, então a página fica também na tag de plantas do usuário
+func (r *CachedPlantRepository) FindAllHistoryByPlantID(ctx context.Context, userId, plantID string, page entities.PageRequest) (*entities.Page[*entities.HistoryPlant], error) {
+	tags := []string{"history_plants:" + plantID, userTag("plants", userId)}
+	return readThrough(ctx, r.Cache, cacheKey("history_plants", userId, plantID, page), tags, r.TTL,
+		func(ctx context.Context) (*entities.Page[*entities.HistoryPlant], error) {
+			return r.PlantRepository.FindAllHistoryByPlantID(ctx, userId, plantID, page)
+		})
+}
+
+func (r *CachedPlantRepository) FindGrowthSamples(ctx context.Context, plantID string) ([]*entities.GrowthSample, error) {
+	return readThrough(ctx, r.Cache, cacheKey("history_plants:growth", plantID), []string{"history_plants:" + plantID}, r.TTL,
+		func(ctx context.Context) ([]*entities.GrowthSample, error) {
+			return r.PlantRepository.FindGrowthSamples(ctx, plantID)
+		})
+}
+
+func (r *CachedPlantRepository) SetAutoTasks(ctx context.Context, userId, id string, enabled bool) error {
+	if err := r.PlantRepository.SetAutoTasks(ctx, userId, id, enabled); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, plantWriteTags(audienceOf(ctx, r.Workspaces, userId)...)...)
+	return nil
+}
+
+func (r *CachedPlantRepository) LogCare(ctx context.Context, care *entities.CareLog, ids []string) error {
+	if err := r.PlantRepository.LogCare(ctx, care, ids); err != nil {
+		return err
+	}
+	tags := plantWriteTags(audienceOf(ctx, r.Workspaces, care.UserId)...)
+	for _, id := range ids {
+		tags = append(tags, "history_plants:"+id)
+	}
+	invalidate(ctx, r.Cache, tags...)
+	return nil
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// Tags usadas pelos repositórios com cache. Cada escrita invalida a tag da
+// própria entidade e as das listagens que a exibem ou filtram por ela.
+func userTag(entity, userId string) string {
+	return entity + ":" + userId
+}
+
+// userTags gera a tag de cada entidade para cada usuário.
+func userTags(userIds []string, names ...string) []string {
+	tags := make([]string, 0, len(userIds)*len(names))
+	for _, userId := range userIds {
+		for _, entity := range names {
+			tags = append(tags, userTag(entity, userId))
+		}
+	}
+	return tags
+}
+
+// audienceOf lista quem pode ter em cache o que userId acabou de gravar: ele e
+// quem divide algum workspace com ele. Se a consulta falhar, invalida ao menos
+// o cache do próprio usuário.
+func audienceOf(ctx context.Context, workspaces entities.WorkspaceRepository, userId string) []string {
+	users, err := workspaces.CoMembers(ctx, userId)
+	if err != nil {
+		log.Printf("Erro ao buscar membros dos workspaces de %s: %v", userId, err)
+		return []string{userId}
+	}
+	return users
+}
+
+// cacheKey identifica uma leitura pelo método e pelos parâmetros, resumidos
+// em hash para a chave não depender de tamanho ou caracteres do filtro.
+func cacheKey(name string, params ...interface{}) string {
+	raw, _ := json.Marshal(params)
+	sum := sha1.Sum(raw)
+	return name + ":" + hex.EncodeToString(sum[:])
+}
+
+// readThrough lê pelo cache, exceto dentro de uma transação, que precisa ver
+// as próprias escritas ainda não confirmadas. Logo depois de uma invalidação a
+// leitura vai ao primário, para o cache não guardar o atraso da réplica.
+func readThrough[T any](ctx context.Context, c *cache.ReadThrough, key string, tags []string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	if inTx(ctx) {
+		return load(ctx)
+	}
+	return cache.Fetch(ctx, c, key, tags, ttl, func(ctx context.Context) (T, error) {
+		if cache.Fresh(ctx) {
+			ctx = database.WithRouting(ctx, true, nil)
+		}
+		return load(ctx)
+	})
+}
+
+// invalidate só descarta as tags depois do commit, para nenhuma leitura
+// concorrente gravar no cache um estado que ainda pode sofrer rollback.
+func invalidate(ctx context.Context, c *cache.ReadThrough, tags ...string) {
+	ctx = context.WithoutCancel(ctx)
+	afterCommit(ctx, func() {
+		c.Invalidate(ctx, tags...)
+	})
+}
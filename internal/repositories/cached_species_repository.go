@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// speciesTag é global porque o catálogo de espécies é o mesmo para todos os usuários.
+const speciesTag = "species"
+
+// CachedSpeciesRepository guarda as leituras do catálogo de espécies no cache.
+type CachedSpeciesRepository struct {
+	entities.SpecieRepository
+	Cache *cache.ReadThrough
+	TTL   time.Duration
+}
+
+func NewCachedSpeciesRepository(repository entities.SpecieRepository, c *cache.ReadThrough, ttl time.Duration) *CachedSpeciesRepository {
+	return &CachedSpeciesRepository{
+		SpecieRepository: repository,
+		Cache:            c,
+		TTL:              ttl,
+	}
+}
+
+// Invalidate descarta o catálogo em cache; deve ser chamado por quem altera a tabela species.
+func (r *CachedSpeciesRepository) Invalidate(ctx context.Context) {
+	invalidate(ctx, r.Cache, speciesTag)
+}
+
+func (r *CachedSpeciesRepository) Upsert(ctx context.Context, species []*entities.Specie) error {
+	if err := r.SpecieRepository.Upsert(ctx, species); err != nil {
+		return err
+	}
+	r.Invalidate(ctx)
+	return nil
+}
+
+func (r *CachedSpeciesRepository) FindAll(ctx context.Context) ([]*entities.Specie, error) {
+	return readThrough(ctx, r.Cache, cacheKey("species:all"), []string{speciesTag}, r.TTL,
+		func(ctx context.Context) ([]*entities.Specie, error) {
+			return r.SpecieRepository.FindAll(ctx)
+		})
+}
+
+func (r *CachedSpeciesRepository) FindById(ctx context.Context, id string) (*entities.Specie, error) {
+	return readThrough(ctx, r.Cache, cacheKey("species:id", id), []string{speciesTag}, r.TTL,
+		func(ctx context.Context) (*entities.Specie, error) {
+			return r.SpecieRepository.FindById(ctx, id)
+		})
+}
+
+func (r *CachedSpeciesRepository) FindByName(ctx context.Context, commonName string) ([]*entities.Specie, error) {
+	return readThrough(ctx, r.Cache, cacheKey("species:name", commonName), []string{speciesTag}, r.TTL,
+		func(ctx context.Context) ([]*entities.Specie, error) {
+			return r.SpecieRepository.FindByName(ctx, commonName)
+		})
+}
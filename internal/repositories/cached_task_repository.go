@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// CachedTaskRepository guarda as leituras de tarefas no cache; o resto do
+// comportamento é o do repositório embutido.
+type CachedTaskRepository struct {
+	entities.TaskRepository
+	Cache      *cache.ReadThrough
+	TTL        time.Duration
+	Workspaces entities.WorkspaceRepository
+}
+
+func NewCachedTaskRepository(repository entities.TaskRepository, c *cache.ReadThrough, ttl time.Duration, workspaces entities.WorkspaceRepository) *CachedTaskRepository {
+	return &CachedTaskRepository{
+		TaskRepository: repository,
+		Cache:          c,
+		TTL:            ttl,
+		Workspaces:     workspaces,
+	}
+}
+
+type taskPage = *entities.Page[*entities.TaskOutputDTO]
+
+func (r *CachedTaskRepository) list(ctx context.Context, userId, key string, load func(ctx context.Context) (taskPage, error)) (taskPage, error) {
+	return readThrough(ctx, r.Cache, key, []string{userTag("tasks", userId)}, r.TTL, load)
+}
+
+func (r *CachedTaskRepository) Create(ctx context.Context, task *entities.Task) (string, error) {
+	id, err := r.TaskRepository.Create(ctx, task)
+	if err != nil {
+		return "", err
+	}
+	invalidate(ctx, r.Cache, userTags(audienceOf(ctx, r.Workspaces, task.UserId), "tasks")...)
+	return id, nil
+}
+
+func (r *CachedTaskRepository) Update(ctx context.Context, task *entities.Task) error {
+	if err := r.TaskRepository.Update(ctx, task); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, userTags(audienceOf(ctx, r.Workspaces, task.UserId), "tasks")...)
+	return nil
+}
+
+func (r *CachedTaskRepository) Delete(ctx context.Context, userId, id string) error {
+	if err := r.TaskRepository.Delete(ctx, userId, id); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, userTags(audienceOf(ctx, r.Workspaces, userId), "tasks")...)
+	return nil
+}
+
+func (r *CachedTaskRepository) FindByID(ctx context.Context, userId, id string) (*entities.TaskOutputDTO, error) {
+	return readThrough(ctx, r.Cache, cacheKey("tasks:id", userId, id), []string{userTag("tasks", userId)}, r.TTL,
+		func(ctx context.Context) (*entities.TaskOutputDTO, error) {
+			return r.TaskRepository.FindByID(ctx, userId, id)
+		})
+}
+
+func (r *CachedTaskRepository) FindByCategoryName(ctx context.Context, userId, categoryName string, page entities.PageRequest) (taskPage, error) {
+	return r.list(ctx, userId, cacheKey("tasks:category", userId, categoryName, page), func(ctx context.Context) (taskPage, error) {
+		return r.TaskRepository.FindByCategoryName(ctx, userId, categoryName, page)
+	})
+}
+
+func (r *CachedTaskRepository) FindByName(ctx context.Context, userId, name string, page entities.PageRequest) (taskPage, error) {
+	return r.list(ctx, userId, cacheKey("tasks:name", userId, name, page), func(ctx context.Context) (taskPage, error) {
+		return r.TaskRepository.FindByName(ctx, userId, name, page)
+	})
+}
+
+func (r *CachedTaskRepository) FindAll(ctx context.Context, userId string, page entities.PageRequest) (taskPage, error) {
+	return r.list(ctx, userId, cacheKey("tasks:all", userId, page), func(ctx context.Context) (taskPage, error) {
+		return r.TaskRepository.FindAll(ctx, userId, page)
+	})
+}
+
+func (r *CachedTaskRepository) FindByStatus(ctx context.Context, userId, status string, page entities.PageRequest) (taskPage, error) {
+	return r.list(ctx, userId, cacheKey("tasks:status", userId, status, page), func(ctx context.Context) (taskPage, error) {
+		return r.TaskRepository.FindByStatus(ctx, userId, status, page)
+	})
+}
+
+func (r *CachedTaskRepository) FindByUrgencyLevel(ctx context.Context, userId string, urgencyLevel int, page entities.PageRequest) (taskPage, error) {
+	return r.list(ctx, userId, cacheKey("tasks:urgency", userId, urgencyLevel, page), func(ctx context.Context) (taskPage, error) {
+		return r.TaskRepository.FindByUrgencyLevel(ctx, userId, urgencyLevel, page)
+	})
+}
+
+func (r *CachedTaskRepository) Search(ctx context.Context, userId string, filter entities.TaskFilter, page entities.PageRequest) (taskPage, error) {
+	return r.list(ctx, userId, cacheKey("tasks:search", userId, filter, page), func(ctx context.Context) (taskPage, error) {
+		return r.TaskRepository.Search(ctx, userId, filter, page)
+	})
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// CachedTrashRepository não guarda a lixeira, só invalida as listagens que
+// voltam a exibir o item restaurado, com as mesmas tags da exclusão.
+type CachedTrashRepository struct {
+	entities.TrashRepository
+	Cache      *cache.ReadThrough
+	Workspaces entities.WorkspaceRepository
+}
+
+func NewCachedTrashRepository(repository entities.TrashRepository, c *cache.ReadThrough, workspaces entities.WorkspaceRepository) *CachedTrashRepository {
+	return &CachedTrashRepository{
+		TrashRepository: repository,
+		Cache:           c,
+		Workspaces:      workspaces,
+	}
+}
+
+func (r *CachedTrashRepository) Restore(ctx context.Context, userId, itemType, id string) error {
+	if err := r.TrashRepository.Restore(ctx, userId, itemType, id); err != nil {
+		return err
+	}
+
+	users := audienceOf(ctx, r.Workspaces, userId)
+	var tags []string
+	switch itemType {
+	case entities.TrashTypePlant:
+		tags = append(plantWriteTags(users...), "history_plants:"+id)
+	case entities.TrashTypeGarden:
+		tags = append(gardenWriteTags(users...), "history_gardens:"+id)
+	case entities.TrashTypeTask:
+		tags = userTags(users, "tasks")
+	case entities.TrashTypeCategoryPlant:
+		tags = categoryPlantWriteTags(users...)
+	case entities.TrashTypeCategoryTask:
+		tags = categoryTaskWriteTags(users...)
+	}
+	invalidate(ctx, r.Cache, tags...)
+	return nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// workspaceContentTags são as listagens que mudam quando alguém entra ou sai de
+// um workspace, porque passa a ver (ou deixa de ver) o conteúdo dele.
+func workspaceContentTags(userIds ...string) []string {
+	return userTags(userIds, "plants", "gardens", "tasks", "categories_plants", "categories_tasks", "harvests")
+}
+
+// CachedWorkspaceRepository não guarda workspaces no cache, só invalida o
+// conteúdo de quem ganhou ou perdeu acesso a eles.
+type CachedWorkspaceRepository struct {
+	entities.WorkspaceRepository
+	Cache *cache.ReadThrough
+}
+
+func NewCachedWorkspaceRepository(repository entities.WorkspaceRepository, c *cache.ReadThrough) *CachedWorkspaceRepository {
+	return &CachedWorkspaceRepository{
+		WorkspaceRepository: repository,
+		Cache:               c,
+	}
+}
+
+func (r *CachedWorkspaceRepository) Delete(ctx context.Context, userId, id string) error {
+	// os membros precisam ser lidos antes, o workspace some com eles
+	members, err := r.WorkspaceRepository.FindMembers(ctx, userId, id)
+	if err != nil {
+		return err
+	}
+	if err := r.WorkspaceRepository.Delete(ctx, userId, id); err != nil {
+		return err
+	}
+
+	userIds := make([]string, len(members))
+	for i, member := range members {
+		userIds[i] = member.UserId
+	}
+	invalidate(ctx, r.Cache, workspaceContentTags(userIds...)...)
+	return nil
+}
+
+func (r *CachedWorkspaceRepository) RemoveMember(ctx context.Context, userId, workspaceId, memberId string) error {
+	if err := r.WorkspaceRepository.RemoveMember(ctx, userId, workspaceId, memberId); err != nil {
+		return err
+	}
+	invalidate(ctx, r.Cache, workspaceContentTags(memberId)...)
+	return nil
+}
+
+func (r *CachedWorkspaceRepository) AcceptInvitation(ctx context.Context, userId, email, invitationId string) (string, error) {
+	workspaceId, err := r.WorkspaceRepository.AcceptInvitation(ctx, userId, email, invitationId)
+	if err != nil {
+		return "", err
+	}
+	invalidate(ctx, r.Cache, workspaceContentTags(userId)...)
+	return workspaceId, nil
+}
@@ -0,0 +1,211 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type CareRepositoryImpl struct {
+	DB *database.Cluster
+}
+
+func NewCareRepository(db *database.Cluster) *CareRepositoryImpl {
+	return &CareRepositoryImpl{DB: db}
+}
+
+// careSubjectsQuery lê plantas e jardins numa consulta só; %[1]s e %[2]s filtram
+// plantas (p) e jardins (g). As plantas levam os pesos da espécie; jardins não têm
+// espécie e ficam com o peso neutro em $1.
+const careSubjectsQuery = `
+	SELECT 'plant', p.id, p.plant_name, p.user_id, p.workspace_id, p.last_irrigation, p.last_fertilization,
+		p.irrigation_week, p.fertilization_week,
+		COALESCE(s.irrigation_weight, $1), COALESCE(s.fertilization_weight, $1)
+	FROM plants p
+	LEFT JOIN species s ON s.id = p.species_id
+	WHERE p.deleted_at IS NULL AND %[1]s
+
+	UNION ALL
+	SELECT 'garden', g.id, g.garden_name, g.user_id, g.workspace_id, g.last_irrigation, g.last_fertilization,
+		g.irrigation_week, g.fertilization_week, $1, $1
+	FROM gardens g
+	WHERE g.deleted_at IS NULL AND %[2]s`
+
+func (r *CareRepositoryImpl) FindSubjects(ctx context.Context, userId string) ([]*entities.CareSubject, error) {
+	query := fmt.Sprintf(careSubjectsQuery, memberOf("p.workspace_id", "$2"), memberOf("g.workspace_id", "$2"))
+	return r.findSubjects(ctx, reader(ctx, r.DB), query, entities.NeutralCareWeight, userId)
+}
+
+func (r *CareRepositoryImpl) FindAutoTaskSubjects(ctx context.Context) ([]*entities.CareSubject, error) {
+	query := fmt.Sprintf(careSubjectsQuery, "p.auto_tasks", "g.auto_tasks")
+	// o gerador compara com as tarefas do primário logo em seguida
+	return r.findSubjects(ctx, primary(ctx, r.DB), query, entities.NeutralCareWeight)
+}
+
+func (r *CareRepositoryImpl) findSubjects(ctx context.Context, db executor, query string, args ...interface{}) ([]*entities.CareSubject, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cuidados: %w", err)
+	}
+	defer rows.Close()
+
+	subjects := make([]*entities.CareSubject, 0)
+	for rows.Next() {
+		var s entities.CareSubject
+		err := rows.Scan(&s.Type, &s.Id, &s.Name, &s.UserId, &s.WorkspaceId, &s.LastIrrigation, &s.LastFertilization,
+			&s.IrrigationWeek, &s.FertilizationWeek, &s.IrrigationWeight, &s.FertilizationWeight)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler cuidados: %w", err)
+		}
+		subjects = append(subjects, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler cuidados: %w", err)
+	}
+	return subjects, nil
+}
+
+func (r *CareRepositoryImpl) SyncTasks(ctx context.Context, subject *entities.CareSubject, windows map[string]time.Time, tasks []*entities.CareTask) (int64, int64, error) {
+	careTypes := make([]string, 0, len(windows))
+	days := make([]time.Time, 0, len(windows))
+	for careType, day := range windows {
+		careTypes = append(careTypes, careType)
+		days = append(days, day)
+	}
+
+	var created, cancelled int64
+	err := withTx(ctx, r.DB, func(ctx context.Context) error {
+		// o que ficou de uma janela antiga perdeu o sentido: o cuidado foi feito ou a frequência mudou
+		query := `
+			UPDATE tasks SET task_status = 'cancelled', version = version + 1
+			WHERE care_subject_id = $1 AND task_status = 'pending' AND deleted_at IS NULL
+				AND (care_type, care_window) NOT IN (SELECT * FROM unnest($2::text[], $3::date[]))`
+		result, err := conn(ctx, r.DB).Exec(ctx, query, subject.Id, careTypes, days)
+		if err != nil {
+			return fmt.Errorf("erro ao cancelar tarefas de cuidado: %w", err)
+		}
+		cancelled = result.RowsAffected()
+
+		for _, careTask := range tasks {
+			inserted, err := r.createTask(ctx, subject, careTask)
+			if err != nil {
+				return err
+			}
+			if inserted {
+				created++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return created, cancelled, nil
+}
+
+// createTask grava a tarefa se ainda não houver outra ativa na mesma janela.
+func (r *CareRepositoryImpl) createTask(ctx context.Context, subject *entities.CareSubject, careTask *entities.CareTask) (bool, error) {
+	task := careTask.Task
+	query := `
+		INSERT INTO tasks (id, task_name, task_description, date_task, urgency_level, task_status, user_id, workspace_id,
+			care_subject_type, care_subject_id, care_type, care_window)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (care_subject_id, care_type, care_window) WHERE care_type IS NOT NULL AND task_status <> 'cancelled'
+		DO NOTHING`
+	result, err := conn(ctx, r.DB).Exec(ctx, query, task.Id, task.Name, task.Description, task.TaskDate, task.UrgencyLevel,
+		task.TaskStatus, task.UserId, task.WorkspaceId, subject.Type, subject.Id, careTask.CareType, careTask.Window)
+	if err != nil {
+		return false, fmt.Errorf("erro ao criar tarefa de cuidado: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	var batch links
+	taskLinks(&batch, task)
+	if err := batch.send(ctx, conn(ctx, r.DB)); err != nil {
+		return false, fmt.Errorf("erro ao vincular tarefa de cuidado: %w", err)
+	}
+	return true, nil
+}
+
+func (r *CareRepositoryImpl) CancelOrphanTasks(ctx context.Context) ([]string, error) {
+	query := `
+		UPDATE tasks t SET task_status = 'cancelled', version = t.version + 1
+		WHERE t.care_type IS NOT NULL AND t.task_status = 'pending' AND t.deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM plants p
+				WHERE t.care_subject_type = 'plant' AND p.id = t.care_subject_id AND p.deleted_at IS NULL AND p.auto_tasks
+				UNION ALL
+				SELECT 1 FROM gardens g
+				WHERE t.care_subject_type = 'garden' AND g.id = t.care_subject_id AND g.deleted_at IS NULL AND g.auto_tasks
+			)
+		RETURNING t.user_id`
+	rows, err := conn(ctx, r.DB).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao cancelar tarefas de cuidado: %w", err)
+	}
+	owners, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao cancelar tarefas de cuidado: %w", err)
+	}
+	return owners, nil
+}
+
+// cancelCareTasks cancela as tarefas pendentes geradas para a planta ou o jardim id,
+// usado quando o item vai para a lixeira ou desliga a geração.
+func cancelCareTasks(ctx context.Context, db *database.Cluster, subjectType, id string) error {
+	query := `UPDATE tasks SET task_status = 'cancelled', version = version + 1
+		WHERE care_subject_type = $1 AND care_subject_id = $2 AND task_status = 'pending' AND deleted_at IS NULL`
+	if _, err := conn(ctx, db).Exec(ctx, query, subjectType, id); err != nil {
+		return fmt.Errorf("erro ao cancelar tarefas de cuidado: %w", err)
+	}
+	return nil
+}
+
+// logCare roda query, que aplica o cuidado e grava o histórico de cada item de ids
+// devolvendo os ids gravados. Os parâmetros são $1 ids, $2 usuário, $3 tipo, $4 data,
+// $5 quantidade, $6 altura, $7 largura e $8 observações. Chamar dentro de withTx.
+func logCare(ctx context.Context, db *database.Cluster, table, subjectType, query string, care *entities.CareLog, ids []string) error {
+	rows, err := conn(ctx, db).Query(ctx, query, ids, care.UserId, care.CareType, care.PerformedAt,
+		care.Amount, care.Height, care.Width, care.Notes)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar cuidado: %w", err)
+	}
+	logged, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("erro ao registrar cuidado: %w", err)
+	}
+
+	if len(logged) < len(ids) {
+		done := make(map[string]bool, len(logged))
+		for _, id := range logged {
+			done[id] = true
+		}
+		for _, id := range ids {
+			if done[id] {
+				continue
+			}
+			if err := writeDenied(ctx, db, table, id, care.UserId); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %s", entities.ErrCareSubjectNotFound, id)
+		}
+	}
+
+	if care.CareType != entities.CareTypeIrrigation && care.CareType != entities.CareTypeFertilization {
+		return nil
+	}
+	// o cuidado registrado é o que a tarefa gerada pedia
+	query = `UPDATE tasks SET task_status = 'completed', version = version + 1
+		WHERE care_subject_type = $1 AND care_subject_id = ANY($2::uuid[]) AND care_type = $3
+			AND task_status = 'pending' AND deleted_at IS NULL`
+	if _, err := conn(ctx, db).Exec(ctx, query, subjectType, ids, care.CareType); err != nil {
+		return fmt.Errorf("erro ao concluir tarefas de cuidado: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+)
+
+// whereBuilder junta condições com AND numerando os placeholders na ordem em que
+// são adicionadas. As condições são sempre literais do código; valores vindos do
+// cliente entram só como argumentos.
+type whereBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+func newWhere(cond string, args ...interface{}) *whereBuilder {
+	w := &whereBuilder{}
+	w.add(cond, args...)
+	return w
+}
+
+// add troca cada "?" de cond pelo próximo placeholder posicional.
+func (w *whereBuilder) add(cond string, args ...interface{}) {
+	var b strings.Builder
+	next := len(w.args) + 1
+	for _, ch := range cond {
+		if ch == '?' {
+			fmt.Fprintf(&b, "$%d", next)
+			next++
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	w.conds = append(w.conds, b.String())
+	w.args = append(w.args, args...)
+}
+
+func (w *whereBuilder) sql() string {
+	return strings.Join(w.conds, " AND ")
+}
@@ -2,709 +2,322 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
-	"log"
 
-	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lucasBiazon/botany-back/internal/database"
 	"github.com/lucasBiazon/botany-back/internal/entities"
 )
 
 type GardenRepositoryImpl struct {
-	DB *sql.DB
+	DB *database.Cluster
 }
 
-func NewGardenRepository(db *sql.DB) *GardenRepositoryImpl {
+func NewGardenRepository(db *database.Cluster) *GardenRepositoryImpl {
 	return &GardenRepositoryImpl{DB: db}
 }
 
 func (r *GardenRepositoryImpl) Create(ctx context.Context, garden *entities.Garden) (string, error) {
-	idParsed, err := uuid.Parse(garden.Id)
-	if err != nil {
-		return "", err
-	}
-	userIdParsed, err := uuid.Parse(garden.UserId)
+	workspaceId, err := writableWorkspace(ctx, r.DB, garden.UserId, garden.WorkspaceId)
 	if err != nil {
 		return "", err
 	}
+	garden.WorkspaceId = workspaceId
 
-	query := `INSERT INTO gardens (id, user_id, garden_name, garden_description,  garden_location
+	query := `INSERT INTO gardens (id, user_id, garden_name, garden_description, garden_location,
 	total_area, currenting_height, currenting_width, planting_date, last_irrigation, last_fertilization, 
-	irrigation_week, sun_exposure, fertilization_week)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+	irrigation_week, sun_exposure, fertilization_week, workspace_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
 
-	_, err = r.DB.ExecContext(ctx, query, idParsed, userIdParsed, garden.GardenName, garden.GardenDescription, garden.GardenLocation,
-		garden.TotalArea, garden.CurrentingHeight, garden.CurrentingWidth, garden.PlantingDate, garden.LastIrrigation, garden.LastFertilization,
-		garden.IrrigationWeek, garden.SunExposure, garden.FertilizationWeek)
+	err = withTx(ctx, r.DB, func(ctx context.Context) error {
+		_, err := conn(ctx, r.DB).Exec(ctx, query, garden.Id, garden.UserId, garden.GardenName, garden.GardenDescription, garden.GardenLocation,
+			garden.TotalArea, garden.CurrentingHeight, garden.CurrentingWidth, garden.PlantingDate, garden.LastIrrigation, garden.LastFertilization,
+			garden.IrrigationWeek, garden.SunExposure, garden.FertilizationWeek, garden.WorkspaceId)
+		if err != nil {
+			return err
+		}
+		var batch links
+		gardenLinks(&batch, garden)
+		return batch.send(ctx, conn(ctx, r.DB))
+	})
 	if err != nil {
 		return "", err
 	}
 
-	if len(garden.CategoriesPlantId) > 0 {
-		query = `INSERT INTO garden_categories (id, garden_id, category_id) VALUES ($1, $2, $3)`
-		for _, categoryPlantId := range garden.CategoriesPlantId {
-			categoryId, err := uuid.Parse(categoryPlantId)
-			if err != nil {
-				return "", err
-			}
-			_, err = r.DB.ExecContext(ctx, query, uuid.New(), idParsed, categoryId)
-			if err != nil {
-				return "", err
-			}
-		}
-	}
+	return garden.Id, nil
+}
 
-	if len(garden.PlantsId) > 0 {
-		query = `INSERT INTO garden_plant (id, garden_id, plant_id) VALUES ($1, $2, $3)`
-		for _, plantId := range garden.PlantsId {
-			plantIdParsed, err := uuid.Parse(plantId)
-			if err != nil {
-				return "", err
-			}
-			_, err = r.DB.ExecContext(ctx, query, uuid.New(), idParsed, plantIdParsed)
-			if err != nil {
-				return "", err
-			}
-		}
-	}
+// gardenLinks enfileira as categorias e plantas vinculadas ao jardim.
+func gardenLinks(batch *links, garden *entities.Garden) {
+	batch.add("garden_categories", "garden_id", "category_id", garden.Id, garden.CategoriesPlantId)
+	batch.add("garden_plant", "garden_id", "plant_id", garden.Id, garden.PlantsId)
+}
 
-	return garden.Id, nil
+// gardenColumns lista as colunas de um jardim com os relacionamentos agregados;
+// alias é a tabela ou CTE de onde o jardim vem.
+func gardenColumns(alias string) string {
+	return fmt.Sprintf(`
+		%[1]s.id,
+		%[1]s.user_id,
+		%[1]s.workspace_id,
+		%[1]s.garden_name,
+		%[1]s.garden_description,
+		%[1]s.garden_location,
+		%[1]s.total_area,
+		%[1]s.currenting_height,
+		%[1]s.currenting_width,
+		%[1]s.planting_date,
+		%[1]s.last_irrigation,
+		%[1]s.last_fertilization,
+		%[1]s.irrigation_week,
+		%[1]s.sun_exposure,
+		%[1]s.fertilization_week,
+		%[1]s.created_at,
+		%[1]s.updated_at,
+		%[1]s.version,
+		%[1]s.auto_tasks,
+		%[2]s AS categories,
+		%[3]s AS plants`,
+		alias,
+		relation(alias+".id", "garden_categories", "garden_id", "category_id", "categories_plants", "'id', x.id, 'name', x.category_name"),
+		relation(alias+".id", "garden_plant", "garden_id", "plant_id", "plants", "'id', x.id, 'plant_name', x.plant_name"),
+	)
 }
 
-func (r *GardenRepositoryImpl) FindByID(ctx context.Context, userId, id string) (*entities.GardenOutputDTO, error) {
-	idParse, err := uuid.Parse(id)
-	if err != nil {
+// scanGarden lê uma linha de gardenColumns; extra recebe as colunas que vierem depois.
+func scanGarden(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*entities.GardenOutputDTO, error) {
+	var garden entities.GardenOutputDTO
+	var categories, plants []byte
+
+	dest := []interface{}{
+		&garden.Id,
+		&garden.UserId,
+		&garden.WorkspaceId,
+		&garden.GardenName,
+		&garden.GardenDescription,
+		&garden.GardenLocation,
+		&garden.TotalArea,
+		&garden.CurrentingHeight,
+		&garden.CurrentingWidth,
+		&garden.PlantingDate,
+		&garden.LastIrrigation,
+		&garden.LastFertilization,
+		&garden.IrrigationWeek,
+		&garden.SunExposure,
+		&garden.FertilizationWeek,
+		&garden.CreatedAt,
+		&garden.UpdatedAt,
+		&garden.Version,
+		&garden.AutoTasks,
+		&categories,
+		&plants,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
+
+	if err := scanRelation(categories, &garden.CategoriesPlant); err != nil {
 		return nil, err
 	}
-
-	query := `SELECT 
-		g.id AS garden_id,
-		g.garden_name,
-		g.garden_description,
-		g.garden_location,
-		g.total_area,
-		g.currenting_height,
-		g.currenting_width,
-		g.planting_date,
-		g.last_irrigation,
-		g.last_fertilization,
-		g.irrigation_week,
-		g.sun_exposure,
-		g.fertilization_week,
-		g.created_at AS garden_created_at,
-		g.updated_at AS garden_updated_at,
-		gc.category_id,
-		cp.category_name,
-		gp.plant_id,
-		p.plant_name
-	FROM 
-		gardens g
-	LEFT JOIN 
-		garden_categories gc ON g.id = gc.garden_id
-	LEFT JOIN 
-		categories_plants cp ON gc.category_id = cp.id
-	LEFT JOIN 
-		garden_plant gp ON g.id = gp.garden_id
-	LEFT JOIN 
-		plants p ON gp.plant_id = p.id
-	WHERE 
-		g.user_id = $1 AND g.id = $2;`
-
-	rows, err := r.DB.QueryContext(ctx, query, userIdParse, idParse)
-	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
+	if err := scanRelation(plants, &garden.Plants); err != nil {
+		return nil, err
 	}
-	defer rows.Close()
-
-	var garden *entities.GardenOutputDTO
-	var categories []entities.CategoryPlant
-	var plants []entities.Plant
-
-	for rows.Next() {
-		var category entities.CategoryPlant
-		var plant entities.Plant
-		tempGarden := entities.GardenOutputDTO{}
-
-		err := rows.Scan(
-			&tempGarden.Id,
-			&tempGarden.GardenName,
-			&tempGarden.GardenDescription,
-			&tempGarden.GardenLocation,
-			&tempGarden.TotalArea,
-			&tempGarden.CurrentingHeight,
-			&tempGarden.CurrentingWidth,
-			&tempGarden.PlantingDate,
-			&tempGarden.LastIrrigation,
-			&tempGarden.LastFertilization,
-			&tempGarden.IrrigationWeek,
-			&tempGarden.SunExposure,
-			&tempGarden.FertilizationWeek,
-			&tempGarden.CreatedAt,
-			&tempGarden.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&plant.Id,
-			&plant.PlantName,
-		)
-		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
-		}
-
-		if garden == nil {
-			garden = &tempGarden
-		}
+	return &garden, nil
+}
 
-		if category.Id != "" {
-			categories = append(categories, category)
-		}
-		if plant.Id != "" {
-			plants = append(plants, plant)
-		}
-	}
+func (r *GardenRepositoryImpl) FindByID(ctx context.Context, userId, id string) (*entities.GardenOutputDTO, error) {
 
-	if garden != nil {
-		garden.CategoriesPlant = categories
-		garden.Plants = plants
-	}
+	query := `SELECT ` + gardenColumns("g") + `
+	FROM gardens g
+	WHERE ` + memberOf("g.workspace_id", "$1") + ` AND g.id = $2 AND g.deleted_at IS NULL`
 
-	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
+	garden, err := scanGarden(reader(ctx, r.DB).QueryRow(ctx, query, userId, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil // Nenhum registro encontrado
 	}
-
-	if garden == nil {
-		log.Println("Nenhum jardim encontrado para os parâmetros fornecidos.")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar jardim: %w", err)
 	}
 	return garden, nil
 }
 
-func (r *GardenRepositoryImpl) FindAll(ctx context.Context, userId string) ([]*entities.GardenOutputDTO, error) {
-	userIdParse, err := uuid.Parse(userId)
+var gardenSortFields = map[string]sortField{
+	"name":          {column: "g.garden_name", cast: "text"},
+	"created_at":    {column: "g.created_at", cast: "timestamp"},
+	"planting_date": {column: "g.planting_date", cast: "timestamp"},
+	"location":      {column: "g.garden_location", cast: "text"},
+}
+
+// listGardens pagina os jardins visíveis ao usuário que atendem filter; $1 é sempre o user_id.
+func (r *GardenRepositoryImpl) listGardens(ctx context.Context, filter string, args []interface{}, page entities.PageRequest) (*entities.Page[*entities.GardenOutputDTO], error) {
+	ks, err := newKeyset(page, gardenSortFields, "created_at", "g.id")
 	if err != nil {
 		return nil, err
 	}
-	query := `SELECT 
-		g.id AS garden_id,
-		g.garden_name,
-		g.garden_description,
-		g.garden_location,
-		g.total_area,
-		g.currenting_height,
-		g.currenting_width,
-		g.planting_date,
-		g.last_irrigation,
-		g.last_fertilization,
-		g.irrigation_week,
-		g.sun_exposure,
-		g.fertilization_week,
-		g.created_at AS garden_created_at,
-		g.updated_at AS garden_updated_at,
-		gc.category_id,
-		cp.category_name,
-		gp.plant_id,
-		p.plant_name
-	FROM 
-		gardens g
-	LEFT JOIN 
-		garden_categories gc ON g.id = gc.garden_id
-	LEFT JOIN 
-		categories_plants cp ON gc.category_id = cp.id
-	LEFT JOIN 
-		garden_plant gp ON g.id = gp.garden_id
-	LEFT JOIN 
-		plants p ON gp.plant_id = p.id
-	WHERE 
-		 g.user_id = $1;`
-
-	rows, err := r.DB.QueryContext(ctx, query, userIdParse)
-	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
-	}
-	defer rows.Close()
-
-	gardenMap := make(map[string]*entities.GardenOutputDTO)
-
-	for rows.Next() {
-		var category entities.CategoryPlant
-		var plant entities.Plant
-		tempGarden := entities.GardenOutputDTO{}
-
-		err := rows.Scan(
-			&tempGarden.Id,
-			&tempGarden.GardenName,
-			&tempGarden.GardenDescription,
-			&tempGarden.GardenLocation,
-			&tempGarden.TotalArea,
-			&tempGarden.CurrentingHeight,
-			&tempGarden.CurrentingWidth,
-			&tempGarden.PlantingDate,
-			&tempGarden.LastIrrigation,
-			&tempGarden.LastFertilization,
-			&tempGarden.IrrigationWeek,
-			&tempGarden.SunExposure,
-			&tempGarden.FertilizationWeek,
-			&tempGarden.CreatedAt,
-			&tempGarden.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&plant.Id,
-			&plant.PlantName,
-		)
-		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
-		}
-
-		existingGarden, exists := gardenMap[tempGarden.Id]
-		if !exists {
-			existingGarden = &tempGarden
-			existingGarden.CategoriesPlant = []entities.CategoryPlant{}
-			existingGarden.Plants = []entities.Plant{}
-			gardenMap[tempGarden.Id] = existingGarden
-		}
-
-		if category.Id != "" {
-			existingGarden.CategoriesPlant = append(existingGarden.CategoriesPlant, category)
-		}
-		if plant.Id != "" {
-			existingGarden.Plants = append(existingGarden.Plants, plant)
-		}
-	}
 
-	var gardens []*entities.GardenOutputDTO
-	for _, garden := range gardenMap {
-		gardens = append(gardens, garden)
-	}
-
-	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
-	}
-
-	if len(gardens) == 0 {
-		log.Println("Nenhum jardim encontrado para os parâmetros fornecidos.")
-	}
-
-	return gardens, nil
-}
-
-func (r *GardenRepositoryImpl) FindByLocation(ctx context.Context, userId, gardenLocation string) ([]*entities.GardenOutputDTO, error) {
-	userIdParse, err := uuid.Parse(userId)
+	total, err := count(ctx, reader(ctx, r.DB), "gardens", "g", filter, args)
 	if err != nil {
 		return nil, err
 	}
 
-	query := `SELECT 
-		g.id AS garden_id,
-		g.garden_name,
-		g.garden_description,
-		g.garden_location,
-		g.total_area,
-		g.currenting_height,
-		g.currenting_width,
-		g.planting_date,
-		g.last_irrigation,
-		g.last_fertilization,
-		g.irrigation_week,
-		g.sun_exposure,
-		g.fertilization_week,
-		g.created_at AS garden_created_at,
-		g.updated_at AS garden_updated_at,
-		gc.category_id,
-		cp.category_name,
-		gp.plant_id,
-		p.plant_name
-	FROM 
-		gardens g
-	LEFT JOIN 
-		garden_categories gc ON g.id = gc.garden_id
-	LEFT JOIN 
-		categories_plants cp ON gc.category_id = cp.id
-	LEFT JOIN 
-		garden_plant gp ON g.id = gp.garden_id
-	LEFT JOIN 
-		plants p ON gp.plant_id = p.id
-	WHERE 
-		g.garden_location ILIKE $1 AND g.user_id = $2;`
-
-	rows, err := r.DB.QueryContext(ctx, query, "%"+gardenLocation+"%", userIdParse)
+	pageQuery, queryArgs := ks.pageQuery("gardens", "g", filter, args)
+	query := pageQuery + `
+	SELECT ` + gardenColumns("page") + `,
+		page.sort_value
+	FROM page
+	ORDER BY page.page_pos`
+
+	rows, err := reader(ctx, r.DB).Query(ctx, query, queryArgs...)
 	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
+		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
 	}
 	defer rows.Close()
 
-	gardenMap := make(map[string]*entities.GardenOutputDTO)
+	gardens := make([]*entities.GardenOutputDTO, 0)
+	sortValues := make(map[string]string)
 
 	for rows.Next() {
-		var category entities.CategoryPlant
-		var plant entities.Plant
-		tempGarden := entities.GardenOutputDTO{}
-
-		err := rows.Scan(
-			&tempGarden.Id,
-			&tempGarden.GardenName,
-			&tempGarden.GardenDescription,
-			&tempGarden.GardenLocation,
-			&tempGarden.TotalArea,
-			&tempGarden.CurrentingHeight,
-			&tempGarden.CurrentingWidth,
-			&tempGarden.PlantingDate,
-			&tempGarden.LastIrrigation,
-			&tempGarden.LastFertilization,
-			&tempGarden.IrrigationWeek,
-			&tempGarden.SunExposure,
-			&tempGarden.FertilizationWeek,
-			&tempGarden.CreatedAt,
-			&tempGarden.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&plant.Id,
-			&plant.PlantName,
-		)
+		var sortValue string
+		garden, err := scanGarden(rows, &sortValue)
 		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
-		}
-
-		existingGarden, exists := gardenMap[tempGarden.Id]
-		if !exists {
-			existingGarden = &tempGarden
-			existingGarden.CategoriesPlant = []entities.CategoryPlant{}
-			existingGarden.Plants = []entities.Plant{}
-			gardenMap[tempGarden.Id] = existingGarden
-		}
-
-		if category.Id != "" {
-			existingGarden.CategoriesPlant = append(existingGarden.CategoriesPlant, category)
-		}
-		if plant.Id != "" {
-			existingGarden.Plants = append(existingGarden.Plants, plant)
+			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
 		}
-	}
-
-	var gardens []*entities.GardenOutputDTO
-	for _, garden := range gardenMap {
 		gardens = append(gardens, garden)
+		sortValues[garden.Id] = sortValue
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
+		return nil, fmt.Errorf("erro durante a iteração: %w", err)
 	}
 
-	if len(gardens) == 0 {
-		log.Println("Nenhum jardim encontrado para os parâmetros fornecidos.")
+	result := &entities.Page[*entities.GardenOutputDTO]{
+		Items: gardens,
+		Total: total,
+		Limit: ks.limit,
+	}
+	if len(gardens) > ks.limit {
+		result.Items = gardens[:ks.limit]
+		last := result.Items[ks.limit-1]
+		result.NextCursor = ks.next(sortValues[last.Id], last.Id)
 	}
+	return result, nil
+}
 
-	return gardens, nil
+// Search combina os filtros informados em uma única consulta paginada. Os filtros de
+// texto comparam o valor inteiro, sem diferenciar maiúsculas.
+func (r *GardenRepositoryImpl) Search(ctx context.Context, userId string, filter entities.GardenFilter, page entities.PageRequest) (*entities.Page[*entities.GardenOutputDTO], error) {
+	return r.search(ctx, userId, filter, page, escapeLike)
 }
 
-func (r *GardenRepositoryImpl) FindByName(ctx context.Context, userId, gardenName string) ([]*entities.GardenOutputDTO, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, err
-	}
+// search monta a consulta de Search; like transforma cada filtro de texto no padrão do ILIKE.
+func (r *GardenRepositoryImpl) search(ctx context.Context, userId string, filter entities.GardenFilter, page entities.PageRequest, like func(string) string) (*entities.Page[*entities.GardenOutputDTO], error) {
 
-	query := `SELECT 
-		g.id AS garden_id,
-		g.garden_name,
-		g.garden_description,
-		g.garden_location,
-		g.total_area,
-		g.currenting_height,
-		g.currenting_width,
-		g.planting_date,
-		g.last_irrigation,
-		g.last_fertilization,
-		g.irrigation_week,
-		g.sun_exposure,
-		g.fertilization_week,
-		g.created_at AS garden_created_at,
-		g.updated_at AS garden_updated_at,
-		gc.category_id,
-		cp.category_name,
-		gp.plant_id,
-		p.plant_name
-	FROM 
-		gardens g
-	LEFT JOIN 
-		garden_categories gc ON g.id = gc.garden_id
-	LEFT JOIN 
-		categories_plants cp ON gc.category_id = cp.id
-	LEFT JOIN 
-		garden_plant gp ON g.id = gp.garden_id
-	LEFT JOIN 
-		plants p ON gp.plant_id = p.id
-	WHERE 
-		g.garden_name ILIKE $1 AND g.user_id = $2;`
-
-	rows, err := r.DB.QueryContext(ctx, query, "%"+gardenName+"%", userIdParse)
-	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
+	where := newWhere(memberOf("g.workspace_id", "?"), userId)
+	where.add("g.deleted_at IS NULL")
+	if filter.Name != "" {
+		where.add("f_unaccent(g.garden_name) ILIKE f_unaccent(?)", like(filter.Name))
 	}
-	defer rows.Close()
-
-	gardenMap := make(map[string]*entities.GardenOutputDTO)
-
-	for rows.Next() {
-		var category entities.CategoryPlant
-		var plant entities.Plant
-		tempGarden := entities.GardenOutputDTO{}
-
-		err := rows.Scan(
-			&tempGarden.Id,
-			&tempGarden.GardenName,
-			&tempGarden.GardenDescription,
-			&tempGarden.GardenLocation,
-			&tempGarden.TotalArea,
-			&tempGarden.CurrentingHeight,
-			&tempGarden.CurrentingWidth,
-			&tempGarden.PlantingDate,
-			&tempGarden.LastIrrigation,
-			&tempGarden.LastFertilization,
-			&tempGarden.IrrigationWeek,
-			&tempGarden.SunExposure,
-			&tempGarden.FertilizationWeek,
-			&tempGarden.CreatedAt,
-			&tempGarden.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&plant.Id,
-			&plant.PlantName,
-		)
-		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
-		}
-
-		existingGarden, exists := gardenMap[tempGarden.Id]
-		if !exists {
-			existingGarden = &tempGarden
-			existingGarden.CategoriesPlant = []entities.CategoryPlant{}
-			existingGarden.Plants = []entities.Plant{}
-			gardenMap[tempGarden.Id] = existingGarden
-		}
-
-		if category.Id != "" {
-			existingGarden.CategoriesPlant = append(existingGarden.CategoriesPlant, category)
-		}
-		if plant.Id != "" {
-			existingGarden.Plants = append(existingGarden.Plants, plant)
-		}
+	if filter.Location != "" {
+		where.add("f_unaccent(g.garden_location) ILIKE f_unaccent(?)", like(filter.Location))
 	}
-
-	var gardens []*entities.GardenOutputDTO
-	for _, garden := range gardenMap {
-		gardens = append(gardens, garden)
+	if filter.Category != "" {
+		where.add(`EXISTS (
+		SELECT 1 FROM garden_categories fgc
+		JOIN categories_plants fcp ON fgc.category_id = fcp.id
+		WHERE fgc.garden_id = g.id AND fcp.deleted_at IS NULL AND fcp.category_name ILIKE ?)`, like(filter.Category))
 	}
-
-	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
+	if filter.Plant != "" {
+		where.add(`EXISTS (
+		SELECT 1 FROM garden_plant fgp
+		JOIN plants fp ON fgp.plant_id = fp.id
+		WHERE fgp.garden_id = g.id AND fp.deleted_at IS NULL AND fp.plant_name ILIKE ?)`, like(filter.Plant))
 	}
-
-	if len(gardens) == 0 {
-		log.Println("Nenhum jardim encontrado para os parâmetros fornecidos.")
+	if filter.PlantedFrom != nil {
+		where.add("g.planting_date >= ?", *filter.PlantedFrom)
 	}
-
-	return gardens, nil
-}
-
-func (r *GardenRepositoryImpl) FindByCategoryName(ctx context.Context, userId, categoryName string) ([]*entities.GardenOutputDTO, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, err
+	if filter.PlantedTo != nil {
+		where.add("g.planting_date <= ?", *filter.PlantedTo)
 	}
-
-	query := `SELECT 
-		g.id AS garden_id,
-		g.garden_name,
-		g.garden_description,
-		g.garden_location,
-		g.total_area,
-		g.currenting_height,
-		g.currenting_width,
-		g.planting_date,
-		g.last_irrigation,
-		g.last_fertilization,
-		g.irrigation_week,
-		g.sun_exposure,
-		g.fertilization_week,
-		g.created_at AS garden_created_at,
-		g.updated_at AS garden_updated_at,
-		gc.category_id,
-		cp.category_name,
-		gp.plant_id,
-		p.plant_name
-	FROM 
-		gardens g
-	LEFT JOIN 
-		garden_categories gc ON g.id = gc.garden_id
-	LEFT JOIN 
-		categories_plants cp ON gc.category_id = cp.id
-	LEFT JOIN 
-		garden_plant gp ON g.id = gp.garden_id
-	LEFT JOIN 
-		plants p ON gp.plant_id = p.id
-	WHERE 
-		 g.user_id = $1 AND cp.category_name ILIKE $2;`
-
-	rows, err := r.DB.QueryContext(ctx, query, userIdParse, "%"+categoryName+"%")
-	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
+	if filter.LastIrrigationBefore != nil {
+		where.add("g.last_irrigation < ?", *filter.LastIrrigationBefore)
 	}
-	defer rows.Close()
-
-	gardenMap := make(map[string]*entities.GardenOutputDTO)
 
-	for rows.Next() {
-		var category entities.CategoryPlant
-		var plant entities.Plant
-		tempGarden := entities.GardenOutputDTO{}
-
-		err := rows.Scan(
-			&tempGarden.Id,
-			&tempGarden.GardenName,
-			&tempGarden.GardenDescription,
-			&tempGarden.GardenLocation,
-			&tempGarden.TotalArea,
-			&tempGarden.CurrentingHeight,
-			&tempGarden.CurrentingWidth,
-			&tempGarden.PlantingDate,
-			&tempGarden.LastIrrigation,
-			&tempGarden.LastFertilization,
-			&tempGarden.IrrigationWeek,
-			&tempGarden.SunExposure,
-			&tempGarden.FertilizationWeek,
-			&tempGarden.CreatedAt,
-			&tempGarden.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&plant.Id,
-			&plant.PlantName,
-		)
-		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
-		}
+	return r.listGardens(ctx, where.sql(), where.args, page)
+}
 
-		existingGarden, exists := gardenMap[tempGarden.Id]
-		if !exists {
-			existingGarden = &tempGarden
-			existingGarden.CategoriesPlant = []entities.CategoryPlant{}
-			existingGarden.Plants = []entities.Plant{}
-			gardenMap[tempGarden.Id] = existingGarden
-		}
+func (r *GardenRepositoryImpl) FindAll(ctx context.Context, userId string, page entities.PageRequest) (*entities.Page[*entities.GardenOutputDTO], error) {
+	return r.Search(ctx, userId, entities.GardenFilter{}, page)
+}
 
-		if category.Id != "" {
-			existingGarden.CategoriesPlant = append(existingGarden.CategoriesPlant, category)
-		}
-		if plant.Id != "" {
-			existingGarden.Plants = append(existingGarden.Plants, plant)
-		}
-	}
+func (r *GardenRepositoryImpl) FindByLocation(ctx context.Context, userId, gardenLocation string, page entities.PageRequest) (*entities.Page[*entities.GardenOutputDTO], error) {
+	return r.search(ctx, userId, entities.GardenFilter{Location: gardenLocation}, page, containsLike)
+}
 
-	var gardens []*entities.GardenOutputDTO
-	for _, garden := range gardenMap {
-		gardens = append(gardens, garden)
-	}
+func (r *GardenRepositoryImpl) FindByName(ctx context.Context, userId, gardenName string, page entities.PageRequest) (*entities.Page[*entities.GardenOutputDTO], error) {
+	return r.search(ctx, userId, entities.GardenFilter{Name: gardenName}, page, containsLike)
+}
 
-	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
-	}
+func (r *GardenRepositoryImpl) FindByCategoryName(ctx context.Context, userId, categoryName string, page entities.PageRequest) (*entities.Page[*entities.GardenOutputDTO], error) {
+	return r.search(ctx, userId, entities.GardenFilter{Category: categoryName}, page, containsLike)
+}
 
-	if len(gardens) == 0 {
-		log.Println("Nenhum jardim encontrado para os parâmetros fornecidos.")
+func (r *GardenRepositoryImpl) NameInUse(ctx context.Context, userId, name, exceptId string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM gardens
+		WHERE ` + memberOf("workspace_id", "$1") + ` AND deleted_at IS NULL
+			AND lower(garden_name) = lower($2) AND id <> $3)`
+	var inUse bool
+	// a checagem antecede uma escrita, então não pode ler de uma réplica atrasada
+	if err := primary(ctx, r.DB).QueryRow(ctx, query, userId, name, exceptId).Scan(&inUse); err != nil {
+		return false, fmt.Errorf("erro ao verificar nome do jardim: %w", err)
 	}
-
-	return gardens, nil
+	return inUse, nil
 }
 
 func (r *GardenRepositoryImpl) Update(ctx context.Context, garden *entities.Garden) error {
-	tx, err := r.DB.Begin()
-	if err != nil {
-		return fmt.Errorf("erro ao iniciar transação: %v", err)
-	}
 	updateGardenQuery := `UPDATE gardens SET garden_name = $1, garden_description = $2, garden_location = $3,
 	total_area = $4, currenting_height = $5, currenting_width = $6, planting_date = $7, last_irrigation = $8,
-	last_fertilization = $9, irrigation_week = $10, sun_exposure = $11, fertilization_week = $12
-	WHERE id = $13 AND user_id = $14;`
-
-	_, err = tx.ExecContext(ctx, updateGardenQuery, garden.GardenName, garden.GardenDescription, garden.GardenLocation,
-		garden.TotalArea, garden.CurrentingHeight, garden.CurrentingWidth, garden.PlantingDate, garden.LastIrrigation,
-		garden.LastFertilization, garden.IrrigationWeek, garden.SunExposure, garden.FertilizationWeek, garden.Id, garden.UserId)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("erro ao atualizar jardim: %v", err)
-	}
-
-	deleteCategoriesQuery := `DELETE FROM garden_categories WHERE garden_id = $1;`
-	_, err = tx.ExecContext(ctx, deleteCategoriesQuery, garden.Id)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("erro ao deletar categorias: %v", err)
-	}
-	deletePlantQuery := `DELETE FROM garden_plant WHERE garden_id = $1;`
-	_, err = tx.ExecContext(ctx, deletePlantQuery, garden.Id)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("erro ao deletar plantas: %v", err)
-	}
-
-	if len(garden.CategoriesPlantId) > 0 {
-		insertCategoriesQuery := `INSERT INTO garden_categories (id, garden_id, category_id) VALUES ($1, $2, $3);`
-		for _, categoryPlantId := range garden.CategoriesPlantId {
-			categoryId, err := uuid.Parse(categoryPlantId)
-			if err != nil {
-				tx.Rollback()
-				return fmt.Errorf("erro ao converter id da categoria: %v", err)
-			}
-			_, err = tx.ExecContext(ctx, insertCategoriesQuery, uuid.New(), garden.Id, categoryId)
-			if err != nil {
-				tx.Rollback()
-				return fmt.Errorf("erro ao inserir categoria: %v", err)
-			}
+	last_fertilization = $9, irrigation_week = $10, sun_exposure = $11, fertilization_week = $12,
+	version = version + 1
+	WHERE id = $13 AND ` + editorOf("workspace_id", "$14") + ` AND deleted_at IS NULL AND version = $15;`
+
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		result, err := conn(ctx, r.DB).Exec(ctx, updateGardenQuery, garden.GardenName, garden.GardenDescription, garden.GardenLocation,
+			garden.TotalArea, garden.CurrentingHeight, garden.CurrentingWidth, garden.PlantingDate, garden.LastIrrigation,
+			garden.LastFertilization, garden.IrrigationWeek, garden.SunExposure, garden.FertilizationWeek, garden.Id, garden.UserId,
+			garden.Version)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar jardim: %v", err)
 		}
-	}
-
-	if len(garden.PlantsId) > 0 {
-		insertPlantQuery := `INSERT INTO garden_plant (id, garden_id, plant_id) VALUES ($1, $2, $3);`
-		for _, plantId := range garden.PlantsId {
-			plantIdParsed, err := uuid.Parse(plantId)
-			if err != nil {
-				tx.Rollback()
-				return fmt.Errorf("erro ao converter id da planta: %v", err)
-			}
-			_, err = tx.ExecContext(ctx, insertPlantQuery, uuid.New(), garden.Id, plantIdParsed)
-			if err != nil {
-				tx.Rollback()
-				return fmt.Errorf("erro ao inserir planta: %v", err)
+		if result.RowsAffected() == 0 {
+			if err := writeDenied(ctx, r.DB, "gardens", garden.Id, garden.UserId); err != nil {
+				return err
 			}
+			// outra escrita passou na frente desde a leitura feita pelo cliente
+			return entities.ErrVersionConflict
 		}
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return fmt.Errorf("erro ao commitar transação: %v", err)
-	}
-
-	return nil
 
+		var batch links
+		batch.replace("garden_categories", "garden_id", "category_id", "categories_plants", garden.Id)
+		batch.replace("garden_plant", "garden_id", "plant_id", "plants", garden.Id)
+		gardenLinks(&batch, garden)
+		return batch.send(ctx, conn(ctx, r.DB))
+	})
 }
 
 func (r *GardenRepositoryImpl) Delete(ctx context.Context, userId, id string) error {
-	userIdParsed, err := uuid.Parse(userId)
-	if err != nil {
-		return err
-	}
-	idParsed, err := uuid.Parse(id)
-	if err != nil {
-		return err
-	}
-
-	query := `DELETE FROM gardens WHERE id = $1 AND user_id = $2;`
-	_, err = r.DB.ExecContext(ctx, query, idParsed, userIdParsed)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// vai para a lixeira; histórico e vínculos só somem no expurgo
+	query := `UPDATE gardens SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND ` + editorOf("workspace_id", "$2") + ` AND deleted_at IS NULL;`
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		result, err := conn(ctx, r.DB).Exec(ctx, query, id, userId)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return writeDenied(ctx, r.DB, "gardens", id, userId)
+		}
+		return cancelCareTasks(ctx, r.DB, entities.CareSubjectGarden, id)
+	})
 }
 
 func (r *GardenRepositoryImpl) CreateHistory(ctx context.Context, garden *entities.HistoryGarden) error {
@@ -714,23 +327,9 @@ func (r *GardenRepositoryImpl) CreateHistory(ctx context.Context, garden *entiti
 		)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);`
 
-	idParsed, err := uuid.Parse(garden.ID)
-	if err != nil {
-		return err
-	}
-	gardenIdParsed, err := uuid.Parse(garden.GardenID)
-	if err != nil {
-		return err
-	}
-
-	userIdParsed, err := uuid.Parse(garden.UserID)
-	if err != nil {
-		return err
-	}
-
-	_, err = r.DB.ExecContext(ctx, query, idParsed, gardenIdParsed, garden.GardenLocation, garden.TotalArea, garden.RecordDate,
+	_, err := conn(ctx, r.DB).Exec(ctx, query, garden.ID, garden.GardenID, garden.GardenLocation, garden.TotalArea, garden.RecordDate,
 		garden.Height, garden.Width, garden.HealthStatus, garden.Irrigation, garden.Fertilization, garden.IrrigationWeek,
-		garden.SunExposure, garden.FertilizationWeek, garden.Notes, userIdParsed)
+		garden.SunExposure, garden.FertilizationWeek, garden.Notes, garden.UserID)
 
 	if err != nil {
 		return err
@@ -740,14 +339,25 @@ func (r *GardenRepositoryImpl) CreateHistory(ctx context.Context, garden *entiti
 
 }
 
-func (r *GardenRepositoryImpl) FindAllHistoryByGardenID(ctx context.Context, gardenID string) ([]*entities.HistoryGarden, error) {
+func (r *GardenRepositoryImpl) FindAllHistoryByGardenID(ctx context.Context, userId, gardenID string, page entities.PageRequest) (*entities.Page[*entities.HistoryGarden], error) {
+
+	ks, err := newKeyset(page, historySortFields, "record_date", "h.id")
+	if err != nil {
+		return nil, err
+	}
 
-	gardenIdParsed, err := uuid.Parse(gardenID)
+	filter := `h.garden_id = $1 AND EXISTS (SELECT 1 FROM gardens g
+		WHERE g.id = h.garden_id AND g.deleted_at IS NULL AND ` + memberOf("g.workspace_id", "$2") + `)`
+	args := []interface{}{gardenID, userId}
+	total, err := count(ctx, reader(ctx, r.DB), "history_gardens_timeline", "h", filter, args)
 	if err != nil {
 		return nil, err
 	}
 
-	query := `SELECT 
+	// a linha do tempo junta os registros brutos com os resumos do que saiu da retenção
+	pageQuery, queryArgs := ks.pageQuery("history_gardens_timeline", "h", filter, args)
+	query := pageQuery + `
+	SELECT 
 		hg.id,
 		hg.garden_id,
 		hg.garden_location,
@@ -763,23 +373,31 @@ func (r *GardenRepositoryImpl) FindAllHistoryByGardenID(ctx context.Context, gar
 		hg.fertilization_week,
 		hg.notes,
 		hg.user_id,
-		hg.created_at
+		hg.created_at,
+		hg.sort_value,
+		hg.care_type,
+		hg.amount,
+		` + timelineRollupColumns + `
 	FROM 
-		history_gardens hg
-	WHERE 
-		hg.garden_id = $1;`
+		page hg
+	ORDER BY 
+		hg.page_pos;`
 
-	rows, err := r.DB.QueryContext(ctx, query, gardenIdParsed)
+	rows, err := reader(ctx, r.DB).Query(ctx, query, queryArgs...)
 	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
+		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
 	}
 	defer rows.Close()
 
 	historyGardens := make([]*entities.HistoryGarden, 0)
+	sortValues := make([]string, 0)
 
 	for rows.Next() {
 		historyGarden := entities.HistoryGarden{}
-		err := rows.Scan(
+		var sortValue string
+		var careType *string
+		var rollup timelineRollup
+		dest := []interface{}{
 			&historyGarden.ID,
 			&historyGarden.GardenID,
 			&historyGarden.GardenLocation,
@@ -796,21 +414,89 @@ func (r *GardenRepositoryImpl) FindAllHistoryByGardenID(ctx context.Context, gar
 			&historyGarden.Notes,
 			&historyGarden.UserID,
 			&historyGarden.CreatedAt,
-		)
-		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
+			&sortValue,
+			&careType,
+			&historyGarden.Amount,
+		}
+		if err := rows.Scan(append(dest, rollup.dest()...)...); err != nil {
+			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
 		}
+		historyGarden.CareType = deref(careType)
+		historyGarden.Rollup = rollup.value()
 
 		historyGardens = append(historyGardens, &historyGarden)
+		sortValues = append(sortValues, sortValue)
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
+		return nil, fmt.Errorf("erro durante a iteração: %w", err)
 	}
 
-	if len(historyGardens) == 0 {
-		log.Println("Nenhum histórico encontrado para os parâmetros fornecidos.")
+	result := &entities.Page[*entities.HistoryGarden]{
+		Items: historyGardens,
+		Total: total,
+		Limit: ks.limit,
+	}
+	if len(historyGardens) > ks.limit {
+		result.Items = historyGardens[:ks.limit]
+		last := result.Items[ks.limit-1]
+		result.NextCursor = ks.next(sortValues[ks.limit-1], last.ID)
 	}
+	return result, nil
+}
+
+func (r *GardenRepositoryImpl) SetAutoTasks(ctx context.Context, userId, id string, enabled bool) error {
+	query := `UPDATE gardens SET auto_tasks = $1, version = version + 1
+		WHERE id = $2 AND ` + editorOf("workspace_id", "$3") + ` AND deleted_at IS NULL`
+
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		result, err := conn(ctx, r.DB).Exec(ctx, query, enabled, id, userId)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar tarefas automáticas do jardim: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			if err := writeDenied(ctx, r.DB, "gardens", id, userId); err != nil {
+				return err
+			}
+			return errors.New("jardim não encontrado")
+		}
+		if enabled {
+			return nil
+		}
+		return cancelCareTasks(ctx, r.DB, entities.CareSubjectGarden, id)
+	})
+}
 
-	return historyGardens, nil
+// gardenCareQuery aplica o cuidado e grava o histórico com os valores já atualizados.
+// Jardins não guardam a saúde, então o histórico repete a do último registro.
+var gardenCareQuery = `
+	WITH cared AS (
+		UPDATE gardens g SET
+			last_irrigation = CASE WHEN $3::text = 'irrigation' THEN GREATEST(g.last_irrigation, $4::timestamp) ELSE g.last_irrigation END,
+			last_fertilization = CASE WHEN $3::text = 'fertilization' THEN GREATEST(g.last_fertilization, $4::timestamp) ELSE g.last_fertilization END,
+			currenting_height = COALESCE($6::numeric, g.currenting_height),
+			currenting_width = COALESCE($7::numeric, g.currenting_width),
+			updated_at = CURRENT_TIMESTAMP,
+			version = g.version + 1
+		WHERE g.id = ANY($1::uuid[]) AND ` + editorOf("g.workspace_id", "$2") + ` AND g.deleted_at IS NULL
+		RETURNING g.id, g.garden_location, g.total_area, g.currenting_height, g.currenting_width,
+			g.irrigation_week, g.sun_exposure, g.fertilization_week
+	)
+	INSERT INTO history_gardens (
+		id, garden_id, garden_location, total_area, record_date, height, width, health_status,
+		irrigation, fertilization, irrigation_week, sun_exposure, fertilization_week, notes, user_id, care_type, amount
+	)
+	SELECT gen_random_uuid(), c.id, c.garden_location, c.total_area, $4::timestamp, c.currenting_height, c.currenting_width,
+		COALESCE((SELECT h.health_status FROM history_gardens h
+			WHERE h.garden_id = c.id AND h.health_status IS NOT NULL
+			ORDER BY h.record_date DESC LIMIT 1), 'Saudável'),
+		$3::text = 'irrigation', $3::text = 'fertilization', c.irrigation_week, c.sun_exposure, c.fertilization_week,
+		$8::text, $2::uuid, $3::text, $5::numeric
+	FROM cared c
+	RETURNING garden_id::text`
+
+func (r *GardenRepositoryImpl) LogCare(ctx context.Context, care *entities.CareLog, ids []string) error {
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		return logCare(ctx, r.DB, "gardens", entities.CareSubjectGarden, gardenCareQuery, care, ids)
+	})
 }
@@ -0,0 +1,295 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type HarvestRepositoryImpl struct {
+	DB *database.Cluster
+}
+
+func NewHarvestRepository(db *database.Cluster) *HarvestRepositoryImpl {
+	return &HarvestRepositoryImpl{DB: db}
+}
+
+func (r *HarvestRepositoryImpl) Create(ctx context.Context, harvest *entities.Harvest, plantStatus string) error {
+	// a consulta da planta confere a permissão e, com plantStatus, já muda o status
+	plantQuery := `SELECT workspace_id, estimated_harvest_date FROM plants
+		WHERE id = $1 AND ` + editorOf("workspace_id", "$2") + ` AND deleted_at IS NULL
+		FOR UPDATE`
+	plantArgs := []interface{}{harvest.PlantId, harvest.UserId}
+	if plantStatus != "" {
+		plantQuery = `UPDATE plants SET plant_status = $3, version = version + 1
+			WHERE id = $1 AND ` + editorOf("workspace_id", "$2") + ` AND deleted_at IS NULL
+			RETURNING workspace_id, estimated_harvest_date`
+		plantArgs = append(plantArgs, plantStatus)
+	}
+
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		err := conn(ctx, r.DB).QueryRow(ctx, plantQuery, plantArgs...).Scan(&harvest.WorkspaceId, &harvest.EstimatedHarvestDate)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if err := writeDenied(ctx, r.DB, "plants", harvest.PlantId, harvest.UserId); err != nil {
+				return err
+			}
+			return entities.ErrPlantNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao buscar planta da colheita: %w", err)
+		}
+
+		if harvest.GardenId != "" {
+			var found bool
+			gardenQuery := `SELECT EXISTS (SELECT 1 FROM gardens WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL)`
+			if err := conn(ctx, r.DB).QueryRow(ctx, gardenQuery, harvest.GardenId, harvest.WorkspaceId).Scan(&found); err != nil {
+				return fmt.Errorf("erro ao buscar jardim da colheita: %w", err)
+			}
+			if !found {
+				return entities.ErrHarvestGardenNotFound
+			}
+		}
+
+		query := `INSERT INTO harvests (id, plant_id, garden_id, workspace_id, user_id, harvest_date, quantity, unit,
+				quality_grade, notes, estimated_harvest_date)
+			VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11)
+			RETURNING created_at`
+		err = conn(ctx, r.DB).QueryRow(ctx, query, harvest.Id, harvest.PlantId, harvest.GardenId, harvest.WorkspaceId,
+			harvest.UserId, harvest.HarvestDate, harvest.Quantity, harvest.Unit, harvest.QualityGrade, harvest.Notes,
+			harvest.EstimatedHarvestDate).Scan(&harvest.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar colheita: %w", err)
+		}
+		return nil
+	})
+}
+
+var harvestSortFields = map[string]sortField{
+	"harvest_date": {column: "h.harvest_date", cast: "timestamp"},
+	"quantity":     {column: "h.quantity", cast: "numeric"},
+	"created_at":   {column: "h.created_at", cast: "timestamp"},
+}
+
+func (r *HarvestRepositoryImpl) FindAll(ctx context.Context, userId string, filter entities.HarvestFilter, page entities.PageRequest) (*entities.Page[*entities.Harvest], error) {
+	if page.Sort == "" && page.Order == "" {
+		// sem ordem pedida, as colheitas mais recentes vêm primeiro
+		page.Order = "desc"
+	}
+	ks, err := newKeyset(page, harvestSortFields, "harvest_date", "h.id")
+	if err != nil {
+		return nil, err
+	}
+
+	where := newWhere(memberOf("h.workspace_id", "?"), userId)
+	where.add("EXISTS (SELECT 1 FROM plants fp WHERE fp.id = h.plant_id AND fp.deleted_at IS NULL)")
+	if filter.PlantId != "" {
+		where.add("h.plant_id = ?", filter.PlantId)
+	}
+	if filter.GardenId != "" {
+		where.add("h.garden_id = ?", filter.GardenId)
+	}
+	if filter.From != nil {
+		where.add("h.harvest_date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		where.add("h.harvest_date <= ?", *filter.To)
+	}
+
+	total, err := count(ctx, reader(ctx, r.DB), "harvests", "h", where.sql(), where.args)
+	if err != nil {
+		return nil, err
+	}
+
+	pageQuery, queryArgs := ks.pageQuery("harvests", "h", where.sql(), where.args)
+	query := pageQuery + `
+		SELECT id, plant_id, COALESCE(garden_id::text, ''), workspace_id, user_id, harvest_date, quantity::float8, unit,
+			COALESCE(quality_grade, ''), notes, estimated_harvest_date, created_at, sort_value
+		FROM page
+		ORDER BY page_pos`
+
+	rows, err := reader(ctx, r.DB).Query(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar colheitas: %w", err)
+	}
+	defer rows.Close()
+
+	harvests := make([]*entities.Harvest, 0)
+	sortValues := make([]string, 0)
+	for rows.Next() {
+		var harvest entities.Harvest
+		var sortValue string
+		err := rows.Scan(&harvest.Id, &harvest.PlantId, &harvest.GardenId, &harvest.WorkspaceId, &harvest.UserId,
+			&harvest.HarvestDate, &harvest.Quantity, &harvest.Unit, &harvest.QualityGrade, &harvest.Notes,
+			&harvest.EstimatedHarvestDate, &harvest.CreatedAt, &sortValue)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler colheitas: %w", err)
+		}
+		harvests = append(harvests, &harvest)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler colheitas: %w", err)
+	}
+
+	result := &entities.Page[*entities.Harvest]{
+		Items: harvests,
+		Total: total,
+		Limit: ks.limit,
+	}
+	if len(harvests) > ks.limit {
+		result.Items = harvests[:ks.limit]
+		last := result.Items[ks.limit-1]
+		result.NextCursor = ks.next(sortValues[ks.limit-1], last.Id)
+	}
+	return result, nil
+}
+
+// Delete apaga a colheita de vez; colheitas não passam pela lixeira.
+func (r *HarvestRepositoryImpl) Delete(ctx context.Context, userId, id string) error {
+	query := `DELETE FROM harvests WHERE id = $1 AND ` + editorOf("workspace_id", "$2")
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		result, err := conn(ctx, r.DB).Exec(ctx, query, id, userId)
+		if err != nil {
+			return fmt.Errorf("erro ao excluir colheita: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			if err := writeDenied(ctx, r.DB, "harvests", id, userId); err != nil {
+				return err
+			}
+			return entities.ErrHarvestNotFound
+		}
+		return nil
+	})
+}
+
+// harvestSeason é a estação da colheita no hemisfério sul. O verão começa em
+// dezembro e leva o ano do seu início, por isso o ano é o de dois meses antes.
+const harvestSeason = `
+	CASE
+		WHEN EXTRACT(MONTH FROM h.harvest_date) IN (12, 1, 2) THEN 'summer'
+		WHEN EXTRACT(MONTH FROM h.harvest_date) IN (3, 4, 5) THEN 'autumn'
+		WHEN EXTRACT(MONTH FROM h.harvest_date) IN (6, 7, 8) THEN 'winter'
+		ELSE 'spring'
+	END`
+
+const harvestSeasonLabel = `
+	CASE
+		WHEN EXTRACT(MONTH FROM h.harvest_date) IN (12, 1, 2) THEN 'Verão'
+		WHEN EXTRACT(MONTH FROM h.harvest_date) IN (3, 4, 5) THEN 'Outono'
+		WHEN EXTRACT(MONTH FROM h.harvest_date) IN (6, 7, 8) THEN 'Inverno'
+		ELSE 'Primavera'
+	END`
+
+const harvestSeasonYear = `to_char(h.harvest_date - INTERVAL '2 months', 'YYYY')`
+
+// harvestGroups são a chave e o rótulo de cada agrupamento das estatísticas.
+var harvestGroups = map[string][2]string{
+	entities.HarvestGroupPlant:   {"h.plant_id::text", "h.plant_name"},
+	entities.HarvestGroupGarden:  {"COALESCE(h.visible_garden_id::text, '')", "COALESCE(h.garden_name, 'Sem jardim')"},
+	entities.HarvestGroupSpecies: {"h.species_id::text", "COALESCE(h.common_name, '')"},
+	entities.HarvestGroupSeason:  {harvestSeasonYear + " || '-' || " + harvestSeason, harvestSeasonLabel + " || ' ' || " + harvestSeasonYear},
+}
+
+// harvestStatsQuery soma as colheitas por grupo; %[1]s e %[2]s são a chave e o rótulo.
+// Os GROUPING SETS devolvem, por grupo, uma linha com os totais e a precisão
+// (base_unit nulo) e uma linha de rendimento por unidade base. A precisão só usa a
+// primeira colheita de cada planta, contada antes do filtro de datas.
+const harvestStatsQuery = `
+	WITH visible AS (
+		SELECT h.*, p.plant_name, p.species_id, s.common_name, g.id AS visible_garden_id, g.garden_name,
+			ROW_NUMBER() OVER (PARTITION BY h.plant_id ORDER BY h.harvest_date, h.id) = 1 AS first_of_plant
+		FROM harvests h
+		JOIN plants p ON p.id = h.plant_id AND p.deleted_at IS NULL
+		LEFT JOIN species s ON s.id = p.species_id
+		LEFT JOIN gardens g ON g.id = h.garden_id AND g.deleted_at IS NULL
+		WHERE %[3]s
+	), grouped AS (
+		SELECT %[1]s AS group_key, %[2]s AS group_label,
+			CASE h.unit WHEN 'g' THEN 'kg' WHEN 'ml' THEN 'l' ELSE h.unit END AS base_unit,
+			(h.quantity * CASE WHEN h.unit IN ('g', 'ml') THEN 0.001 ELSE 1 END)::float8 AS base_quantity,
+			h.harvest_date,
+			CASE WHEN h.first_of_plant AND h.estimated_harvest_date IS NOT NULL
+				THEN (EXTRACT(EPOCH FROM h.harvest_date - h.estimated_harvest_date) / 86400)::float8
+			END AS delay_days
+		FROM visible h
+		WHERE ($2::timestamp IS NULL OR h.harvest_date >= $2::timestamp)
+			AND ($3::timestamp IS NULL OR h.harvest_date <= $3::timestamp)
+	)
+	SELECT group_key, group_label, COALESCE(base_unit, ''), COUNT(*), COALESCE(SUM(base_quantity), 0),
+		COALESCE(AVG(base_quantity), 0), MIN(harvest_date), MAX(harvest_date),
+		COUNT(delay_days), COALESCE(AVG(delay_days), 0), COALESCE(AVG(ABS(delay_days)), 0),
+		COUNT(*) FILTER (WHERE ABS(delay_days) <= $4::int)
+	FROM grouped
+	GROUP BY GROUPING SETS ((group_key, group_label), (group_key, group_label, base_unit))
+	ORDER BY group_key, GROUPING(base_unit) DESC, base_unit`
+
+func (r *HarvestRepositoryImpl) Stats(ctx context.Context, userId string, statsQuery entities.HarvestStatsQuery) ([]*entities.HarvestStats, error) {
+	group, ok := harvestGroups[statsQuery.GroupBy]
+	if !ok {
+		return nil, entities.ErrInvalidHarvestGroup
+	}
+	query := fmt.Sprintf(harvestStatsQuery, group[0], group[1], memberOf("h.workspace_id", "$1"))
+
+	rows, err := reader(ctx, r.DB).Query(ctx, query, userId, statsQuery.From, statsQuery.To, entities.HarvestOnTimeDays)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular estatísticas de colheita: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]*entities.HarvestStats, 0)
+	byKey := make(map[string]*entities.HarvestStats)
+	for rows.Next() {
+		var key, label, unit string
+		var harvests, samples, onTime int
+		var total, average, avgDelay, avgAbsDelay float64
+		var row entities.HarvestStats
+		err := rows.Scan(&key, &label, &unit, &harvests, &total, &average, &row.FirstHarvest, &row.LastHarvest,
+			&samples, &avgDelay, &avgAbsDelay, &onTime)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler estatísticas de colheita: %w", err)
+		}
+
+		// a linha de totais vem antes das linhas de rendimento do mesmo grupo
+		if unit == "" {
+			row.Key, row.Label, row.Harvests = key, label, harvests
+			row.Yields = make([]*entities.HarvestYield, 0)
+			if samples > 0 {
+				row.Accuracy = &entities.HarvestAccuracy{
+					Samples:         samples,
+					AvgDelayDays:    round2(avgDelay),
+					AvgAbsDelayDays: round2(avgAbsDelay),
+					OnTimeRatio:     round2(float64(onTime) / float64(samples)),
+				}
+			}
+			byKey[key] = &row
+			stats = append(stats, &row)
+			continue
+		}
+		if parent, ok := byKey[key]; ok {
+			parent.Yields = append(parent.Yields, &entities.HarvestYield{
+				Unit:     unit,
+				Harvests: harvests,
+				Total:    round2(total),
+				Average:  round2(average),
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler estatísticas de colheita: %w", err)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].FirstHarvest.Before(stats[j].FirstHarvest)
+	})
+	return stats, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,234 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// historyRollupLockKey garante que só uma instância resume o histórico por vez.
+const historyRollupLockKey int64 = 0x686973746f7279 // "history"
+
+// rollupCutoff é o início do primeiro período que ainda fica bruto. CURRENT_TIMESTAMP
+// não muda dentro da transação, então todas as consultas do resumo usam o mesmo corte.
+// $1 é a granularidade e $2 a retenção em segundos.
+const rollupCutoff = `date_trunc($1::text, CURRENT_TIMESTAMP::timestamp - make_interval(secs => $2))`
+
+// historyTables são as tabelas de histórico particionadas e o INSERT do resumo de cada uma.
+var historyTables = []struct {
+	table  string
+	rollup string
+}{
+	{"history_plants", `
+		INSERT INTO history_plants_rollups AS r (
+			plant_id, granularity, period_start, period_end, samples,
+			min_height, max_height, avg_height, min_width, max_width, avg_width,
+			irrigation_count, fertilization_count, health_status,
+			irrigation_week, sun_exposure, fertilization_week, user_id, created_at
+		)
+		SELECT plant_id, $1::text, period_start, period_start + ('1 ' || $1::text)::interval, COUNT(*),
+			MIN(height), MAX(height), AVG(height), MIN(width), MAX(width), AVG(width),
+			COUNT(*) FILTER (WHERE irrigation), COUNT(*) FILTER (WHERE fertilization),
+			(array_agg(health_status ORDER BY record_date DESC))[1],
+			AVG(irrigation_week), AVG(sun_exposure), AVG(fertilization_week),
+			(array_agg(user_id ORDER BY record_date DESC))[1], MIN(created_at)
+		FROM (
+			SELECT h.*, date_trunc($1::text, h.record_date) AS period_start
+			FROM history_plants h
+			WHERE h.record_date < ` + rollupCutoff + `
+		) raw
+		GROUP BY plant_id, period_start
+		ON CONFLICT (plant_id, granularity, period_start) DO UPDATE SET ` + rollupMerge},
+	{"history_gardens", `
+		INSERT INTO history_gardens_rollups AS r (
+			garden_id, granularity, period_start, period_end, samples, garden_location, total_area,
+			min_height, max_height, avg_height, min_width, max_width, avg_width,
+			irrigation_count, fertilization_count, health_status,
+			irrigation_week, sun_exposure, fertilization_week, user_id, created_at
+		)
+		SELECT garden_id, $1::text, period_start, period_start + ('1 ' || $1::text)::interval, COUNT(*),
+			(array_agg(garden_location ORDER BY record_date DESC))[1],
+			(array_agg(total_area ORDER BY record_date DESC))[1],
+			MIN(height), MAX(height), AVG(height), MIN(width), MAX(width), AVG(width),
+			COUNT(*) FILTER (WHERE irrigation), COUNT(*) FILTER (WHERE fertilization),
+			(array_agg(health_status ORDER BY record_date DESC))[1],
+			AVG(irrigation_week), AVG(sun_exposure), AVG(fertilization_week),
+			(array_agg(user_id ORDER BY record_date DESC))[1], MIN(created_at)
+		FROM (
+			SELECT h.*, date_trunc($1::text, h.record_date) AS period_start
+			FROM history_gardens h
+			WHERE h.record_date < ` + rollupCutoff + `
+		) raw
+		GROUP BY garden_id, period_start
+		ON CONFLICT (garden_id, granularity, period_start) DO UPDATE SET
+			garden_location = EXCLUDED.garden_location,
+			total_area = EXCLUDED.total_area, ` + rollupMerge},
+}
+
+// rollupMerge junta um resumo novo com o que já existia no mesmo período, o que só
+// acontece quando chegam registros atrasados depois de o período ter sido resumido.
+const rollupMerge = `
+	samples = r.samples + EXCLUDED.samples,
+	min_height = LEAST(r.min_height, EXCLUDED.min_height),
+	max_height = GREATEST(r.max_height, EXCLUDED.max_height),
+	avg_height = COALESCE((r.avg_height * r.samples + EXCLUDED.avg_height * EXCLUDED.samples) / (r.samples + EXCLUDED.samples), r.avg_height, EXCLUDED.avg_height),
+	min_width = LEAST(r.min_width, EXCLUDED.min_width),
+	max_width = GREATEST(r.max_width, EXCLUDED.max_width),
+	avg_width = COALESCE((r.avg_width * r.samples + EXCLUDED.avg_width * EXCLUDED.samples) / (r.samples + EXCLUDED.samples), r.avg_width, EXCLUDED.avg_width),
+	irrigation_count = r.irrigation_count + EXCLUDED.irrigation_count,
+	fertilization_count = r.fertilization_count + EXCLUDED.fertilization_count,
+	health_status = EXCLUDED.health_status,
+	irrigation_week = (r.irrigation_week * r.samples + EXCLUDED.irrigation_week * EXCLUDED.samples) / (r.samples + EXCLUDED.samples),
+	sun_exposure = (r.sun_exposure * r.samples + EXCLUDED.sun_exposure * EXCLUDED.samples) / (r.samples + EXCLUDED.samples),
+	fertilization_week = (r.fertilization_week * r.samples + EXCLUDED.fertilization_week * EXCLUDED.samples) / (r.samples + EXCLUDED.samples),
+	user_id = EXCLUDED.user_id,
+	created_at = LEAST(r.created_at, EXCLUDED.created_at),
+	rolled_up_at = CURRENT_TIMESTAMP`
+
+type HistoryRepositoryImpl struct {
+	DB *database.Cluster
+}
+
+func NewHistoryRepository(db *database.Cluster) *HistoryRepositoryImpl {
+	return &HistoryRepositoryImpl{DB: db}
+}
+
+func (r *HistoryRepositoryImpl) EnsurePartitions(ctx context.Context, months int) (int, error) {
+	var created int
+	for _, t := range historyTables {
+		var n int
+		query := `
+			SELECT COUNT(*) FILTER (WHERE ensure_history_partition($1, m::date))
+			FROM generate_series(
+				date_trunc('month', CURRENT_TIMESTAMP::timestamp),
+				date_trunc('month', CURRENT_TIMESTAMP::timestamp) + make_interval(months => $2),
+				INTERVAL '1 month'
+			) AS m`
+		if err := conn(ctx, r.DB).QueryRow(ctx, query, t.table, months).Scan(&n); err != nil {
+			return created, fmt.Errorf("erro ao criar partições de %s: %w", t.table, err)
+		}
+		created += n
+	}
+	return created, nil
+}
+
+func (r *HistoryRepositoryImpl) Rollup(ctx context.Context, retention time.Duration, granularity string) (int64, error) {
+	if !entities.IsHistoryGranularity(granularity) {
+		return 0, fmt.Errorf("granularidade do histórico inválida: %s", granularity)
+	}
+
+	var periods int64
+	err := withTx(ctx, r.DB, func(ctx context.Context) error {
+		var locked bool
+		if err := conn(ctx, r.DB).QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, historyRollupLockKey).Scan(&locked); err != nil {
+			return fmt.Errorf("erro ao travar o resumo do histórico: %w", err)
+		}
+		if !locked {
+			// outra instância já está resumindo
+			return nil
+		}
+
+		for _, t := range historyTables {
+			result, err := conn(ctx, r.DB).Exec(ctx, t.rollup, granularity, retention.Seconds())
+			if err != nil {
+				return fmt.Errorf("erro ao resumir %s: %w", t.table, err)
+			}
+			periods += result.RowsAffected()
+
+			if err := r.dropExpiredPartitions(ctx, t.table, granularity, retention); err != nil {
+				return err
+			}
+
+			query := fmt.Sprintf(`DELETE FROM %s WHERE record_date < %s`, t.table, rollupCutoff)
+			if _, err := conn(ctx, r.DB).Exec(ctx, query, granularity, retention.Seconds()); err != nil {
+				return fmt.Errorf("erro ao apagar o histórico resumido de %s: %w", t.table, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return periods, nil
+}
+
+// dropExpiredPartitions descarta as partições mensais que terminam antes do corte. Já
+// foram resumidas, e soltar a partição inteira sai bem mais barato que o DELETE.
+func (r *HistoryRepositoryImpl) dropExpiredPartitions(ctx context.Context, table, granularity string, retention time.Duration) error {
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = $3::text::regclass
+			AND c.relname ~ '_p[0-9]{6}$'
+			AND to_date(right(c.relname, 6), 'YYYYMM') + INTERVAL '1 month' <= ` + rollupCutoff
+	rows, err := conn(ctx, r.DB).Query(ctx, query, granularity, retention.Seconds(), table)
+	if err != nil {
+		return fmt.Errorf("erro ao listar partições de %s: %w", table, err)
+	}
+	partitions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("erro ao listar partições de %s: %w", table, err)
+	}
+
+	for _, partition := range partitions {
+		if _, err := conn(ctx, r.DB).Exec(ctx, "DROP TABLE "+pgx.Identifier{partition}.Sanitize()); err != nil {
+			return fmt.Errorf("erro ao descartar a partição %s: %w", partition, err)
+		}
+	}
+	return nil
+}
+
+// timelineRollupColumns são as colunas das views *_timeline que só vêm preenchidas nos resumos.
+const timelineRollupColumns = `granularity, period_end, samples, min_height, max_height, min_width, max_width, irrigation_count, fertilization_count`
+
+// timelineRollup recebe as colunas de resumo de uma linha da linha do tempo do histórico.
+type timelineRollup struct {
+	granularity        string
+	periodEnd          *time.Time
+	samples            *int
+	minHeight          *float64
+	maxHeight          *float64
+	minWidth           *float64
+	maxWidth           *float64
+	irrigationCount    *int
+	fertilizationCount *int
+}
+
+func (t *timelineRollup) dest() []interface{} {
+	return []interface{}{
+		&t.granularity, &t.periodEnd, &t.samples,
+		&t.minHeight, &t.maxHeight, &t.minWidth, &t.maxWidth,
+		&t.irrigationCount, &t.fertilizationCount,
+	}
+}
+
+// value devolve o resumo da linha, ou nil quando ela é um registro bruto.
+func (t *timelineRollup) value() *entities.HistoryRollup {
+	if t.granularity == entities.HistoryGranularityRaw {
+		return nil
+	}
+	return &entities.HistoryRollup{
+		Granularity:        t.granularity,
+		PeriodEnd:          deref(t.periodEnd),
+		Samples:            deref(t.samples),
+		MinHeight:          deref(t.minHeight),
+		MaxHeight:          deref(t.maxHeight),
+		MinWidth:           deref(t.minWidth),
+		MaxWidth:           deref(t.maxWidth),
+		IrrigationCount:    deref(t.irrigationCount),
+		FertilizationCount: deref(t.fertilizationCount),
+	}
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
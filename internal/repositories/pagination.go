@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// sortField é uma coluna liberada para ordenação. cast é o tipo usado para
+// comparar o valor que volta no cursor, que trafega como texto.
+type sortField struct {
+	column string
+	cast   string
+}
+
+type cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, entities.ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, entities.ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// keyset monta ORDER BY e a condição de continuação de uma listagem.
+// O id entra sempre como desempate para a ordem ser estável.
+type keyset struct {
+	sort     string
+	field    sortField
+	idColumn string
+	desc     bool
+	limit    int
+	after    *cursor
+}
+
+func newKeyset(page entities.PageRequest, fields map[string]sortField, defaultSort, idColumn string) (*keyset, error) {
+	page = page.Normalize()
+
+	sort := page.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+	field, ok := fields[sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", entities.ErrInvalidSort, sort)
+	}
+
+	var desc bool
+	switch strings.ToLower(page.Order) {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return nil, fmt.Errorf("%w: ordem %s", entities.ErrInvalidSort, page.Order)
+	}
+
+	k := &keyset{
+		sort:     sort,
+		field:    field,
+		idColumn: idColumn,
+		desc:     desc,
+		limit:    page.Limit,
+	}
+
+	if page.Cursor != "" {
+		c, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// um cursor só vale para a mesma ordenação que o gerou
+		if c.Sort != sort || c.Order != k.order() {
+			return nil, entities.ErrInvalidCursor
+		}
+		k.after = c
+	}
+	return k, nil
+}
+
+func (k *keyset) order() string {
+	if k.desc {
+		return "desc"
+	}
+	return "asc"
+}
+
+func (k *keyset) orderBy() string {
+	dir := "ASC"
+	if k.desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("%s %s, %s %s", k.field.column, dir, k.idColumn, dir)
+}
+
+// where devolve a condição de continuação usando placeholders a partir de next.
+func (k *keyset) where(next int) (string, []interface{}) {
+	if k.after == nil {
+		return "", nil
+	}
+	op := ">"
+	if k.desc {
+		op = "<"
+	}
+	cond := fmt.Sprintf(" AND (%s, %s) %s ($%d::%s, $%d::uuid)",
+		k.field.column, k.idColumn, op, next, k.field.cast, next+1)
+	return cond, []interface{}{k.after.Value, k.after.ID}
+}
+
+// sortValue é a expressão que devolve o valor de ordenação em texto para o próximo cursor.
+func (k *keyset) sortValue() string {
+	return k.field.column + "::text"
+}
+
+// next gera o cursor da página seguinte a partir do último item entregue.
+func (k *keyset) next(value, id string) string {
+	return encodeCursor(cursor{
+		Sort:  k.sort,
+		Order: k.order(),
+		Value: value,
+		ID:    id,
+	})
+}
+
+// pageQuery recorta uma página da tabela base em um CTE "page" com a posição de
+// cada linha; a consulta externa faz os joins e ordena por page.page_pos.
+// filter e args usam os placeholders a partir de $1.
+func (k *keyset) pageQuery(table, alias, filter string, args []interface{}) (string, []interface{}) {
+	cond, cursorArgs := k.where(len(args) + 1)
+	args = append(args, cursorArgs...)
+	args = append(args, k.limit+1)
+
+	query := fmt.Sprintf(`WITH page AS (
+		SELECT %[2]s.*, %[3]s AS sort_value, ROW_NUMBER() OVER (ORDER BY %[4]s) AS page_pos
+		FROM %[1]s %[2]s
+		WHERE %[5]s%[6]s
+		ORDER BY %[4]s
+		LIMIT $%[7]d
+	)`, table, alias, k.sortValue(), k.orderBy(), filter, cond, len(args))
+	return query, args
+}
+
+// count devolve o total de linhas do filtro, sem considerar o cursor.
+func count(ctx context.Context, db executor, table, alias, filter string, args []interface{}) (int, error) {
+	var total int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s %s WHERE %s", table, alias, filter)
+	if err := db.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("erro ao contar registros: %w", err)
+	}
+	return total, nil
+}
@@ -2,23 +2,23 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/go-redis/redis/v8"
-	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lucasBiazon/botany-back/internal/database"
 	"github.com/lucasBiazon/botany-back/internal/entities"
 )
 
 type PlantRepositoryImpl struct {
-	DB *sql.DB
+	DB *database.Cluster
 	RD *redis.Client
 }
 
-func NewPlantRepositoryImpl(db *sql.DB, rd *redis.Client) *PlantRepositoryImpl {
+func NewPlantRepositoryImpl(db *database.Cluster, rd *redis.Client) *PlantRepositoryImpl {
 	return &PlantRepositoryImpl{
 		DB: db,
 		RD: rd,
@@ -26,61 +26,39 @@ func NewPlantRepositoryImpl(db *sql.DB, rd *redis.Client) *PlantRepositoryImpl {
 }
 
 func (r *PlantRepositoryImpl) Create(ctx context.Context, plant *entities.Plant) (string, error) {
-	idParse, err := uuid.Parse(plant.Id)
+	workspaceId, err := writableWorkspace(ctx, r.DB, plant.UserId, plant.WorkspaceId)
 	if err != nil {
-		return "", fmt.Errorf("erro ao converter ID da planta: %w", err)
-	}
-
-	userIdParse, err := uuid.Parse(plant.UserId)
-	if err != nil {
-		return "", fmt.Errorf("erro ao converter ID do usuário: %w", err)
-	}
-
-	specieIdParse, err := uuid.Parse(plant.SpeciesId)
-	if err != nil {
-		return "", fmt.Errorf("erro ao converter ID da espécie: %w", err)
+		return "", err
 	}
+	plant.WorkspaceId = workspaceId
 
 	query := `INSERT INTO plants (id, plant_name, plant_description, planting_date,
 		estimated_harvest_date, plant_status, current_height, current_width,
 		irrigation_week, health_status, last_irrigation, last_fertilization,
-		sun_exposure, fertilization_week, user_id, species_id) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+		sun_exposure, fertilization_week, user_id, species_id, workspace_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+
+	err = withTx(ctx, r.DB, func(ctx context.Context) error {
+		_, err := conn(ctx, r.DB).Exec(ctx, query, plant.Id, plant.PlantName, plant.PlantDescription,
+			plant.PlantingDate, plant.EstimatedHarvestDate, plant.PlantStatus, plant.CurrentHeight,
+			plant.CurrentWidth, plant.IrrigationWeek, plant.HealthStatus, plant.LastIrrigation,
+			plant.LastFertilization, plant.SunExposure, plant.FertilizationWeek, plant.UserId, plant.SpeciesId, plant.WorkspaceId)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir planta: %w", err)
+		}
 
-	_, err = r.DB.ExecContext(ctx, query, idParse, plant.PlantName, plant.PlantDescription,
-		plant.PlantingDate, plant.EstimatedHarvestDate, plant.PlantStatus, plant.CurrentHeight,
-		plant.CurrentWidth, plant.IrrigationWeek, plant.HealthStatus, plant.LastIrrigation,
-		plant.LastFertilization, plant.SunExposure, plant.FertilizationWeek, userIdParse, specieIdParse)
+		var batch links
+		batch.add("plant_categories", "plant_id", "category_id", plant.Id, plant.CategoriesPlant)
+		return batch.send(ctx, conn(ctx, r.DB))
+	})
 	if err != nil {
-		return "", fmt.Errorf("erro ao inserir planta: %w", err)
-	}
-
-	if len(plant.CategoriesPlant) != 0 {
-		query = `INSERT INTO plant_categories (id, plant_id, category_id) VALUES ($1, $2, $3)`
-		for _, categoryItem := range plant.CategoriesPlant {
-			categoryId, err := uuid.Parse(categoryItem)
-			if err != nil {
-				return "", fmt.Errorf("erro ao converter ID da categoria: %w", err)
-			}
-			_, err = r.DB.ExecContext(ctx, query, uuid.New(), plant.Id, categoryId)
-			if err != nil {
-				return "", fmt.Errorf("erro ao inserir categoria: %w", err)
-			}
-		}
+		return "", err
 	}
 	return plant.Id, nil
 }
 
 func (r *PlantRepositoryImpl) FindByIDPG(ctx context.Context, userId, id string) (*entities.PlantWithCategory, error) {
 
-	idParse, err := uuid.Parse(id)
-	if err != nil {
-		return nil, err
-	}
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, err
-	}
 	query := `SELECT 
 		p.id AS plant_id,
 		p.plant_name,
@@ -97,23 +75,25 @@ func (r *PlantRepositoryImpl) FindByIDPG(ctx context.Context, userId, id string)
 		p.sun_exposure,
 		p.fertilization_week,
 		p.user_id,
+		p.workspace_id,
 		p.species_id,
 		p.created_at AS plant_created_at,
 		p.updated_at AS plant_updated_at,
+		p.version,
+		p.auto_tasks,
 		cp.id AS category_id,
 		cp.category_name
 	FROM 
 		plants p
 	LEFT JOIN 
-		plant_categories pc ON p.id = pc.plant_id
-	LEFT JOIN 
-		categories_plants cp ON pc.category_id = cp.id
+		(plant_categories pc JOIN categories_plants cp ON pc.category_id = cp.id AND cp.deleted_at IS NULL)
+		ON p.id = pc.plant_id
 	WHERE 
-		p.user_id = $1 AND p.id = $2;`
+		` + memberOf("p.workspace_id", "$1") + ` AND p.id = $2 AND p.deleted_at IS NULL;`
 
-	rows, err := r.DB.Query(query, userIdParse, idParse)
+	rows, err := reader(ctx, r.DB).Query(ctx, query, userId, id)
 	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
+		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
 	}
 	defer rows.Close()
 
@@ -121,7 +101,7 @@ func (r *PlantRepositoryImpl) FindByIDPG(ctx context.Context, userId, id string)
 	var categories []entities.CategoryPlant
 
 	for rows.Next() {
-		var category entities.CategoryPlant
+		var categoryId, categoryName pgtype.Text
 		tempPlant := entities.PlantWithCategory{}
 
 		err := rows.Scan(
@@ -140,22 +120,25 @@ func (r *PlantRepositoryImpl) FindByIDPG(ctx context.Context, userId, id string)
 			&tempPlant.SunExposure,
 			&tempPlant.FertilizationWeek,
 			&tempPlant.UserId,
+			&tempPlant.WorkspaceId,
 			&tempPlant.SpeciesId,
 			&tempPlant.PlantCreatedAt,
 			&tempPlant.PlantUpdatedAt,
-			&category.Id,
-			&category.Name,
+			&tempPlant.Version,
+			&tempPlant.AutoTasks,
+			&categoryId,
+			&categoryName,
 		)
 		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
+			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
 		}
 
 		if plant == nil {
 			plant = &tempPlant
 		}
 
-		if category.Id != "" {
-			categories = append(categories, category)
+		if categoryId.Valid {
+			categories = append(categories, entities.CategoryPlant{Id: categoryId.String, Name: categoryName.String})
 		}
 	}
 	if plant != nil {
@@ -163,7 +146,7 @@ func (r *PlantRepositoryImpl) FindByIDPG(ctx context.Context, userId, id string)
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
+		return nil, fmt.Errorf("erro durante a iteração: %w", err)
 	}
 
 	if plant == nil {
@@ -183,169 +166,72 @@ func (r *PlantRepositoryImpl) FindByID(ctx context.Context, userId, id string) (
 	return plant, nil
 }
 
-func (r *PlantRepositoryImpl) FindBySpeciesNamePG(ctx context.Context, userId, speciesName string) ([]*entities.PlantWithCategory, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, err
-	}
-	query := `
-		SELECT 
-			p.id AS plant_id,
-			p.plant_name,
-			p.plant_description,
-			p.planting_date,
-			p.estimated_harvest_date,
-			p.plant_status,
-			p.current_height,
-			p.current_width,
-			p.irrigation_week,
-			p.health_status,
-			p.last_irrigation,
-			p.last_fertilization,
-			p.sun_exposure,
-			p.fertilization_week,
-			p.user_id,
-			p.species_id,
-			p.created_at AS plant_created_at,
-			p.updated_at AS plant_updated_at,
-			cp.id AS category_id,
-			cp.category_name
-	FROM 
-			plants p
-	JOIN 
-			species s ON p.species_id = s.id
-	LEFT JOIN 
-			plant_categories pc ON p.id = pc.plant_id
-	LEFT JOIN 
-			categories_plants cp ON pc.category_id = cp.id
-	WHERE 
-			s.common_name ILIKE $1 AND p.user_id = $2;
-	`
-
-	rows, err := r.DB.Query(query, speciesName, userIdParse)
-	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
-	}
-	defer rows.Close()
-
-	plantMap := make(map[string]*entities.PlantWithCategory)
-
-	for rows.Next() {
-		var category entities.CategoryPlant
-		tempPlant := entities.PlantWithCategory{}
-
-		err := rows.Scan(
-			&tempPlant.PlantId,
-			&tempPlant.PlantName,
-			&tempPlant.PlantDescription,
-			&tempPlant.PlantingDate,
-			&tempPlant.EstimatedHarvestDate,
-			&tempPlant.PlantStatus,
-			&tempPlant.CurrentHeight,
-			&tempPlant.CurrentWidth,
-			&tempPlant.IrrigationWeek,
-			&tempPlant.HealthStatus,
-			&tempPlant.LastIrrigation,
-			&tempPlant.LastFertilization,
-			&tempPlant.SunExposure,
-			&tempPlant.FertilizationWeek,
-			&tempPlant.UserId,
-			&tempPlant.SpeciesId,
-			&tempPlant.PlantCreatedAt,
-			&tempPlant.PlantUpdatedAt,
-			&category.Id,
-			&category.Name,
-		)
-		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
-		}
-
-		existingPlant, exists := plantMap[tempPlant.PlantId]
-		if !exists {
-			existingPlant = &tempPlant
-			existingPlant.Category = []entities.CategoryPlant{}
-			plantMap[tempPlant.PlantId] = existingPlant
-		}
-
-		if category.Id != "" {
-			existingPlant.Category = append(existingPlant.Category, category)
-		}
-	}
-
-	var plantsWithCategories []*entities.PlantWithCategory
-	for _, plant := range plantMap {
-		plantsWithCategories = append(plantsWithCategories, plant)
-	}
-
-	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
-	}
-
-	if len(plantsWithCategories) == 0 {
-		log.Println("Nenhuma planta encontrada para os parâmetros fornecidos.")
-	}
-	return plantsWithCategories, nil
+var plantSortFields = map[string]sortField{
+	"name":          {column: "p.plant_name", cast: "text"},
+	"created_at":    {column: "p.created_at", cast: "timestamp"},
+	"planting_date": {column: "p.planting_date", cast: "timestamp"},
+	"harvest_date":  {column: "p.estimated_harvest_date", cast: "timestamp"},
 }
 
-func (r *PlantRepositoryImpl) FindBySpeciesName(ctx context.Context, userId, speciesName string) ([]*entities.PlantWithCategory, error) {
-	plants, err := r.FindBySpeciesNamePG(ctx, userId, speciesName)
+// listPlants pagina as plantas visíveis ao usuário que atendem filter; $1 é sempre o user_id.
+func (r *PlantRepositoryImpl) listPlants(ctx context.Context, filter string, args []interface{}, page entities.PageRequest) (*entities.Page[*entities.PlantWithCategory], error) {
+	ks, err := newKeyset(page, plantSortFields, "created_at", "p.id")
 	if err != nil {
 		return nil, err
 	}
-	if plants == nil {
-		return nil, nil
-	}
-
-	return plants, nil
-}
 
-func (r *PlantRepositoryImpl) FindByCategoryNamePG(ctx context.Context, userId, categoryName string) ([]*entities.PlantWithCategory, error) {
-	userIdParse, err := uuid.Parse(userId)
+	total, err := count(ctx, reader(ctx, r.DB), "plants", "p", filter, args)
 	if err != nil {
 		return nil, err
 	}
-	query := `
-		SELECT 
-    p.id AS plant_id,
-    p.plant_name,
-    p.plant_description,
-    p.planting_date,
-    p.estimated_harvest_date,
-    p.plant_status,
-    p.current_height,
-    p.current_width,
-    p.irrigation_week,
-    p.health_status,
-    p.last_irrigation,
-    p.last_fertilization,
-    p.sun_exposure,
-    p.fertilization_week,
-    p.user_id,
-    p.species_id,
-    p.created_at AS plant_created_at,
-    p.updated_at AS plant_updated_at,
-    c.id AS category_id,
-    c.category_name
-FROM 
-    plants p
-JOIN 
-    plant_categories pc ON p.id = pc.plant_id
-JOIN 
-    categories_plants c ON pc.category_id = c.id
-WHERE 
-    p.user_id = $1 AND c.category_name ILIKE $2;
-	`
 
-	rows, err := r.DB.Query(query, userIdParse, "%"+categoryName+"%")
+	pageQuery, queryArgs := ks.pageQuery("plants", "p", filter, args)
+	query := pageQuery + `
+	SELECT 
+		page.id,
+		page.plant_name,
+		page.plant_description,
+		page.planting_date,
+		page.estimated_harvest_date,
+		page.plant_status,
+		page.current_height,
+		page.current_width,
+		page.irrigation_week,
+		page.health_status,
+		page.last_irrigation,
+		page.last_fertilization,
+		page.sun_exposure,
+		page.fertilization_week,
+		page.user_id,
+		page.workspace_id,
+		page.species_id,
+		page.created_at,
+		page.updated_at,
+		page.version,
+		page.auto_tasks,
+		page.sort_value,
+		cp.id AS category_id,
+		cp.category_name
+	FROM 
+		page
+	LEFT JOIN 
+		(plant_categories pc JOIN categories_plants cp ON pc.category_id = cp.id AND cp.deleted_at IS NULL)
+		ON page.id = pc.plant_id
+	ORDER BY 
+		page.page_pos, cp.category_name;`
+
+	rows, err := reader(ctx, r.DB).Query(ctx, query, queryArgs...)
 	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
+		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
 	}
 	defer rows.Close()
 
-	plantMap := make(map[string]*entities.PlantWithCategory)
+	plants := make([]*entities.PlantWithCategory, 0)
+	sortValues := make(map[string]string)
 
 	for rows.Next() {
-		var category entities.CategoryPlant
+		var categoryId, categoryName pgtype.Text
+		var sortValue string
 		tempPlant := entities.PlantWithCategory{}
 
 		err := rows.Scan(
@@ -364,288 +250,130 @@ WHERE
 			&tempPlant.SunExposure,
 			&tempPlant.FertilizationWeek,
 			&tempPlant.UserId,
+			&tempPlant.WorkspaceId,
 			&tempPlant.SpeciesId,
 			&tempPlant.PlantCreatedAt,
 			&tempPlant.PlantUpdatedAt,
-			&category.Id,
-			&category.Name,
+			&tempPlant.Version,
+			&tempPlant.AutoTasks,
+			&sortValue,
+			&categoryId,
+			&categoryName,
 		)
 		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
+			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
 		}
 
-		existingPlant, exists := plantMap[tempPlant.PlantId]
-		if !exists {
-			existingPlant = &tempPlant
-			existingPlant.Category = []entities.CategoryPlant{}
-			plantMap[tempPlant.PlantId] = existingPlant
+		// as linhas chegam agrupadas por planta, na ordem da página
+		if len(plants) == 0 || plants[len(plants)-1].PlantId != tempPlant.PlantId {
+			tempPlant.Category = []entities.CategoryPlant{}
+			plants = append(plants, &tempPlant)
+			sortValues[tempPlant.PlantId] = sortValue
 		}
 
-		existingPlant.Category = append(existingPlant.Category, category)
-	}
-
-	var plantsWithCategories []*entities.PlantWithCategory
-	for _, plant := range plantMap {
-		plantsWithCategories = append(plantsWithCategories, plant)
+		if categoryId.Valid {
+			current := plants[len(plants)-1]
+			current.Category = append(current.Category, entities.CategoryPlant{
+				Id:   categoryId.String,
+				Name: categoryName.String,
+			})
+		}
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
-	}
-
-	if len(plantsWithCategories) == 0 {
-		log.Println("Nenhuma planta encontrada para os parâmetros fornecidos.")
+		return nil, fmt.Errorf("erro durante a iteração: %w", err)
 	}
 
-	return plantsWithCategories, nil
-}
-
-func (r *PlantRepositoryImpl) FindByCategoryName(ctx context.Context, userId, categoryName string) ([]*entities.PlantWithCategory, error) {
-
-	plants, err := r.FindByCategoryNamePG(ctx, userId, categoryName)
-	if err != nil {
-		return nil, err
+	result := &entities.Page[*entities.PlantWithCategory]{
+		Items: plants,
+		Total: total,
+		Limit: ks.limit,
 	}
-	if plants == nil {
-		return nil, nil
+	if len(plants) > ks.limit {
+		result.Items = plants[:ks.limit]
+		last := result.Items[ks.limit-1]
+		result.NextCursor = ks.next(sortValues[last.PlantId], last.PlantId)
 	}
-	return plants, nil
+	return result, nil
 }
 
-func (r *PlantRepositoryImpl) FindByNamePG(ctx context.Context, userId, plantName string) ([]*entities.PlantWithCategory, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, err
-	}
-	query := `
-		SELECT 
-			p.id AS plant_id,
-			p.plant_name,
-			p.plant_description,
-			p.planting_date,
-			p.estimated_harvest_date,
-			p.plant_status,
-			p.current_height,
-			p.current_width,
-			p.irrigation_week,
-			p.health_status,
-			p.last_irrigation,
-			p.last_fertilization,
-			p.sun_exposure,
-			p.fertilization_week,
-			p.user_id,
-			p.species_id,
-			p.created_at AS plant_created_at,
-			p.updated_at AS plant_updated_at,
-			c.id AS category_id,
-			c.category_name
-	FROM 
-			plants p
-	LEFT JOIN 
-			plant_categories pc ON p.id = pc.plant_id
-	LEFT JOIN 
-			categories_plants c ON pc.category_id = c.id
-	WHERE 
-			p.plant_name ILIKE $1 AND p.user_id = $2;
-	`
-
-	rows, err := r.DB.Query(query, "%"+plantName+"%", userIdParse)
-	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
-	}
-	defer rows.Close()
-
-	plantMap := make(map[string]*entities.PlantWithCategory)
-
-	for rows.Next() {
-		var category entities.CategoryPlant
-		tempPlant := entities.PlantWithCategory{}
-
-		err := rows.Scan(
-			&tempPlant.PlantId,
-			&tempPlant.PlantName,
-			&tempPlant.PlantDescription,
-			&tempPlant.PlantingDate,
-			&tempPlant.EstimatedHarvestDate,
-			&tempPlant.PlantStatus,
-			&tempPlant.CurrentHeight,
-			&tempPlant.CurrentWidth,
-			&tempPlant.IrrigationWeek,
-			&tempPlant.HealthStatus,
-			&tempPlant.LastIrrigation,
-			&tempPlant.LastFertilization,
-			&tempPlant.SunExposure,
-			&tempPlant.FertilizationWeek,
-			&tempPlant.UserId,
-			&tempPlant.SpeciesId,
-			&tempPlant.PlantCreatedAt,
-			&tempPlant.PlantUpdatedAt,
-			&category.Id,
-			&category.Name,
-		)
-		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
-		}
+// Search combina os filtros informados em uma única consulta paginada. Os filtros de
+// texto comparam o valor inteiro, sem diferenciar maiúsculas.
+func (r *PlantRepositoryImpl) Search(ctx context.Context, userId string, filter entities.PlantFilter, page entities.PageRequest) (*entities.Page[*entities.PlantWithCategory], error) {
+	return r.search(ctx, userId, filter, page, escapeLike)
+}
 
-		existingPlant, exists := plantMap[tempPlant.PlantId]
-		if !exists {
-			existingPlant = &tempPlant
-			existingPlant.Category = []entities.CategoryPlant{}
-			plantMap[tempPlant.PlantId] = existingPlant
-		}
+// search monta a consulta de Search; like transforma cada filtro de texto no padrão do ILIKE.
+func (r *PlantRepositoryImpl) search(ctx context.Context, userId string, filter entities.PlantFilter, page entities.PageRequest, like func(string) string) (*entities.Page[*entities.PlantWithCategory], error) {
 
-		if category.Id != "" {
-			existingPlant.Category = append(existingPlant.Category, category)
-		}
+	where := newWhere(memberOf("p.workspace_id", "?"), userId)
+	where.add("p.deleted_at IS NULL")
+	if filter.Name != "" {
+		where.add("f_unaccent(p.plant_name) ILIKE f_unaccent(?)", like(filter.Name))
 	}
-
-	var plantsWithCategories []*entities.PlantWithCategory
-	for _, plant := range plantMap {
-		plantsWithCategories = append(plantsWithCategories, plant)
+	if filter.HealthStatus != "" {
+		where.add("p.health_status ILIKE ?", like(filter.HealthStatus))
 	}
-
-	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
+	if filter.PlantStatus != "" {
+		where.add("p.plant_status ILIKE ?", like(filter.PlantStatus))
 	}
-
-	if len(plantsWithCategories) == 0 {
-		log.Println("Nenhuma planta encontrada para os parâmetros fornecidos.")
+	if filter.Species != "" {
+		where.add("p.species_id IN (SELECT s.id FROM species s WHERE f_unaccent(s.common_name) ILIKE f_unaccent(?))", like(filter.Species))
 	}
-
-	return plantsWithCategories, nil
-}
-
-func (r *PlantRepositoryImpl) FindByName(ctx context.Context, userId, name string) ([]*entities.PlantWithCategory, error) {
-	plants, err := r.FindByNamePG(ctx, userId, name)
-	if err != nil {
-		return nil, err
+	if filter.Category != "" {
+		where.add(`EXISTS (
+		SELECT 1 FROM plant_categories fpc
+		JOIN categories_plants fcp ON fpc.category_id = fcp.id
+		WHERE fpc.plant_id = p.id AND fcp.deleted_at IS NULL AND fcp.category_name ILIKE ?)`, like(filter.Category))
 	}
-	if plants == nil {
-		return nil, nil
+	if filter.Garden != "" {
+		where.add(`EXISTS (
+		SELECT 1 FROM garden_plant fgp
+		JOIN gardens fg ON fgp.garden_id = fg.id
+		WHERE fgp.plant_id = p.id AND fg.deleted_at IS NULL AND fg.garden_name ILIKE ?)`, like(filter.Garden))
 	}
-
-	return plants, nil
-}
-
-func (r *PlantRepositoryImpl) FindAllPG(ctx context.Context, userId string) ([]*entities.PlantWithCategory, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, err
+	if filter.PlantedFrom != nil {
+		where.add("p.planting_date >= ?", *filter.PlantedFrom)
 	}
-	query := `
-	SELECT 
-			p.id AS plant_id,
-			p.plant_name,
-			p.plant_description,
-			p.planting_date,
-			p.estimated_harvest_date,
-			p.plant_status,
-			p.current_height,
-			p.current_width,
-			p.irrigation_week,
-			p.health_status,
-			p.last_irrigation,
-			p.last_fertilization,
-			p.sun_exposure,
-			p.fertilization_week,
-			p.user_id,
-			p.species_id,
-			p.created_at AS plant_created_at,
-			p.updated_at AS plant_updated_at,
-			c.id AS category_id,
-			c.category_name
-	FROM 
-			plants p
-	LEFT JOIN 
-			plant_categories pc ON p.id = pc.plant_id
-	LEFT JOIN 
-			categories_plants c ON pc.category_id = c.id
-	WHERE 
-			p.user_id = $1;`
-
-	rows, err := r.DB.Query(query, userIdParse)
-	if err != nil {
-		log.Fatalf("Erro ao executar consulta: %v", err)
+	if filter.PlantedTo != nil {
+		where.add("p.planting_date <= ?", *filter.PlantedTo)
 	}
-	defer rows.Close()
-
-	plantMap := make(map[string]*entities.PlantWithCategory)
-
-	for rows.Next() {
-		var category entities.CategoryPlant
-		tempPlant := entities.PlantWithCategory{}
-
-		err := rows.Scan(
-			&tempPlant.PlantId,
-			&tempPlant.PlantName,
-			&tempPlant.PlantDescription,
-			&tempPlant.PlantingDate,
-			&tempPlant.EstimatedHarvestDate,
-			&tempPlant.PlantStatus,
-			&tempPlant.CurrentHeight,
-			&tempPlant.CurrentWidth,
-			&tempPlant.IrrigationWeek,
-			&tempPlant.HealthStatus,
-			&tempPlant.LastIrrigation,
-			&tempPlant.LastFertilization,
-			&tempPlant.SunExposure,
-			&tempPlant.FertilizationWeek,
-			&tempPlant.UserId,
-			&tempPlant.SpeciesId,
-			&tempPlant.PlantCreatedAt,
-			&tempPlant.PlantUpdatedAt,
-			&category.Id,
-			&category.Name,
-		)
-		if err != nil {
-			log.Fatalf("Erro ao escanear resultados: %v", err)
-		}
-
-		existingPlant, exists := plantMap[tempPlant.PlantId]
-		if !exists {
-			existingPlant = &tempPlant
-			existingPlant.Category = []entities.CategoryPlant{}
-			plantMap[tempPlant.PlantId] = existingPlant
-		}
-
-		if category.Id != "" {
-			existingPlant.Category = append(existingPlant.Category, category)
-		}
+	if filter.LastIrrigationBefore != nil {
+		where.add("p.last_irrigation < ?", *filter.LastIrrigationBefore)
 	}
 
-	var plantsWithCategories []*entities.PlantWithCategory
-	for _, plant := range plantMap {
-		plantsWithCategories = append(plantsWithCategories, plant)
-	}
+	return r.listPlants(ctx, where.sql(), where.args, page)
+}
 
-	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
-	}
+func (r *PlantRepositoryImpl) FindBySpeciesName(ctx context.Context, userId, speciesName string, page entities.PageRequest) (*entities.Page[*entities.PlantWithCategory], error) {
+	return r.search(ctx, userId, entities.PlantFilter{Species: speciesName}, page, containsLike)
+}
 
-	if len(plantsWithCategories) == 0 {
-		log.Println("Nenhuma planta encontrada para o usuário fornecido.")
-	}
+func (r *PlantRepositoryImpl) FindByCategoryName(ctx context.Context, userId, categoryName string, page entities.PageRequest) (*entities.Page[*entities.PlantWithCategory], error) {
+	return r.search(ctx, userId, entities.PlantFilter{Category: categoryName}, page, containsLike)
+}
 
-	return plantsWithCategories, nil
+func (r *PlantRepositoryImpl) FindByName(ctx context.Context, userId, name string, page entities.PageRequest) (*entities.Page[*entities.PlantWithCategory], error) {
+	return r.search(ctx, userId, entities.PlantFilter{Name: name}, page, containsLike)
 }
 
-func (r *PlantRepositoryImpl) FindAll(ctx context.Context, userId string) ([]*entities.PlantWithCategory, error) {
+func (r *PlantRepositoryImpl) FindAll(ctx context.Context, userId string, page entities.PageRequest) (*entities.Page[*entities.PlantWithCategory], error) {
+	return r.Search(ctx, userId, entities.PlantFilter{}, page)
+}
 
-	plants, err := r.FindAllPG(ctx, userId)
-	if err != nil {
-		return nil, err
-	}
-	if plants == nil {
-		return nil, nil
+func (r *PlantRepositoryImpl) NameInUse(ctx context.Context, userId, name, exceptId string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM plants
+		WHERE ` + memberOf("workspace_id", "$1") + ` AND deleted_at IS NULL
+			AND lower(plant_name) = lower($2) AND id <> $3)`
+	var inUse bool
+	// a checagem antecede uma escrita, então não pode ler de uma réplica atrasada
+	if err := primary(ctx, r.DB).QueryRow(ctx, query, userId, name, exceptId).Scan(&inUse); err != nil {
+		return false, fmt.Errorf("erro ao verificar nome da planta: %w", err)
 	}
-	return plants, nil
+	return inUse, nil
 }
 
 func (r *PlantRepositoryImpl) UpdatePlantPG(ctx context.Context, plant *entities.Plant) error {
-	tx, err := r.DB.Begin()
-	if err != nil {
-		return fmt.Errorf("erro ao iniciar transação: %v", err)
-	}
 
 	// Atualizar campos da planta
 	updatePlantQuery := `
@@ -664,67 +392,50 @@ func (r *PlantRepositoryImpl) UpdatePlantPG(ctx context.Context, plant *entities
             last_fertilization = $11,
             sun_exposure = $12,
             fertilization_week = $13,
-            updated_at = $14
-        WHERE id = $15;
-    `
-
-	_, err = tx.Exec(
-		updatePlantQuery,
-		plant.PlantName,
-		plant.PlantDescription,
-		plant.PlantingDate,
-		plant.EstimatedHarvestDate,
-		plant.PlantStatus,
-		plant.CurrentHeight,
-		plant.CurrentWidth,
-		plant.IrrigationWeek,
-		plant.HealthStatus,
-		plant.LastIrrigation,
-		plant.LastFertilization,
-		plant.SunExposure,
-		plant.FertilizationWeek,
-		time.Now(),
-		plant.Id,
-	)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("erro ao atualizar planta: %v", err)
-	}
-
-	deleteCategoriesQuery := `
-        DELETE FROM plant_categories
-        WHERE plant_id = $1;
+            updated_at = $14,
+            species_id = $18,
+            version = version + 1
+        WHERE id = $15 AND ` + editorOf("workspace_id", "$17") + ` AND deleted_at IS NULL AND version = $16;
     `
-	idParsed, err := uuid.Parse(plant.Id)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("erro ao converter ID da planta: %v", err)
-	}
-
-	_, err = tx.Exec(deleteCategoriesQuery, idParsed)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("erro ao remover categorias da planta: %v", err)
-	}
 
-	insertCategoryQuery := `
-        INSERT INTO plant_categories (id, plant_id, category_id)
-        VALUES ($1, $2, $3);
-    `
-
-	for _, categoryId := range plant.CategoriesPlant {
-		_, err = tx.Exec(insertCategoryQuery, uuid.New(), plant.Id, categoryId)
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		result, err := conn(ctx, r.DB).Exec(ctx,
+			updatePlantQuery,
+			plant.PlantName,
+			plant.PlantDescription,
+			plant.PlantingDate,
+			plant.EstimatedHarvestDate,
+			plant.PlantStatus,
+			plant.CurrentHeight,
+			plant.CurrentWidth,
+			plant.IrrigationWeek,
+			plant.HealthStatus,
+			plant.LastIrrigation,
+			plant.LastFertilization,
+			plant.SunExposure,
+			plant.FertilizationWeek,
+			time.Now(),
+			plant.Id,
+			plant.Version,
+			plant.UserId,
+			plant.SpeciesId,
+		)
 		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("erro ao adicionar categoria %v à planta: %v", categoryId, err)
+			return fmt.Errorf("erro ao atualizar planta: %v", err)
+		}
+		if result.RowsAffected() == 0 {
+			if err := writeDenied(ctx, r.DB, "plants", plant.Id, plant.UserId); err != nil {
+				return err
+			}
+			// outra escrita passou na frente desde a leitura feita pelo cliente
+			return entities.ErrVersionConflict
 		}
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("erro ao confirmar transação: %v", err)
-	}
 
-	return nil
+		var batch links
+		batch.replace("plant_categories", "plant_id", "category_id", "categories_plants", plant.Id)
+		batch.add("plant_categories", "plant_id", "category_id", plant.Id, plant.CategoriesPlant)
+		return batch.send(ctx, conn(ctx, r.DB))
+	})
 }
 
 func (r *PlantRepositoryImpl) Update(ctx context.Context, plant *entities.Plant) error {
@@ -741,17 +452,27 @@ func (r *PlantRepositoryImpl) DeletePlantPG(ctx context.Context, userID, plantID
 		log.Println("Erro: ID do usuário ou da planta não fornecido.")
 		return fmt.Errorf("ID do usuário ou da planta não fornecido")
 	}
+	// vai para a lixeira; histórico e vínculos só somem no expurgo
 	query := `
-		DELETE FROM plants
-		WHERE id = $1 AND user_id = $2
+		UPDATE plants SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND ` + editorOf("workspace_id", "$2") + ` AND deleted_at IS NULL
 	`
-	_, err := r.DB.ExecContext(ctx, query, plantID, userID)
+	err := withTx(ctx, r.DB, func(ctx context.Context) error {
+		result, err := conn(ctx, r.DB).Exec(ctx, query, plantID, userID)
+		if err != nil {
+			log.Printf("Erro ao deletar planta no PostgreSQL: %v\n", err)
+			return fmt.Errorf("erro ao deletar planta no PostgreSQL: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return writeDenied(ctx, r.DB, "plants", plantID, userID)
+		}
+		return cancelCareTasks(ctx, r.DB, entities.CareSubjectPlant, plantID)
+	})
 	if err != nil {
-		log.Printf("Erro ao deletar planta no PostgreSQL: %v\n", err)
-		return fmt.Errorf("erro ao deletar planta no PostgreSQL: %w", err)
+		return err
 	}
 
-	log.Println("Planta deletada com sucesso no PostgreSQL.")
+	log.Println("Planta movida para a lixeira no PostgreSQL.")
 	return nil
 }
 
@@ -776,20 +497,6 @@ func (r *PlantRepositoryImpl) Delete(ctx context.Context, userID, plantID string
 }
 
 func (r *PlantRepositoryImpl) CreateHistory(ctx context.Context, plant *entities.HistoryPlant) error {
-	idParse, err := uuid.Parse(plant.ID)
-	if err != nil {
-		return fmt.Errorf("erro ao converter ID da planta: %w", err)
-	}
-
-	plantIdParse, err := uuid.Parse(plant.PlantID)
-	if err != nil {
-		return fmt.Errorf("erro ao converter ID da planta: %w", err)
-	}
-
-	userIdParse, err := uuid.Parse(plant.UserID)
-	if err != nil {
-		return fmt.Errorf("erro ao converter ID do usuário: %w", err)
-	}
 
 	query := `
 	INSERT INTO history_plants (
@@ -798,9 +505,9 @@ func (r *PlantRepositoryImpl) CreateHistory(ctx context.Context, plant *entities
 	)
 	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
 
-	_, err = r.DB.ExecContext(ctx, query, idParse, plantIdParse, plant.IrrigationWeek, plant.RecordDate,
+	_, err := conn(ctx, r.DB).Exec(ctx, query, plant.ID, plant.PlantID, plant.IrrigationWeek, plant.RecordDate,
 		plant.Height, plant.Width, plant.HealthStatus, plant.Irrigation, plant.Fertilization,
-		plant.SunExposure, plant.FertilizationWeek, plant.Notes, userIdParse)
+		plant.SunExposure, plant.FertilizationWeek, plant.Notes, plant.UserID)
 	if err != nil {
 		return fmt.Errorf("erro ao inserir histórico da planta: %w", err)
 	}
@@ -808,23 +515,49 @@ func (r *PlantRepositoryImpl) CreateHistory(ctx context.Context, plant *entities
 	return nil
 }
 
-func (r *PlantRepositoryImpl) FindAllHistoryByPlantID(ctx context.Context, plantID string) ([]*entities.HistoryPlant, error) {
-	query := `
+var historySortFields = map[string]sortField{
+	"record_date": {column: "h.record_date", cast: "timestamp"},
+	"created_at":  {column: "h.created_at", cast: "timestamp"},
+}
+
+func (r *PlantRepositoryImpl) FindAllHistoryByPlantID(ctx context.Context, userId, plantID string, page entities.PageRequest) (*entities.Page[*entities.HistoryPlant], error) {
+
+	ks, err := newKeyset(page, historySortFields, "record_date", "h.id")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := `h.plant_id = $1 AND EXISTS (SELECT 1 FROM plants p
+		WHERE p.id = h.plant_id AND p.deleted_at IS NULL AND ` + memberOf("p.workspace_id", "$2") + `)`
+	args := []interface{}{plantID, userId}
+	total, err := count(ctx, reader(ctx, r.DB), "history_plants_timeline", "h", filter, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// a linha do tempo junta os registros brutos com os resumos do que saiu da retenção
+	pageQuery, queryArgs := ks.pageQuery("history_plants_timeline", "h", filter, args)
+	query := pageQuery + `
 		SELECT id, plant_id, irrigation_week, record_date, height, width, health_status, 
-			irrigation, fertilization, sun_exposure, fertilization_week, notes, user_id
-		FROM history_plants WHERE plant_id = $1
+			irrigation, fertilization, sun_exposure, fertilization_week, notes, user_id, sort_value,
+			care_type, amount, ` + timelineRollupColumns + `
+		FROM page ORDER BY page_pos
 	`
 
-	rows, err := r.DB.QueryContext(ctx, query, plantID)
+	rows, err := reader(ctx, r.DB).Query(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var historyPlants []*entities.HistoryPlant
+	historyPlants := make([]*entities.HistoryPlant, 0)
+	sortValues := make([]string, 0)
 	for rows.Next() {
 		var historyPlant entities.HistoryPlant
-		err := rows.Scan(
+		var sortValue string
+		var careType *string
+		var rollup timelineRollup
+		dest := []interface{}{
 			&historyPlant.ID,
 			&historyPlant.PlantID,
 			&historyPlant.IrrigationWeek,
@@ -838,12 +571,108 @@ func (r *PlantRepositoryImpl) FindAllHistoryByPlantID(ctx context.Context, plant
 			&historyPlant.FertilizationWeek,
 			&historyPlant.Notes,
 			&historyPlant.UserID,
-		)
-		if err != nil {
+			&sortValue,
+			&careType,
+			&historyPlant.Amount,
+		}
+		if err := rows.Scan(append(dest, rollup.dest()...)...); err != nil {
 			return nil, err
 		}
+		historyPlant.CareType = deref(careType)
+		historyPlant.Rollup = rollup.value()
 		historyPlants = append(historyPlants, &historyPlant)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &entities.Page[*entities.HistoryPlant]{
+		Items: historyPlants,
+		Total: total,
+		Limit: ks.limit,
+	}
+	if len(historyPlants) > ks.limit {
+		result.Items = historyPlants[:ks.limit]
+		last := result.Items[ks.limit-1]
+		result.NextCursor = ks.next(sortValues[ks.limit-1], last.ID)
+	}
+	return result, nil
+}
+
+func (r *PlantRepositoryImpl) FindGrowthSamples(ctx context.Context, plantID string) ([]*entities.GrowthSample, error) {
+	query := `
+		SELECT record_date, COALESCE(height, 0), COALESCE(width, 0)
+		FROM history_plants_timeline
+		WHERE plant_id = $1 AND (height > 0 OR width > 0)
+		ORDER BY record_date`
+	rows, err := reader(ctx, r.DB).Query(ctx, query, plantID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar medições da planta: %w", err)
+	}
+	defer rows.Close()
+
+	samples := make([]*entities.GrowthSample, 0)
+	for rows.Next() {
+		var sample entities.GrowthSample
+		if err := rows.Scan(&sample.Date, &sample.Height, &sample.Width); err != nil {
+			return nil, fmt.Errorf("erro ao ler medições da planta: %w", err)
+		}
+		samples = append(samples, &sample)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler medições da planta: %w", err)
+	}
+	return samples, nil
+}
+
+func (r *PlantRepositoryImpl) SetAutoTasks(ctx context.Context, userId, id string, enabled bool) error {
+	query := `UPDATE plants SET auto_tasks = $1, version = version + 1
+		WHERE id = $2 AND ` + editorOf("workspace_id", "$3") + ` AND deleted_at IS NULL`
+
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		result, err := conn(ctx, r.DB).Exec(ctx, query, enabled, id, userId)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar tarefas automáticas da planta: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			if err := writeDenied(ctx, r.DB, "plants", id, userId); err != nil {
+				return err
+			}
+			return errors.New("planta não encontrada")
+		}
+		if enabled {
+			return nil
+		}
+		return cancelCareTasks(ctx, r.DB, entities.CareSubjectPlant, id)
+	})
+}
 
-	return historyPlants, nil
+// plantCareQuery aplica o cuidado e grava o histórico com os valores já atualizados.
+// Um cuidado com data anterior à última irrigação ou adubação não a faz voltar.
+var plantCareQuery = `
+	WITH cared AS (
+		UPDATE plants p SET
+			last_irrigation = CASE WHEN $3::text = 'irrigation' THEN GREATEST(p.last_irrigation, $4::timestamp) ELSE p.last_irrigation END,
+			last_fertilization = CASE WHEN $3::text = 'fertilization' THEN GREATEST(p.last_fertilization, $4::timestamp) ELSE p.last_fertilization END,
+			current_height = COALESCE($6::numeric, p.current_height),
+			current_width = COALESCE($7::numeric, p.current_width),
+			updated_at = CURRENT_TIMESTAMP,
+			version = p.version + 1
+		WHERE p.id = ANY($1::uuid[]) AND ` + editorOf("p.workspace_id", "$2") + ` AND p.deleted_at IS NULL
+		RETURNING p.id, p.irrigation_week, p.current_height, p.current_width, p.health_status, p.sun_exposure, p.fertilization_week
+	)
+	INSERT INTO history_plants (
+		id, plant_id, irrigation_week, record_date, height, width, health_status,
+		irrigation, fertilization, sun_exposure, fertilization_week, notes, user_id, care_type, amount
+	)
+	SELECT gen_random_uuid(), id, irrigation_week, $4::timestamp, current_height, current_width, health_status,
+		$3::text = 'irrigation', $3::text = 'fertilization', sun_exposure, fertilization_week, $8::text, $2::uuid, $3::text, $5::numeric
+	FROM cared
+	RETURNING plant_id::text`
+
+func (r *PlantRepositoryImpl) LogCare(ctx context.Context, care *entities.CareLog, ids []string) error {
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		return logCare(ctx, r.DB, "plants", entities.CareSubjectPlant, plantCareQuery, care, ids)
+	})
 }
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// relation monta um subselect que devolve as linhas ligadas a cada pai como um
+// array JSON, uma linha por pai. Com LEFT JOINs cada relação a mais multiplicava
+// as linhas do pai; aqui cada relacionado aparece uma vez, mesmo com vínculo repetido.
+// fields são os pares chave/coluna do jsonb_build_object, com a tabela apelidada de x.
+// Relacionados na lixeira ficam de fora, mas o vínculo continua para a restauração.
+func relation(parentID, link, parentColumn, childColumn, table, fields string) string {
+	return fmt.Sprintf(`COALESCE((
+			SELECT jsonb_agg(DISTINCT jsonb_build_object(%s))
+			FROM %s l JOIN %s x ON x.id = l.%s AND x.deleted_at IS NULL
+			WHERE l.%s = %s
+		), '[]'::jsonb)`, fields, link, table, childColumn, parentColumn, parentID)
+}
+
+// scanRelation decodifica um array montado por relation.
+func scanRelation(raw []byte, target interface{}) error {
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("erro ao decodificar relacionamentos: %w", err)
+	}
+	return nil
+}
+
+// links acumula as escritas em tabelas de vínculo para enviar tudo em um único
+// round trip com pgx.Batch, em vez de um Exec por linha.
+type links struct {
+	batch pgx.Batch
+}
+
+// replace apaga os vínculos atuais do pai na tabela antes dos novos. Os vínculos
+// com relacionados que estão na lixeira ficam, já que o cliente não os enxerga
+// para reenviar e eles precisam voltar junto com a restauração.
+func (l *links) replace(table, parentColumn, childColumn, childTable, parentID string) {
+	query := fmt.Sprintf(`DELETE FROM %[1]s l WHERE l.%[2]s = $1
+		AND NOT EXISTS (SELECT 1 FROM %[4]s x WHERE x.id = l.%[3]s AND x.deleted_at IS NOT NULL)`,
+		table, parentColumn, childColumn, childTable)
+	l.batch.Queue(query, parentID)
+}
+
+func (l *links) add(table, parentColumn, childColumn, parentID string, childIDs []string) {
+	query := fmt.Sprintf("INSERT INTO %s (id, %s, %s) VALUES ($1, $2, $3)", table, parentColumn, childColumn)
+	for _, childID := range childIDs {
+		l.batch.Queue(query, uuid.NewString(), parentID, childID)
+	}
+}
+
+func (l *links) send(ctx context.Context, db executor) error {
+	if l.batch.Len() == 0 {
+		return nil
+	}
+	results := db.SendBatch(ctx, &l.batch)
+	for i := 0; i < l.batch.Len(); i++ {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("erro ao gravar vínculos: %w", err)
+		}
+	}
+	return results.Close()
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type SearchRepositoryImpl struct {
+	DB *database.Cluster
+}
+
+func NewSearchRepository(db *database.Cluster) *SearchRepositoryImpl {
+	return &SearchRepositoryImpl{DB: db}
+}
+
+// Search combina full-text em português (sem acentos) com similaridade de trigramas
+// nos nomes, para que "feijao" encontre "Feijão" e erros de digitação ainda casem.
+// $1 é o usuário, $2 o termo e $3 o limite.
+func (r *SearchRepositoryImpl) Search(ctx context.Context, userId, query string, limit int) ([]*entities.SearchResult, error) {
+
+	sqlQuery := `
+	WITH q AS (
+		SELECT websearch_to_tsquery('portuguese', f_unaccent($2)) AS query, f_unaccent($2) AS term
+	)
+	SELECT type, id, title, snippet, parent_id, rank FROM (
+		SELECT 'plant' AS type, p.id::text AS id, p.plant_name AS title,
+			left(p.plant_description, 160) AS snippet, '' AS parent_id,
+			ts_rank(p.search_vector, q.query) + similarity(f_unaccent(p.plant_name), q.term) AS rank
+		FROM plants p, q
+		WHERE ` + memberOf("p.workspace_id", "$1") + ` AND p.deleted_at IS NULL AND (p.search_vector @@ q.query OR f_unaccent(p.plant_name) % q.term)
+
+		UNION ALL
+		SELECT 'garden', g.id::text, g.garden_name, left(g.garden_description, 160), '',
+			ts_rank(g.search_vector, q.query) + similarity(f_unaccent(g.garden_name), q.term)
+		FROM gardens g, q
+		WHERE ` + memberOf("g.workspace_id", "$1") + ` AND g.deleted_at IS NULL AND (g.search_vector @@ q.query OR f_unaccent(g.garden_name) % q.term)
+
+		UNION ALL
+		SELECT 'task', t.id::text, t.task_name, left(t.task_description, 160), '',
+			ts_rank(t.search_vector, q.query) + similarity(f_unaccent(t.task_name), q.term)
+		FROM tasks t, q
+		WHERE ` + memberOf("t.workspace_id", "$1") + ` AND t.deleted_at IS NULL AND (t.search_vector @@ q.query OR f_unaccent(t.task_name) % q.term)
+
+		UNION ALL
+		SELECT 'plant_note', hp.id::text, p.plant_name, left(hp.notes, 160), hp.plant_id::text,
+			ts_rank(hp.search_vector, q.query)
+		FROM history_plants hp JOIN plants p ON p.id = hp.plant_id, q
+		WHERE ` + memberOf("p.workspace_id", "$1") + ` AND p.deleted_at IS NULL AND hp.search_vector @@ q.query
+
+		UNION ALL
+		SELECT 'garden_note', hg.id::text, g.garden_name, left(hg.notes, 160), hg.garden_id::text,
+			ts_rank(hg.search_vector, q.query)
+		FROM history_gardens hg JOIN gardens g ON g.id = hg.garden_id, q
+		WHERE ` + memberOf("g.workspace_id", "$1") + ` AND g.deleted_at IS NULL AND hg.search_vector @@ q.query
+
+		UNION ALL
+		SELECT 'species', s.id::text, s.common_name, s.scientific_name, '',
+			ts_rank(s.search_vector, q.query) + greatest(
+				similarity(f_unaccent(s.common_name), q.term),
+				similarity(f_unaccent(s.scientific_name), q.term))
+		FROM species s, q
+		WHERE s.search_vector @@ q.query
+			OR f_unaccent(s.common_name) % q.term
+			OR f_unaccent(s.scientific_name) % q.term
+	) results
+	ORDER BY rank DESC, title
+	LIMIT $3;`
+
+	return r.query(ctx, sqlQuery, userId, query, limit)
+}
+
+// Autocomplete sugere nomes que contenham o prefixo digitado, priorizando os que
+// começam com ele.
+func (r *SearchRepositoryImpl) Autocomplete(ctx context.Context, userId, prefix string, limit int) ([]*entities.SearchResult, error) {
+
+	sqlQuery := `
+	WITH q AS (
+		SELECT f_unaccent($2) AS term, '%' || f_unaccent($2) || '%' AS pattern
+	)
+	SELECT type, id, title, snippet, parent_id, rank FROM (
+		SELECT 'plant' AS type, p.id::text AS id, p.plant_name AS title, '' AS snippet, '' AS parent_id,
+			(f_unaccent(p.plant_name) ILIKE q.term || '%')::int + similarity(f_unaccent(p.plant_name), q.term) AS rank
+		FROM plants p, q
+		WHERE ` + memberOf("p.workspace_id", "$1") + ` AND p.deleted_at IS NULL AND f_unaccent(p.plant_name) ILIKE q.pattern
+
+		UNION ALL
+		SELECT 'garden', g.id::text, g.garden_name, '', '',
+			(f_unaccent(g.garden_name) ILIKE q.term || '%')::int + similarity(f_unaccent(g.garden_name), q.term)
+		FROM gardens g, q
+		WHERE ` + memberOf("g.workspace_id", "$1") + ` AND g.deleted_at IS NULL AND f_unaccent(g.garden_name) ILIKE q.pattern
+
+		UNION ALL
+		SELECT 'task', t.id::text, t.task_name, '', '',
+			(f_unaccent(t.task_name) ILIKE q.term || '%')::int + similarity(f_unaccent(t.task_name), q.term)
+		FROM tasks t, q
+		WHERE ` + memberOf("t.workspace_id", "$1") + ` AND t.deleted_at IS NULL AND f_unaccent(t.task_name) ILIKE q.pattern
+
+		UNION ALL
+		SELECT 'species', s.id::text, s.common_name, s.scientific_name, '',
+			(f_unaccent(s.common_name) ILIKE q.term || '%')::int + similarity(f_unaccent(s.common_name), q.term)
+		FROM species s, q
+		WHERE f_unaccent(s.common_name) ILIKE q.pattern OR f_unaccent(s.scientific_name) ILIKE q.pattern
+	) results
+	ORDER BY rank DESC, title
+	LIMIT $3;`
+
+	return r.query(ctx, sqlQuery, userId, escapeLike(prefix), limit)
+}
+
+func (r *SearchRepositoryImpl) query(ctx context.Context, query string, args ...interface{}) ([]*entities.SearchResult, error) {
+	rows, err := reader(ctx, r.DB).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao executar busca: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*entities.SearchResult, 0)
+	for rows.Next() {
+		var result entities.SearchResult
+		var snippet pgtype.Text
+		if err := rows.Scan(&result.Type, &result.Id, &result.Title, &snippet, &result.ParentId, &result.Rank); err != nil {
+			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
+		}
+		result.Snippet = snippet.String
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro durante a iteração: %w", err)
+	}
+	return results, nil
+}
+
+// escapeLike impede que % e _ digitados pelo usuário virem curingas no ILIKE.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// containsLike é o padrão das buscas por trecho do texto.
+func containsLike(value string) string {
+	return "%" + escapeLike(value) + "%"
+}
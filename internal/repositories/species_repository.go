@@ -2,30 +2,32 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
-	"encoding/json"
-	"time"
+	"fmt"
 
-	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lucasBiazon/botany-back/internal/database"
 	"github.com/lucasBiazon/botany-back/internal/entities"
 )
 
 type SpeciesRepositoryImpl struct {
-	DB *sql.DB
-	RD *redis.Client
+	DB *database.Cluster
 }
 
-func NewSpeciesRepository(db *sql.DB, rd *redis.Client) *SpeciesRepositoryImpl {
+func NewSpeciesRepository(db *database.Cluster) *SpeciesRepositoryImpl {
 	return &SpeciesRepositoryImpl{
-		RD: rd,
 		DB: db,
 	}
 }
 
-func (r *SpeciesRepositoryImpl) FindAllPG(ctx context.Context) ([]*entities.Specie, error) {
-	query := `SELECT * FROM species`
-	rows, err := r.DB.QueryContext(ctx, query)
+// speciesColumns segue a ordem dos Scan de Specie; a tabela tem colunas que não entram na entidade.
+const speciesColumns = `id, common_name, specie_description, scientific_name, botanical_family, growth_type,
+	ideal_temperature, ideal_climate, life_cycle, planting_season, harvest_time, average_height,
+	average_width, irrigation_weight, fertilization_weight, sun_weight, image_url, created_at, updated_at`
+
+func (r *SpeciesRepositoryImpl) FindAll(ctx context.Context) ([]*entities.Specie, error) {
+	query := `SELECT ` + speciesColumns + ` FROM species`
+	rows, err := reader(ctx, r.DB).Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -48,80 +50,18 @@ func (r *SpeciesRepositoryImpl) FindAllPG(ctx context.Context) ([]*entities.Spec
 	return species, nil
 }
 
-func (r *SpeciesRepositoryImpl) FindAllRD(ctx context.Context) ([]*entities.Specie, error) {
-	key := "species:" + "all"
-	species, err := r.RD.HGetAll(ctx, key).Result()
-	if err == redis.Nil {
-		return []*entities.Specie{}, nil
-	} else if err != nil {
-		return nil, err
-	}
-
-	var speciesList []*entities.Specie
-	for _, specie := range species {
-		var specieParsed entities.Specie
-		err := json.Unmarshal([]byte(specie), &specieParsed)
-		if err != nil {
-			return nil, err
-		}
-		speciesList = append(speciesList, &specieParsed)
-	}
-	return speciesList, nil
-}
-
-func (r *SpeciesRepositoryImpl) SetAllRD(ctx context.Context, species []*entities.Specie) error {
-	key := "species:" + "all"
-	data := make(map[string]interface{})
-	for _, specie := range species {
-		value, err := json.Marshal(specie)
-		if err != nil {
-			return err
-		}
-		data[specie.ID] = value
-	}
-
-	err := r.RD.HMSet(ctx, key, data).Err()
-	if err != nil {
-		return err
-	}
-
-	return r.RD.Expire(ctx, key, 20*time.Minute).Err()
-}
-
-func (r *SpeciesRepositoryImpl) FindAll(ctx context.Context) ([]*entities.Specie, error) {
-	species, err := r.FindAllRD(ctx)
-	if err != nil {
-		return nil, err
-	}
-	if len(species) == 0 {
-		species, err = r.FindAllPG(ctx)
-		if err != nil {
-			return nil, err
-		}
-		err = r.SetAllRD(ctx, species)
-		if err != nil {
-			return nil, err
-		}
-	}
-	return species, nil
-}
-
-func (r *SpeciesRepositoryImpl) FindByIDPG(ctx context.Context, id string) (*entities.Specie, error) {
-	idParse, err := uuid.Parse(id)
-	if err != nil {
-		return nil, err
-	}
-	query := `SELECT * FROM species WHERE id = $1`
-	row := r.DB.QueryRowContext(ctx, query, idParse)
+func (r *SpeciesRepositoryImpl) FindById(ctx context.Context, id string) (*entities.Specie, error) {
+	query := `SELECT ` + speciesColumns + ` FROM species WHERE id = $1`
+	row := reader(ctx, r.DB).QueryRow(ctx, query, id)
 	var specie entities.Specie
-	err = row.Scan(&specie.ID, &specie.CommonName,
+	err := row.Scan(&specie.ID, &specie.CommonName,
 		&specie.SpecieDescription, &specie.ScientificName, &specie.BotanicalFamily, &specie.GrowthType,
 		&specie.IdealTemperature, &specie.IdealClimate, &specie.LifeCycle,
 		&specie.PlantingSeason, &specie.HarvestTime, &specie.AverageHeight,
 		&specie.AverageWidth, &specie.IrrigationWeight, &specie.FertilizationWeight,
 		&specie.SunWeight, &specie.ImageURL, &specie.CreatedAt, &specie.UpdatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if err == pgx.ErrNoRows {
 			return nil, nil // Nenhum registro encontrado
 		}
 		return nil, err
@@ -129,62 +69,9 @@ func (r *SpeciesRepositoryImpl) FindByIDPG(ctx context.Context, id string) (*ent
 	return &specie, nil
 }
 
-func (r *SpeciesRepositoryImpl) FindByIDRD(ctx context.Context, id string) (*entities.Specie, error) {
-	key := "species:" + id
-	specie, err := r.RD.Get(ctx, key).Result()
-	if err == redis.Nil {
-		return nil, nil
-	} else if err != nil {
-		return nil, err
-	}
-
-	var specieParsed entities.Specie
-	err = json.Unmarshal([]byte(specie), &specieParsed)
-	if err != nil {
-		return nil, err
-	}
-	return &specieParsed, nil
-}
-
-func (r *SpeciesRepositoryImpl) SetByIDRD(ctx context.Context, specie *entities.Specie) error {
-	key := "species:" + specie.ID
-	value, err := json.Marshal(specie)
-	if err != nil {
-		return err
-	}
-
-	err = r.RD.Set(ctx, key, value, 20*time.Minute).Err()
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (r *SpeciesRepositoryImpl) FindById(ctx context.Context, id string) (*entities.Specie, error) {
-	specie, err := r.FindByIDRD(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if specie == nil {
-		specie, err = r.FindByIDPG(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		if specie == nil {
-			return nil, nil
-		}
-		err = r.SetByIDRD(ctx, specie)
-		if err != nil {
-			return nil, err
-		}
-	}
-	return specie, nil
-}
-
-func (r *SpeciesRepositoryImpl) FindByNamePG(ctx context.Context, common_name string) ([]*entities.Specie, error) {
-	query := `SELECT * FROM species WHERE common_name ILIKE $1 `
-	rows, err := r.DB.QueryContext(ctx, query, "%"+common_name+"%")
+func (r *SpeciesRepositoryImpl) FindByName(ctx context.Context, common_name string) ([]*entities.Specie, error) {
+	query := `SELECT ` + speciesColumns + ` FROM species WHERE f_unaccent(common_name) ILIKE f_unaccent($1)`
+	rows, err := reader(ctx, r.DB).Query(ctx, query, "%"+common_name+"%")
 	if err != nil {
 		return nil, err
 	}
@@ -206,59 +93,44 @@ func (r *SpeciesRepositoryImpl) FindByNamePG(ctx context.Context, common_name st
 	return species, nil
 }
 
-func (r *SpeciesRepositoryImpl) FindByNameRD(ctx context.Context, name string) ([]*entities.Specie, error) {
-	key := "species:" + "name:" + name
-	species, err := r.RD.HGetAll(ctx, key).Result()
-	if err == redis.Nil {
-		return []*entities.Specie{}, nil
-	} else if err != nil {
-		return nil, err
-	}
-
-	var speciesList []*entities.Specie
-	for _, specie := range species {
-		var specieParsed entities.Specie
-		err := json.Unmarshal([]byte(specie), &specieParsed)
-		if err != nil {
-			return nil, err
+func (r *SpeciesRepositoryImpl) Upsert(ctx context.Context, species []*entities.Specie) error {
+	query := `INSERT INTO species (id, common_name, specie_description, scientific_name, botanical_family, growth_type,
+		ideal_temperature, ideal_climate, life_cycle, planting_season, harvest_time, average_height,
+		average_width, irrigation_weight, fertilization_weight, sun_weight, image_url)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	ON CONFLICT (scientific_name) DO UPDATE SET
+		common_name = EXCLUDED.common_name,
+		specie_description = EXCLUDED.specie_description,
+		botanical_family = EXCLUDED.botanical_family,
+		growth_type = EXCLUDED.growth_type,
+		ideal_temperature = EXCLUDED.ideal_temperature,
+		ideal_climate = EXCLUDED.ideal_climate,
+		life_cycle = EXCLUDED.life_cycle,
+		planting_season = EXCLUDED.planting_season,
+		harvest_time = EXCLUDED.harvest_time,
+		average_height = EXCLUDED.average_height,
+		average_width = EXCLUDED.average_width,
+		irrigation_weight = EXCLUDED.irrigation_weight,
+		fertilization_weight = EXCLUDED.fertilization_weight,
+		sun_weight = EXCLUDED.sun_weight,
+		image_url = EXCLUDED.image_url,
+		updated_at = CURRENT_TIMESTAMP`
+
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		var batch pgx.Batch
+		for _, specie := range species {
+			batch.Queue(query, uuid.NewString(), specie.CommonName, specie.SpecieDescription, specie.ScientificName,
+				specie.BotanicalFamily, specie.GrowthType, specie.IdealTemperature, specie.IdealClimate, specie.LifeCycle,
+				specie.PlantingSeason, specie.HarvestTime, specie.AverageHeight, specie.AverageWidth,
+				specie.IrrigationWeight, specie.FertilizationWeight, specie.SunWeight, specie.ImageURL)
 		}
-		speciesList = append(speciesList, &specieParsed)
-	}
-	return speciesList, nil
-}
-
-func (r *SpeciesRepositoryImpl) SetByNameRD(ctx context.Context, name string, species []*entities.Specie) error {
-	for _, specie := range species {
-		key := "species:" + "name:" + name
-
-		value, err := json.Marshal(specie)
-		if err != nil {
-			return err
-		}
-
-		err = r.RD.Set(ctx, key, value, 20*time.Minute).Err()
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (r *SpeciesRepositoryImpl) FindByName(ctx context.Context, common_name string) ([]*entities.Specie, error) {
-	species, err := r.FindByNameRD(ctx, common_name)
-	if err != nil {
-		return nil, err
-	}
-	if len(species) == 0 {
-		species, err = r.FindByNamePG(ctx, common_name)
-		if err != nil {
-			return nil, err
-		}
-		err = r.SetByNameRD(ctx, common_name, species)
-		if err != nil {
-			return nil, err
+		results := conn(ctx, r.DB).SendBatch(ctx, &batch)
+		for _, specie := range species {
+			if _, err := results.Exec(); err != nil {
+				results.Close()
+				return fmt.Errorf("erro ao gravar espécie %s: %w", specie.ScientificName, err)
+			}
 		}
-	}
-	return species, nil
+		return results.Close()
+	})
 }
@@ -2,809 +2,319 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 
-	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/lucasBiazon/botany-back/internal/database"
 	"github.com/lucasBiazon/botany-back/internal/entities"
 )
 
 type TaskRepositoryImpl struct {
-	DB *sql.DB
+	DB *database.Cluster
 }
 
-func NewTaskRepository(db *sql.DB) *TaskRepositoryImpl {
+func NewTaskRepository(db *database.Cluster) *TaskRepositoryImpl {
 	return &TaskRepositoryImpl{DB: db}
 }
 
 func (r *TaskRepositoryImpl) Create(ctx context.Context, task *entities.Task) (string, error) {
-	query := `INSERT INTO tasks (id, task_name, task_description, date_task, urgency_level, task_status, user_id)
-	 VALUES ($1, $2, $3, $4, $5, $6, $7)`
-
-	idParsed, err := uuid.Parse(task.Id)
+	workspaceId, err := writableWorkspace(ctx, r.DB, task.UserId, task.WorkspaceId)
 	if err != nil {
-		return "", errors.New("invalid id")
+		return "", err
 	}
+	task.WorkspaceId = workspaceId
 
-	userIdParsed, err := uuid.Parse(task.UserId)
-	if err != nil {
-		return "", errors.New("invalid user id")
-	}
+	query := `INSERT INTO tasks (id, task_name, task_description, date_task, urgency_level, task_status, user_id, workspace_id)
+	 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 
-	_, err = r.DB.ExecContext(ctx, query, idParsed, task.Name, task.Description, task.TaskDate, task.UrgencyLevel, task.TaskStatus, userIdParsed)
+	err = withTx(ctx, r.DB, func(ctx context.Context) error {
+		_, err := conn(ctx, r.DB).Exec(ctx, query, task.Id, task.Name, task.Description, task.TaskDate, task.UrgencyLevel, task.TaskStatus, task.UserId, task.WorkspaceId)
+		if err != nil {
+			return err
+		}
+		var batch links
+		taskLinks(&batch, task)
+		return batch.send(ctx, conn(ctx, r.DB))
+	})
 	if err != nil {
 		return "", err
 	}
-
-	if len(task.PlantsId) != 0 {
-		for _, plantId := range task.PlantsId {
-			_, err = r.DB.ExecContext(ctx, "INSERT INTO task_plants (id, task_id, plant_id) VALUES ($1, $2, $3)", uuid.New(), idParsed, plantId)
-			if err != nil {
-				return "", err
-			}
-		}
-	}
-	if len(task.GardensId) != 0 {
-		for _, gardenId := range task.GardensId {
-			_, err = r.DB.ExecContext(ctx, "INSERT INTO task_gardens (id, task_id, garden_id) VALUES ($1, $2, $3)", uuid.New(), idParsed, gardenId)
-			if err != nil {
-				return "", err
-			}
-		}
-	}
-	if len(task.CategoriesId) != 0 {
-		for _, categoryId := range task.CategoriesId {
-			_, err = r.DB.ExecContext(ctx, "INSERT INTO task_categories (id, task_id, category_id) VALUES ($1, $2, $3)", uuid.New(), idParsed, categoryId)
-			if err != nil {
-				return "", err
-			}
-		}
-	}
 	return task.Id, nil
 }
 
 func (r *TaskRepositoryImpl) Update(ctx context.Context, task *entities.Task) error {
-	query := `UPDATE tasks SET task_name = $1, task_description = $2, date_task = $3, urgency_level = $4, task_status = $5 WHERE id = $6`
-
-	idParsed, err := uuid.Parse(task.Id)
-	if err != nil {
-		return errors.New("invalid id")
-	}
-
-	_, err = r.DB.ExecContext(ctx, query, task.Name, task.Description, task.TaskDate, task.UrgencyLevel, task.TaskStatus, idParsed)
-	if err != nil {
-		return err
-	}
+	query := `UPDATE tasks SET task_name = $1, task_description = $2, date_task = $3, urgency_level = $4, task_status = $5,
+		version = version + 1
+		WHERE id = $6 AND ` + editorOf("workspace_id", "$8") + ` AND deleted_at IS NULL AND version = $7`
 
-	_, err = r.DB.ExecContext(ctx, "DELETE FROM task_plants WHERE task_id = $1", idParsed)
-	if err != nil {
-		return err
-	}
-	if len(task.PlantsId) != 0 {
-		for _, plantId := range task.PlantsId {
-			_, err = r.DB.ExecContext(ctx, "INSERT INTO task_plants (id, task_id, plant_id) VALUES ($1, $2, $3)", uuid.New(), idParsed, plantId)
-			if err != nil {
-				return err
-			}
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		result, err := conn(ctx, r.DB).Exec(ctx, query, task.Name, task.Description, task.TaskDate, task.UrgencyLevel, task.TaskStatus, task.Id, task.Version, task.UserId)
+		if err != nil {
+			return err
 		}
-	}
-
-	_, err = r.DB.ExecContext(ctx, "DELETE FROM task_gardens WHERE task_id = $1", idParsed)
-	if err != nil {
-		return err
-	}
-	if len(task.GardensId) != 0 {
-		for _, gardenId := range task.GardensId {
-			_, err = r.DB.ExecContext(ctx, "INSERT INTO task_gardens (id, task_id, garden_id) VALUES ($1, $2, $3)", uuid.New(), idParsed, gardenId)
-			if err != nil {
+		if result.RowsAffected() == 0 {
+			if err := writeDenied(ctx, r.DB, "tasks", task.Id, task.UserId); err != nil {
 				return err
 			}
+			// outra escrita passou na frente desde a leitura feita pelo cliente
+			return entities.ErrVersionConflict
 		}
-	}
 
-	_, err = r.DB.ExecContext(ctx, "DELETE FROM task_categories WHERE task_id = $1", idParsed)
-	if err != nil {
-		return err
-	}
-	if len(task.CategoriesId) != 0 {
-		for _, categoryId := range task.CategoriesId {
-			_, err = r.DB.ExecContext(ctx, "INSERT INTO task_categories (id, task_id, category_id) VALUES ($1, $2, $3)", uuid.New(), idParsed, categoryId)
-			if err != nil {
-				return err
-			}
-		}
-	}
+		var batch links
+		batch.replace("task_plants", "task_id", "plant_id", "plants", task.Id)
+		batch.replace("task_gardens", "task_id", "garden_id", "gardens", task.Id)
+		batch.replace("task_categories", "task_id", "category_id", "categories_tasks", task.Id)
+		taskLinks(&batch, task)
+		return batch.send(ctx, conn(ctx, r.DB))
+	})
+}
 
-	return nil
+// taskLinks enfileira os vínculos da tarefa com plantas, jardins e categorias.
+func taskLinks(batch *links, task *entities.Task) {
+	batch.add("task_plants", "task_id", "plant_id", task.Id, task.PlantsId)
+	batch.add("task_gardens", "task_id", "garden_id", task.Id, task.GardensId)
+	batch.add("task_categories", "task_id", "category_id", task.Id, task.CategoriesId)
 }
 
 func (r *TaskRepositoryImpl) Delete(ctx context.Context, userId, id string) error {
-	query := `DELETE FROM tasks WHERE id = $1 AND user_id = $2`
-
-	idParsed, err := uuid.Parse(id)
-	if err != nil {
-		return errors.New("invalid id")
-	}
+	// vai para a lixeira; os vínculos só somem no expurgo
+	query := `UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND ` + editorOf("workspace_id", "$2") + ` AND deleted_at IS NULL`
 
-	userIdParsed, err := uuid.Parse(userId)
-	if err != nil {
-		return errors.New("invalid user id")
-	}
-
-	_, err = r.DB.ExecContext(ctx, query, idParsed, userIdParsed)
+	result, err := conn(ctx, r.DB).Exec(ctx, query, id, userId)
 	if err != nil {
 		return err
 	}
+	if result.RowsAffected() == 0 {
+		return writeDenied(ctx, r.DB, "tasks", id, userId)
+	}
 
 	return nil
 }
 
-func (r *TaskRepositoryImpl) FindByID(ctx context.Context, userId, id string) (*entities.TaskOutputDTO, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao converter userId: %w", err)
-	}
-	query := `
-		SELECT 
-			t.id AS task_id,
-			t.task_name,
-			t.task_description,
-			t.date_task,
-			t.urgency_level,
-			t.task_status,
-			t.user_id,
-			t.created_at AS task_created_at,
-			t.updated_at AS task_updated_at,
-			tc.category_id,
-			c.category_name,
-			tg.garden_id,
-			g.garden_name,
-			tp.plant_id,
-			p.plant_name
-		FROM 
-			tasks t
-		LEFT JOIN 
-			task_categories tc ON t.id = tc.task_id
-		LEFT JOIN 
-			categories c ON tc.category_id = c.id
-		LEFT JOIN 
-			task_gardens tg ON t.id = tg.task_id
-		LEFT JOIN 
-			gardens g ON tg.garden_id = g.id
-		LEFT JOIN 
-			task_plants tp ON t.id = tp.task_id
-		LEFT JOIN 
-			plants p ON tp.plant_id = p.id
-		WHERE 
-			t.user_id = $1 AND t.id = $2;
-	`
-
-	idParse, err := uuid.Parse(id)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao converter id: %w", err)
-	}
-
-	rows, err := r.DB.QueryContext(ctx, query, userIdParse, idParse)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
-	}
-	defer rows.Close()
-	var task *entities.TaskOutputDTO
-	var categories []entities.CategoryTask
-	var gardens []entities.Garden
-	var plants []entities.Plant
-
-	for rows.Next() {
-		var category entities.CategoryTask
-		var garden entities.Garden
-		var plant entities.Plant
-		tempTask := entities.TaskOutputDTO{}
-
-		err := rows.Scan(
-			&tempTask.Id,
-			&tempTask.Name,
-			&tempTask.Description,
-			&tempTask.TaskDate,
-			&tempTask.UrgencyLevel,
-			&tempTask.TaskStatus,
-			&tempTask.UserId,
-			&tempTask.CreatedAt,
-			&tempTask.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&garden.Id,
-			&garden.GardenName,
-			&plant.Id,
-			&plant.PlantName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
-		}
-
-		if task == nil {
-			task = &tempTask
-		}
+// taskColumns lista as colunas de uma tarefa com os relacionamentos agregados;
+// alias é a tabela ou CTE de onde a tarefa vem.
+func taskColumns(alias string) string {
+	return fmt.Sprintf(`
+			%[1]s.id,
+			%[1]s.task_name,
+			%[1]s.task_description,
+			%[1]s.date_task,
+			%[1]s.urgency_level,
+			%[1]s.task_status,
+			%[1]s.user_id,
+			%[1]s.workspace_id,
+			%[1]s.created_at,
+			%[1]s.updated_at,
+			%[1]s.version,
+			%[2]s AS categories,
+			%[3]s AS gardens,
+			%[4]s AS plants`,
+		alias,
+		relation(alias+".id", "task_categories", "task_id", "category_id", "categories_tasks", "'id', x.id, 'name', x.category_name"),
+		relation(alias+".id", "task_gardens", "task_id", "garden_id", "gardens", "'id', x.id, 'garden_name', x.garden_name"),
+		relation(alias+".id", "task_plants", "task_id", "plant_id", "plants", "'id', x.id, 'plant_name', x.plant_name"),
+	)
+}
 
-		if category.Id != "" {
-			categories = append(categories, category)
-		}
-		if garden.Id != "" {
-			gardens = append(gardens, garden)
-		}
-		if plant.Id != "" {
-			plants = append(plants, plant)
-		}
-	}
+// scanTask lê uma linha de taskColumns; extra recebe as colunas que vierem depois.
+func scanTask(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*entities.TaskOutputDTO, error) {
+	var task entities.TaskOutputDTO
+	var categories, gardens, plants []byte
+
+	dest := []interface{}{
+		&task.Id,
+		&task.Name,
+		&task.Description,
+		&task.TaskDate,
+		&task.UrgencyLevel,
+		&task.TaskStatus,
+		&task.UserId,
+		&task.WorkspaceId,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.Version,
+		&categories,
+		&gardens,
+		&plants,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if err := scanRelation(categories, &task.Categories); err != nil {
+		return nil, err
+	}
+	if err := scanRelation(gardens, &task.Gardens); err != nil {
+		return nil, err
+	}
+	if err := scanRelation(plants, &task.Plants); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
 
-	if task != nil {
-		task.Categories = categories
-		task.Gardens = gardens
-		task.Plants = plants
-	}
+func (r *TaskRepositoryImpl) FindByID(ctx context.Context, userId, id string) (*entities.TaskOutputDTO, error) {
 
-	if err := rows.Err(); err != nil {
-		log.Fatalf("Erro durante a iteração: %v", err)
-	}
+	query := `SELECT ` + taskColumns("t") + `
+		FROM tasks t
+		WHERE ` + memberOf("t.workspace_id", "$1") + ` AND t.id = $2 AND t.deleted_at IS NULL`
 
-	if task == nil {
+	task, err := scanTask(reader(ctx, r.DB).QueryRow(ctx, query, userId, id))
+	if err == pgx.ErrNoRows {
 		return nil, errors.New("task not found")
 	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar tarefa: %w", err)
+	}
 	return task, nil
 }
 
-func (r *TaskRepositoryImpl) FindByName(ctx context.Context, userId, name string) ([]*entities.TaskOutputDTO, error) {
+var taskSortFields = map[string]sortField{
+	"name":       {column: "t.task_name", cast: "text"},
+	"created_at": {column: "t.created_at", cast: "timestamp"},
+	"task_date":  {column: "t.date_task", cast: "timestamp"},
+	"urgency":    {column: "t.urgency_level", cast: "numeric"},
+}
 
-	userIdParse, err := uuid.Parse(userId)
+// listTasks pagina as tarefas visíveis ao usuário que atendem filter; $1 é sempre o user_id.
+func (r *TaskRepositoryImpl) listTasks(ctx context.Context, filter string, args []interface{}, page entities.PageRequest) (*entities.Page[*entities.TaskOutputDTO], error) {
+	ks, err := newKeyset(page, taskSortFields, "task_date", "t.id")
 	if err != nil {
-		return nil, fmt.Errorf("erro ao converter userId: %w", err)
+		return nil, err
 	}
 
-	query := `
-		SELECT 
-			t.id AS task_id,
-			t.task_name,
-			t.task_description,
-			t.date_task,
-			t.urgency_level,
-			t.task_status,
-			t.user_id,
-			t.created_at AS task_created_at,
-			t.updated_at AS task_updated_at,
-			tc.category_id,
-			c.category_name,
-			tg.garden_id,
-			g.garden_name,
-			tp.plant_id,
-			p.plant_name
-		FROM 
-			tasks t
-		LEFT JOIN 
-			task_categories tc ON t.id = tc.task_id
-		LEFT JOIN 
-			categories c ON tc.category_id = c.id
-		LEFT JOIN 
-			task_gardens tg ON t.id = tg.task_id
-		LEFT JOIN 
-			gardens g ON tg.garden_id = g.id
-		LEFT JOIN 
-			task_plants tp ON t.id = tp.task_id
-		LEFT JOIN 
-			plants p ON tp.plant_id = p.id
-		WHERE 
-			t.user_id = $1 AND t.task_name ILIKE $2;
-	`
-
-	rows, err := r.DB.QueryContext(ctx, query, userIdParse, "%"+name+"%")
+	total, err := count(ctx, reader(ctx, r.DB), "tasks", "t", filter, args)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
-	}
-	defer rows.Close()
-
-	// Map para consolidar tarefas
-	taskMap := make(map[string]*entities.TaskOutputDTO)
-
-	for rows.Next() {
-		var category entities.CategoryTask
-		var garden entities.Garden
-		var plant entities.Plant
-		tempTask := entities.TaskOutputDTO{}
-
-		err := rows.Scan(
-			&tempTask.Id,
-			&tempTask.Name,
-			&tempTask.Description,
-			&tempTask.TaskDate,
-			&tempTask.UrgencyLevel,
-			&tempTask.TaskStatus,
-			&tempTask.UserId,
-			&tempTask.CreatedAt,
-			&tempTask.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&garden.Id,
-			&garden.GardenName,
-			&plant.Id,
-			&plant.PlantName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
-		}
-
-		// Adiciona ou atualiza a tarefa no mapa
-		existingTask, exists := taskMap[tempTask.Id]
-		if !exists {
-			existingTask = &tempTask
-			existingTask.Categories = []entities.CategoryTask{}
-			existingTask.Gardens = []entities.Garden{}
-			existingTask.Plants = []entities.Plant{}
-			taskMap[tempTask.Id] = existingTask
-		}
-
-		// Adiciona categorias, jardins e plantas se existirem
-		if category.Id != "" {
-			existingTask.Categories = append(existingTask.Categories, category)
-		}
-		if garden.Id != "" {
-			existingTask.Gardens = append(existingTask.Gardens, garden)
-		}
-		if plant.Id != "" {
-			existingTask.Plants = append(existingTask.Plants, plant)
-		}
-	}
-
-	var tasks []*entities.TaskOutputDTO
-	for _, task := range taskMap {
-		tasks = append(tasks, task)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro durante a iteração: %w", err)
+		return nil, err
 	}
 
-	return tasks, nil
-}
-
-func (r *TaskRepositoryImpl) FindByCategoryName(ctx context.Context, userId, categoryName string) ([]*entities.TaskOutputDTO, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao converter userId: %w", err)
-	}
+	pageQuery, queryArgs := ks.pageQuery("tasks", "t", filter, args)
+	query := pageQuery + `
+		SELECT ` + taskColumns("page") + `,
+			page.sort_value
+		FROM page
+		ORDER BY page.page_pos`
 
-	query := `
-		SELECT 
-			t.id AS task_id,
-			t.task_name,
-			t.task_description,
-			t.date_task,
-			t.urgency_level,
-			t.task_status,
-			t.user_id,
-			t.created_at AS task_created_at,
-			t.updated_at AS task_updated_at,
-			tc.category_id,
-			c.category_name,
-			tg.garden_id,
-			g.garden_name,
-			tp.plant_id,
-			p.plant_name
-		FROM 
-			tasks t
-		LEFT JOIN 
-			task_categories tc ON t.id = tc.task_id
-		LEFT JOIN 
-			categories c ON tc.category_id = c.id
-		LEFT JOIN 
-			task_gardens tg ON t.id = tg.task_id
-		LEFT JOIN 
-			gardens g ON tg.garden_id = g.id
-		LEFT JOIN 
-			task_plants tp ON t.id = tp.task_id
-		LEFT JOIN 
-			plants p ON tp.plant_id = p.id
-		WHERE 
-			t.user_id = $1 AND c.category_name ILIKE $2;
-	`
-
-	rows, err := r.DB.QueryContext(ctx, query, userIdParse, "%"+categoryName+"%")
+	rows, err := reader(ctx, r.DB).Query(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
 	}
 	defer rows.Close()
 
-	// Map para consolidar tarefas
-	taskMap := make(map[string]*entities.TaskOutputDTO)
+	tasks := make([]*entities.TaskOutputDTO, 0)
+	sortValues := make(map[string]string)
 
 	for rows.Next() {
-		var category entities.CategoryTask
-		var garden entities.Garden
-		var plant entities.Plant
-		tempTask := entities.TaskOutputDTO{}
-
-		err := rows.Scan(
-			&tempTask.Id,
-			&tempTask.Name,
-			&tempTask.Description,
-			&tempTask.TaskDate,
-			&tempTask.UrgencyLevel,
-			&tempTask.TaskStatus,
-			&tempTask.UserId,
-			&tempTask.CreatedAt,
-			&tempTask.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&garden.Id,
-			&garden.GardenName,
-			&plant.Id,
-			&plant.PlantName,
-		)
+		var sortValue string
+		task, err := scanTask(rows, &sortValue)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
 		}
-
-		// Adiciona ou atualiza a tarefa no mapa
-		existingTask, exists := taskMap[tempTask.Id]
-		if !exists {
-			existingTask = &tempTask
-			existingTask.Categories = []entities.CategoryTask{}
-			existingTask.Gardens = []entities.Garden{}
-			existingTask.Plants = []entities.Plant{}
-			taskMap[tempTask.Id] = existingTask
-		}
-
-		// Adiciona categorias, jardins e plantas se existirem
-		if category.Id != "" {
-			existingTask.Categories = append(existingTask.Categories, category)
-		}
-		if garden.Id != "" {
-			existingTask.Gardens = append(existingTask.Gardens, garden)
-		}
-		if plant.Id != "" {
-			existingTask.Plants = append(existingTask.Plants, plant)
-		}
-	}
-
-	// Converte o mapa para uma slice
-	var tasks []*entities.TaskOutputDTO
-	for _, task := range taskMap {
 		tasks = append(tasks, task)
+		sortValues[task.Id] = sortValue
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("erro durante a iteração: %w", err)
 	}
 
-	return tasks, nil
-}
-
-func (r *TaskRepositoryImpl) FindByStatus(ctx context.Context, userId, status string) ([]*entities.TaskOutputDTO, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao converter userId: %w", err)
+	result := &entities.Page[*entities.TaskOutputDTO]{
+		Items: tasks,
+		Total: total,
+		Limit: ks.limit,
 	}
-
-	query := `
-		SELECT 
-			t.id AS task_id,
-			t.task_name,
-			t.task_description,
-			t.date_task,
-			t.urgency_level,
-			t.task_status,
-			t.user_id,
-			t.created_at AS task_created_at,
-			t.updated_at AS task_updated_at,
-			tc.category_id,
-			c.category_name,
-			tg.garden_id,
-			g.garden_name,
-			tp.plant_id,
-			p.plant_name
-		FROM 
-			tasks t
-		LEFT JOIN 
-			task_categories tc ON t.id = tc.task_id
-		LEFT JOIN 
-			categories c ON tc.category_id = c.id
-		LEFT JOIN 
-			task_gardens tg ON t.id = tg.task_id
-		LEFT JOIN 
-			gardens g ON tg.garden_id = g.id
-		LEFT JOIN 
-			task_plants tp ON t.id = tp.task_id
-		LEFT JOIN 
-			plants p ON tp.plant_id = p.id
-		WHERE 
-			t.user_id = $1 AND t.task_status ILIKE $2;
-	`
-
-	rows, err := r.DB.QueryContext(ctx, query, userIdParse, "%"+status+"%")
-	if err != nil {
-		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
+	if len(tasks) > ks.limit {
+		result.Items = tasks[:ks.limit]
+		last := result.Items[ks.limit-1]
+		result.NextCursor = ks.next(sortValues[last.Id], last.Id)
 	}
-	defer rows.Close()
-
-	// Map para consolidar tarefas
-	taskMap := make(map[string]*entities.TaskOutputDTO)
+	return result, nil
+}
 
-	for rows.Next() {
-		var category entities.CategoryTask
-		var garden entities.Garden
-		var plant entities.Plant
-		tempTask := entities.TaskOutputDTO{}
-
-		err := rows.Scan(
-			&tempTask.Id,
-			&tempTask.Name,
-			&tempTask.Description,
-			&tempTask.TaskDate,
-			&tempTask.UrgencyLevel,
-			&tempTask.TaskStatus,
-			&tempTask.UserId,
-			&tempTask.CreatedAt,
-			&tempTask.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&garden.Id,
-			&garden.GardenName,
-			&plant.Id,
-			&plant.PlantName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
-		}
+// Search combina os filtros informados em uma única consulta paginada. Os filtros de
+// texto comparam o valor inteiro, sem diferenciar maiúsculas.
+func (r *TaskRepositoryImpl) Search(ctx context.Context, userId string, filter entities.TaskFilter, page entities.PageRequest) (*entities.Page[*entities.TaskOutputDTO], error) {
+	return r.search(ctx, userId, filter, page, escapeLike)
+}
 
-		// Adiciona ou atualiza a tarefa no mapa
-		existingTask, exists := taskMap[tempTask.Id]
-		if !exists {
-			existingTask = &tempTask
-			existingTask.Categories = []entities.CategoryTask{}
-			existingTask.Gardens = []entities.Garden{}
-			existingTask.Plants = []entities.Plant{}
-			taskMap[tempTask.Id] = existingTask
-		}
+// search monta a consulta de Search; like transforma cada filtro de texto no padrão do ILIKE.
+func (r *TaskRepositoryImpl) search(ctx context.Context, userId string, filter entities.TaskFilter, page entities.PageRequest, like func(string) string) (*entities.Page[*entities.TaskOutputDTO], error) {
 
-		// Adiciona categorias, jardins e plantas se existirem
-		if category.Id != "" {
-			existingTask.Categories = append(existingTask.Categories, category)
-		}
-		if garden.Id != "" {
-			existingTask.Gardens = append(existingTask.Gardens, garden)
-		}
-		if plant.Id != "" {
-			existingTask.Plants = append(existingTask.Plants, plant)
-		}
+	where := newWhere(memberOf("t.workspace_id", "?"), userId)
+	where.add("t.deleted_at IS NULL")
+	if filter.Name != "" {
+		where.add("f_unaccent(t.task_name) ILIKE f_unaccent(?)", like(filter.Name))
 	}
-
-	var tasks []*entities.TaskOutputDTO
-	for _, task := range taskMap {
-		tasks = append(tasks, task)
+	if filter.Category != "" {
+		where.add(`EXISTS (
+			SELECT 1 FROM task_categories ftc
+			JOIN categories_tasks fc ON ftc.category_id = fc.id
+			WHERE ftc.task_id = t.id AND fc.deleted_at IS NULL AND fc.category_name ILIKE ?)`, like(filter.Category))
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro durante a iteração: %w", err)
+	if filter.Plant != "" {
+		where.add(`EXISTS (
+			SELECT 1 FROM task_plants ftp
+			JOIN plants fp ON ftp.plant_id = fp.id
+			WHERE ftp.task_id = t.id AND fp.deleted_at IS NULL AND fp.plant_name ILIKE ?)`, like(filter.Plant))
 	}
-
-	return tasks, nil
-}
-
-func (r *TaskRepositoryImpl) FindByUrgencyLevel(ctx context.Context, userId string, urgencyLevel int) ([]*entities.TaskOutputDTO, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao converter userId: %w", err)
+	if filter.Garden != "" {
+		where.add(`EXISTS (
+			SELECT 1 FROM task_gardens ftg
+			JOIN gardens fg ON ftg.garden_id = fg.id
+			WHERE ftg.task_id = t.id AND fg.deleted_at IS NULL AND fg.garden_name ILIKE ?)`, like(filter.Garden))
 	}
-
-	query := `
-		SELECT 
-			t.id AS task_id,
-			t.task_name,
-			t.task_description,
-			t.date_task,
-			t.urgency_level,
-			t.task_status,
-			t.user_id,
-			t.created_at AS task_created_at,
-			t.updated_at AS task_updated_at,
-			tc.category_id,
-			c.category_name,
-			tg.garden_id,
-			g.garden_name,
-			tp.plant_id,
-			p.plant_name
-		FROM 
-			tasks t
-		LEFT JOIN 
-			task_categories tc ON t.id = tc.task_id
-		LEFT JOIN 
-			categories c ON tc.category_id = c.id
-		LEFT JOIN 
-			task_gardens tg ON t.id = tg.task_id
-		LEFT JOIN 
-			gardens g ON tg.garden_id = g.id
-		LEFT JOIN 
-			task_plants tp ON t.id = tp.task_id
-		LEFT JOIN 
-			plants p ON tp.plant_id = p.id
-		WHERE 
-			t.user_id = $1 AND t.urgency_level = $2;
-	`
-
-	rows, err := r.DB.QueryContext(ctx, query, userIdParse, urgencyLevel)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
-	}
-	defer rows.Close()
-
-	// Map para consolidar tarefas
-	taskMap := make(map[string]*entities.TaskOutputDTO)
-
-	for rows.Next() {
-		var category entities.CategoryTask
-		var garden entities.Garden
-		var plant entities.Plant
-		tempTask := entities.TaskOutputDTO{}
-
-		err := rows.Scan(
-			&tempTask.Id,
-			&tempTask.Name,
-			&tempTask.Description,
-			&tempTask.TaskDate,
-			&tempTask.UrgencyLevel,
-			&tempTask.TaskStatus,
-			&tempTask.UserId,
-			&tempTask.CreatedAt,
-			&tempTask.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&garden.Id,
-			&garden.GardenName,
-			&plant.Id,
-			&plant.PlantName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao escanear resultados: %w", err)
-		}
-
-		// Adiciona ou atualiza a tarefa no mapa
-		existingTask, exists := taskMap[tempTask.Id]
-		if !exists {
-			existingTask = &tempTask
-			existingTask.Categories = []entities.CategoryTask{}
-			existingTask.Gardens = []entities.Garden{}
-			existingTask.Plants = []entities.Plant{}
-			taskMap[tempTask.Id] = existingTask
-		}
-
-		// Adiciona categorias, jardins e plantas se existirem
-		if category.Id != "" {
-			existingTask.Categories = append(existingTask.Categories, category)
-		}
-		if garden.Id != "" {
-			existingTask.Gardens = append(existingTask.Gardens, garden)
-		}
-		if plant.Id != "" {
-			existingTask.Plants = append(existingTask.Plants, plant)
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = like(status)
 		}
+		where.add("t.task_status::text ILIKE ANY(?)", statuses)
 	}
-
-	var tasks []*entities.TaskOutputDTO
-	for _, task := range taskMap {
-		tasks = append(tasks, task)
+	if filter.UrgencyMin != nil {
+		where.add("t.urgency_level >= ?", *filter.UrgencyMin)
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro durante a iteração: %w", err)
+	if filter.UrgencyMax != nil {
+		where.add("t.urgency_level <= ?", *filter.UrgencyMax)
 	}
-
-	return tasks, nil
-}
-
-func (r *TaskRepositoryImpl) FindAll(ctx context.Context, userId string) ([]*entities.TaskOutputDTO, error) {
-	userIdParse, err := uuid.Parse(userId)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao converter userId: %w", err)
+	if filter.TaskDateFrom != nil {
+		where.add("t.date_task >= ?", *filter.TaskDateFrom)
 	}
-
-	query := `
-		SELECT 
-			t.id AS task_id,
-			t.task_name,
-			t.task_description,
-			t.date_task,
-			t.urgency_level,
-			t.task_status,
-			t.user_id,
-			t.created_at AS task_created_at,
-			t.updated_at AS task_updated_at,
-			tc.category_id,
-			c.category_name,
-			tg.garden_id,
-			g.garden_name,
-			tp.plant_id,
-			p.plant_name
-		FROM 
-			tasks t
-		LEFT JOIN 
-			task_categories tc ON t.id = tc.task_id
-		LEFT JOIN 
-			categories c ON tc.category_id = c.id
-		LEFT JOIN 
-			task_gardens tg ON t.id = tg.task_id
-		LEFT JOIN 
-			gardens g ON tg.garden_id = g.id
-		LEFT JOIN 
-			task_plants tp ON t.id = tp.task_id
-		LEFT JOIN 
-			plants p ON tp.plant_id = p.id
-		WHERE 
-			t.user_id = $1;
-	`
-
-	rows, err := r.DB.QueryContext(ctx, query, userIdParse)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao executar consulta: %w", err)
+	if filter.TaskDateTo != nil {
+		where.add("t.date_task <= ?", *filter.TaskDateTo)
 	}
-	defer rows.Close()
 
-	// Map para consolidar tarefas
-	taskMap := make(map[string]*entities.TaskOutputDTO)
+	return r.listTasks(ctx, where.sql(), where.args, page)
+}
 
-	for rows.Next() {
-		var category entities.CategoryTask
-		var garden entities.Garden
-		var plant entities.Plant
-		tempTask := entities.TaskOutputDTO{}
-
-		err := rows.Scan(
-			&tempTask.Id,
-			&tempTask.Name,
-			&tempTask.Description,
-			&tempTask.TaskDate,
-			&tempTask.UrgencyLevel,
-			&tempTask.TaskStatus,
-			&tempTask.UserId,
-			&tempTask.CreatedAt,
-			&tempTask.UpdatedAt,
-			&category.Id,
-			&category.Name,
-			&garden.Id,
-			&garden.GardenName,
-			&plant.Id,
-			&plant.PlantName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao escanear resultados: %w)", err)
-		}
-		existingTask, exists := taskMap[tempTask.Id]
-		if !exists {
-			existingTask = &tempTask
-			existingTask.Categories = []entities.CategoryTask{}
-			existingTask.Gardens = []entities.Garden{}
-			existingTask.Plants = []entities.Plant{}
-			taskMap[tempTask.Id] = existingTask
-		}
+func (r *TaskRepositoryImpl) FindByName(ctx context.Context, userId, name string, page entities.PageRequest) (*entities.Page[*entities.TaskOutputDTO], error) {
+	return r.search(ctx, userId, entities.TaskFilter{Name: name}, page, containsLike)
+}
 
-		// Adiciona categorias, jardins e plantas se existirem
-		if category.Id != "" {
-			existingTask.Categories = append(existingTask.Categories, category)
-		}
-		if garden.Id != "" {
-			existingTask.Gardens = append(existingTask.Gardens, garden)
-		}
-		if plant.Id != "" {
-			existingTask.Plants = append(existingTask.Plants, plant)
-		}
-	}
+func (r *TaskRepositoryImpl) FindByCategoryName(ctx context.Context, userId, categoryName string, page entities.PageRequest) (*entities.Page[*entities.TaskOutputDTO], error) {
+	return r.search(ctx, userId, entities.TaskFilter{Category: categoryName}, page, containsLike)
+}
 
-	var tasks []*entities.TaskOutputDTO
-	for _, task := range taskMap {
-		tasks = append(tasks, task)
+func (r *TaskRepositoryImpl) NameInUse(ctx context.Context, userId, name, exceptId string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM tasks
+		WHERE ` + memberOf("workspace_id", "$1") + ` AND deleted_at IS NULL
+			AND lower(task_name) = lower($2) AND id <> $3)`
+	var inUse bool
+	// a checagem antecede uma escrita, então não pode ler de uma réplica atrasada
+	if err := primary(ctx, r.DB).QueryRow(ctx, query, userId, name, exceptId).Scan(&inUse); err != nil {
+		return false, fmt.Errorf("erro ao verificar nome da tarefa: %w", err)
 	}
+	return inUse, nil
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro durante a iteração: %w", err)
-	}
+func (r *TaskRepositoryImpl) FindByStatus(ctx context.Context, userId, status string, page entities.PageRequest) (*entities.Page[*entities.TaskOutputDTO], error) {
+	return r.search(ctx, userId, entities.TaskFilter{Statuses: []string{status}}, page, containsLike)
+}
+
+func (r *TaskRepositoryImpl) FindByUrgencyLevel(ctx context.Context, userId string, urgencyLevel int, page entities.PageRequest) (*entities.Page[*entities.TaskOutputDTO], error) {
+	return r.Search(ctx, userId, entities.TaskFilter{UrgencyMin: &urgencyLevel, UrgencyMax: &urgencyLevel}, page)
+}
 
-	return tasks, nil
+func (r *TaskRepositoryImpl) FindAll(ctx context.Context, userId string, page entities.PageRequest) (*entities.Page[*entities.TaskOutputDTO], error) {
+	return r.Search(ctx, userId, entities.TaskFilter{}, page)
 }
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type trashTable struct {
+	table      string
+	nameColumn string
+}
+
+// trashTables liga cada tipo da lixeira à sua tabela. A ordem é a do expurgo:
+// primeiro quem aponta para os outros.
+var trashTables = []struct {
+	itemType string
+	trashTable
+}{
+	{entities.TrashTypeTask, trashTable{"tasks", "task_name"}},
+	{entities.TrashTypeGarden, trashTable{"gardens", "garden_name"}},
+	{entities.TrashTypePlant, trashTable{"plants", "plant_name"}},
+	{entities.TrashTypeCategoryTask, trashTable{"categories_tasks", "category_name"}},
+	{entities.TrashTypeCategoryPlant, trashTable{"categories_plants", "category_name"}},
+}
+
+func trashTableOf(itemType string) (trashTable, bool) {
+	for _, t := range trashTables {
+		if t.itemType == itemType {
+			return t.trashTable, true
+		}
+	}
+	return trashTable{}, false
+}
+
+type TrashRepositoryImpl struct {
+	DB *database.Cluster
+}
+
+func NewTrashRepository(db *database.Cluster) *TrashRepositoryImpl {
+	return &TrashRepositoryImpl{DB: db}
+}
+
+func (r *TrashRepositoryImpl) List(ctx context.Context, userId string) ([]*entities.TrashItem, error) {
+	query := ""
+	for i, t := range trashTables {
+		if i > 0 {
+			query += "\n\t\tUNION ALL\n"
+		}
+		query += fmt.Sprintf(`		SELECT '%s', id::text, %s, deleted_at FROM %s WHERE %s AND deleted_at IS NOT NULL`,
+			t.itemType, t.nameColumn, t.table, memberOf("workspace_id", "$1"))
+	}
+	query += "\n\t\tORDER BY deleted_at DESC"
+
+	rows, err := reader(ctx, r.DB).Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar lixeira: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*entities.TrashItem, 0)
+	for rows.Next() {
+		var item entities.TrashItem
+		if err := rows.Scan(&item.Type, &item.Id, &item.Name, &item.DeletedAt); err != nil {
+			return nil, fmt.Errorf("erro ao escanear lixeira: %w", err)
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro durante a iteração: %w", err)
+	}
+	return items, nil
+}
+
+// Restore tira o registro da lixeira. Os vínculos foram mantidos durante a
+// exclusão, então ele volta ligado aos mesmos relacionados de antes.
+func (r *TrashRepositoryImpl) Restore(ctx context.Context, userId, itemType, id string) error {
+	t, ok := trashTableOf(itemType)
+	if !ok {
+		return fmt.Errorf("tipo inválido na lixeira: %s", itemType)
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET deleted_at = NULL WHERE id = $1 AND %s AND deleted_at IS NOT NULL`,
+		t.table, editorOf("workspace_id", "$2"))
+	result, err := conn(ctx, r.DB).Exec(ctx, query, id, userId)
+	if err != nil {
+		return fmt.Errorf("erro ao restaurar item da lixeira: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		if err := writeDenied(ctx, r.DB, t.table, id, userId); err != nil {
+			return err
+		}
+		return entities.ErrTrashItemNotFound
+	}
+	return nil
+}
+
+// Purge apaga de vez; as chaves estrangeiras em cascata levam junto históricos e vínculos.
+// O corte é calculado no banco, no mesmo relógio que gravou deleted_at.
+func (r *TrashRepositoryImpl) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	var purged int64
+	err := withTx(ctx, r.DB, func(ctx context.Context) error {
+		for _, t := range trashTables {
+			query := fmt.Sprintf(`DELETE FROM %s WHERE deleted_at < CURRENT_TIMESTAMP - make_interval(secs => $1)`, t.table)
+			result, err := conn(ctx, r.DB).Exec(ctx, query, retention.Seconds())
+			if err != nil {
+				return fmt.Errorf("erro ao expurgar %s: %w", t.table, err)
+			}
+			purged += result.RowsAffected()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lucasBiazon/botany-back/internal/database"
+)
+
+type txKey struct{}
+
+// txState é o que fica no ctx durante uma transação: a própria transação e as
+// ações que só podem rodar depois do commit, como invalidar cache.
+type txState struct {
+	tx          pgx.Tx
+	afterCommit []func()
+}
+
+// executor é o que *pgxpool.Pool (primário e réplica do Cluster) e pgx.Tx têm em comum.
+type executor interface {
+	Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row
+	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+}
+
+type UnitOfWorkImpl struct {
+	DB *database.Cluster
+}
+
+func NewUnitOfWork(db *database.Cluster) *UnitOfWorkImpl {
+	return &UnitOfWorkImpl{DB: db}
+}
+
+func (u *UnitOfWorkImpl) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTx(ctx, u.DB, fn)
+}
+
+func currentTx(ctx context.Context) *txState {
+	state, _ := ctx.Value(txKey{}).(*txState)
+	return state
+}
+
+func inTx(ctx context.Context) bool {
+	return currentTx(ctx) != nil
+}
+
+// conn devolve a transação aberta no ctx, se houver, ou o primário, e marca a requisição
+// como escrita para as próximas leituras não irem à réplica.
+func conn(ctx context.Context, db *database.Cluster) executor {
+	database.MarkWrite(ctx)
+	return primary(ctx, db)
+}
+
+// primary é conn para leituras que sempre precisam do primário.
+func primary(ctx context.Context, db *database.Cluster) executor {
+	if state := currentTx(ctx); state != nil {
+		return state.tx
+	}
+	return db.Primary
+}
+
+// reader é usado pelos métodos só de leitura: dentro de uma transação continua nela,
+// fora dela o Cluster decide entre réplica e primário.
+func reader(ctx context.Context, db *database.Cluster) executor {
+	if state := currentTx(ctx); state != nil {
+		return state.tx
+	}
+	return db.Reader(ctx)
+}
+
+// afterCommit roda fn depois do commit da transação do ctx, ou na hora se não houver
+// transação. Em rollback fn é descartada.
+func afterCommit(ctx context.Context, fn func()) {
+	if state := currentTx(ctx); state != nil {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// withTx executa fn em uma transação. Se o ctx já carrega uma, fn participa dela
+// e o commit fica com quem a abriu.
+func withTx(ctx context.Context, db *database.Cluster, fn func(ctx context.Context) error) error {
+	if inTx(ctx) {
+		return fn(ctx)
+	}
+
+	database.MarkWrite(ctx)
+	tx, err := db.Primary.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	state := &txState{tx: tx}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback(context.WithoutCancel(ctx))
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		tx.Rollback(context.WithoutCancel(ctx))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	for _, action := range state.afterCommit {
+		action()
+	}
+	return nil
+}
@@ -2,31 +2,36 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"time"
 
-	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/database"
 	"github.com/lucasBiazon/botany-back/internal/entities"
 )
 
 type UserRepositoryImpl struct {
-	DB *sql.DB
-	RD *redis.Client
+	DB                   *database.Cluster
+	Cache                *cache.Fallback
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
 }
 
-func NewUserRepository(db *sql.DB, rd *redis.Client) *UserRepositoryImpl {
+func NewUserRepository(db *database.Cluster, store *cache.Fallback, emailVerificationTTL, passwordResetTTL time.Duration) *UserRepositoryImpl {
 	return &UserRepositoryImpl{
-		DB: db,
-		RD: rd,
+		DB:                   db,
+		Cache:                store,
+		EmailVerificationTTL: emailVerificationTTL,
+		PasswordResetTTL:     passwordResetTTL,
 	}
 }
 
 func (r *UserRepositoryImpl) Create(ctx context.Context, user *entities.User) error {
-	_, err := r.DB.Exec(`
+	_, err := conn(ctx, r.DB).Exec(ctx, `
 		INSERT INTO users (id, user_name, email, password_hash) 
 		VALUES ($1, $2, $3, $4) 
 		RETURNING id`, user.Id, user.Name, user.Email, user.Password)
@@ -39,12 +44,12 @@ func (r *UserRepositoryImpl) Create(ctx context.Context, user *entities.User) er
 func (r *UserRepositoryImpl) FindByID(ctx context.Context, id string) (*entities.User, error) {
 	query := `SELECT id, user_name, email, isActive, password_hash, created_at, updated_at FROM users WHERE id=$1`
 
-	row := r.DB.QueryRow(query, id)
+	row := primary(ctx, r.DB).QueryRow(ctx, query, id)
 	user := &entities.User{}
 	err := row.Scan(&user.Id, &user.Name, &user.Email, &user.IsActive, &user.Password, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
@@ -55,12 +60,12 @@ func (r *UserRepositoryImpl) FindByID(ctx context.Context, id string) (*entities
 func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
 	query := `SELECT id, user_name, email, password_hash, isActive,  created_at, updated_at FROM users WHERE email=$1`
 
-	row := r.DB.QueryRow(query, email)
+	row := primary(ctx, r.DB).QueryRow(ctx, query, email)
 	user := &entities.User{}
 	err := row.Scan(&user.Id, &user.Name, &user.Email, &user.IsActive, &user.Password, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
@@ -71,7 +76,7 @@ func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*en
 func (r *UserRepositoryImpl) Update(ctx context.Context, user *entities.User) error {
 	query := `UPDATE users SET user_name=$1, email=$2  WHERE id=$3`
 
-	_, err := r.DB.Exec(query, user.Name, user.Email, user.Id)
+	_, err := conn(ctx, r.DB).Exec(ctx, query, user.Name, user.Email, user.Id)
 	if err != nil {
 		return err
 	}
@@ -80,7 +85,7 @@ func (r *UserRepositoryImpl) Update(ctx context.Context, user *entities.User) er
 
 func (r *UserRepositoryImpl) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
 	query := `UPDATE users SET password_hash=$1 WHERE id=$2`
-	_, err := r.DB.Exec(query, password, id)
+	_, err := conn(ctx, r.DB).Exec(ctx, query, password, id)
 	if err != nil {
 		return err
 	}
@@ -89,7 +94,7 @@ func (r *UserRepositoryImpl) UpdatePassword(ctx context.Context, id uuid.UUID, p
 
 func (r *UserRepositoryImpl) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM users WHERE id=$1`
-	_, err := r.DB.Exec(query, id)
+	_, err := conn(ctx, r.DB).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -97,7 +102,7 @@ func (r *UserRepositoryImpl) Delete(ctx context.Context, id string) error {
 }
 
 func (r *UserRepositoryImpl) StoreRevokedTokenPassword(ctx context.Context, token string) error {
-	err := r.RD.Set(ctx, token, "revoked", time.Minute*10).Err()
+	err := r.Cache.Set(ctx, "revoked_token:"+token, []byte("revoked"), r.PasswordResetTTL)
 	if err != nil {
 		return errors.New("failed to store revoked token")
 	}
@@ -105,77 +110,55 @@ func (r *UserRepositoryImpl) StoreRevokedTokenPassword(ctx context.Context, toke
 }
 
 func (r *UserRepositoryImpl) IsTokenRevokedPassword(ctx context.Context, token string) bool {
-	val, err := r.RD.Get(ctx, token).Result()
-	if err == redis.Nil {
-		return false
+	val, err := r.Cache.Get(ctx, "revoked_token:"+token)
+	if err == cache.ErrUnavailable {
+		// sem como confirmar, é mais seguro recusar o token do que permitir reuso
+		return true
 	}
-	return err == nil && val == "revoked"
+	return err == nil && string(val) == "revoked"
 }
 
 func (r *UserRepositoryImpl) StoreToken(ctx context.Context, email, token string) error {
-
-	err := r.RD.HSet(ctx, email, "token", token).Err()
-	if err != nil {
-		return err
-	}
-	err = r.RD.Expire(ctx, email, 10*time.Minute).Err()
-	if err != nil {
-		return err
-	}
-	return nil
+	return r.Cache.Set(ctx, "email_token:"+email, []byte(token), r.EmailVerificationTTL)
 }
 
 func (r *UserRepositoryImpl) ResendToken(ctx context.Context, email string, token string) (string, error) {
-	tokenStored, err := r.RD.HGet(ctx, email, "token").Result()
-
-	if err == redis.Nil || tokenStored == "" {
-		err = r.RD.HSet(ctx, email, "token", token).Err()
-		if err != nil {
-			return "", err
-		}
-		err = r.RD.Expire(ctx, email, 10*time.Minute).Err()
-		if err != nil {
-			return "", err
-		}
-		return token, nil
+	tokenStored, err := r.Cache.Get(ctx, "email_token:"+email)
+	if err == nil && len(tokenStored) > 0 {
+		token = string(tokenStored)
 	}
-	if err = r.RD.Del(ctx, email).Err(); err != nil {
-		return "", err
-	}
-	err = r.RD.HSet(ctx, email, "token", token).Err()
-	if err != nil {
-		return "", err
-	}
-	err = r.RD.Expire(ctx, email, 10*time.Minute).Err()
-	if err != nil {
+
+	if err := r.StoreToken(ctx, email, token); err != nil {
 		return "", err
 	}
-	return tokenStored, nil
+	return token, nil
 }
 
 func (r *UserRepositoryImpl) ActivateAccount(ctx context.Context, email, token string) error {
-
-	tokenStored, err := r.RD.HGet(ctx, email, "token").Result()
-	if err == redis.Nil || tokenStored != token {
-		return err
+	tokenStored, err := r.Cache.Get(ctx, "email_token:"+email)
+	if err == cache.ErrUnavailable {
+		return errors.New("não foi possível validar o token, tente novamente mais tarde")
+	}
+	if err != nil || string(tokenStored) != token {
+		return errors.New("token inválido ou expirado")
 	}
-	if err = r.RD.Del(ctx, email).Err(); err != nil {
+	if err = r.Cache.Del(ctx, "email_token:"+email); err != nil {
 		return err
 	}
 
 	query := `UPDATE users SET isActive=TRUE WHERE email=$1`
-	_, err = r.DB.Exec(query, email)
+	_, err = conn(ctx, r.DB).Exec(ctx, query, email)
 	return err
 }
 
 func (r *UserRepositoryImpl) Login(ctx context.Context, email, password string) (string, error) {
 	query := `SELECT id, password_hash, isActive FROM users WHERE email=$1`
-	row := r.DB.QueryRow(query, email)
+	row := primary(ctx, r.DB).QueryRow(ctx, query, email)
 	var passwordHash, id string
 	var isActive bool
 	err := row.Scan(&id, &passwordHash, &isActive)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if err == pgx.ErrNoRows {
 			return "not found", errors.New("user not found")
 		}
 		return "", err
@@ -0,0 +1,392 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type WorkspaceRepositoryImpl struct {
+	DB            *database.Cluster
+	InvitationTTL time.Duration
+}
+
+func NewWorkspaceRepository(db *database.Cluster, invitationTTL time.Duration) *WorkspaceRepositoryImpl {
+	return &WorkspaceRepositoryImpl{
+		DB:            db,
+		InvitationTTL: invitationTTL,
+	}
+}
+
+const workspaceColumns = `w.id, w.workspace_name, w.personal_of IS NOT NULL, wm.member_role, w.created_at, w.updated_at`
+
+func scanWorkspace(row interface{ Scan(...interface{}) error }) (*entities.Workspace, error) {
+	var workspace entities.Workspace
+	err := row.Scan(&workspace.Id, &workspace.Name, &workspace.Personal, &workspace.Role, &workspace.CreatedAt, &workspace.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// lockWorkspace trava o workspace até o fim da transação, serializando as mudanças
+// de membros, e devolve o papel de quem está agindo.
+func (r *WorkspaceRepositoryImpl) lockWorkspace(ctx context.Context, userId, workspaceId string) (role string, personal bool, err error) {
+	query := `SELECT w.personal_of IS NOT NULL, wm.member_role
+		FROM workspaces w
+		JOIN workspace_members wm ON wm.workspace_id = w.id AND wm.user_id = $2
+		WHERE w.id = $1
+		FOR UPDATE OF w`
+	err = conn(ctx, r.DB).QueryRow(ctx, query, workspaceId, userId).Scan(&personal, &role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, entities.ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("erro ao buscar workspace: %w", err)
+	}
+	return role, personal, nil
+}
+
+// lockOwned é lockWorkspace exigindo que quem age seja owner.
+func (r *WorkspaceRepositoryImpl) lockOwned(ctx context.Context, userId, workspaceId string) (personal bool, err error) {
+	role, personal, err := r.lockWorkspace(ctx, userId, workspaceId)
+	if err != nil {
+		return false, err
+	}
+	if role != entities.WorkspaceRoleOwner {
+		return false, entities.ErrWorkspaceForbidden
+	}
+	return personal, nil
+}
+
+// keepsOwner confere se, sem memberId como owner, o workspace ainda fica com algum.
+func (r *WorkspaceRepositoryImpl) keepsOwner(ctx context.Context, workspaceId, memberId string) error {
+	var others int
+	query := `SELECT count(*) FROM workspace_members WHERE workspace_id = $1 AND member_role = 'owner' AND user_id <> $2`
+	if err := conn(ctx, r.DB).QueryRow(ctx, query, workspaceId, memberId).Scan(&others); err != nil {
+		return fmt.Errorf("erro ao contar owners: %w", err)
+	}
+	if others == 0 {
+		return entities.ErrLastWorkspaceOwner
+	}
+	return nil
+}
+
+func (r *WorkspaceRepositoryImpl) Create(ctx context.Context, userId string, workspace *entities.Workspace) (string, error) {
+	err := withTx(ctx, r.DB, func(ctx context.Context) error {
+		_, err := conn(ctx, r.DB).Exec(ctx, `INSERT INTO workspaces (id, workspace_name) VALUES ($1, $2)`, workspace.Id, workspace.Name)
+		if err != nil {
+			return fmt.Errorf("erro ao criar workspace: %w", err)
+		}
+		_, err = conn(ctx, r.DB).Exec(ctx, `INSERT INTO workspace_members (workspace_id, user_id, member_role) VALUES ($1, $2, 'owner')`,
+			workspace.Id, userId)
+		if err != nil {
+			return fmt.Errorf("erro ao adicionar owner: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return workspace.Id, nil
+}
+
+func (r *WorkspaceRepositoryImpl) FindAll(ctx context.Context, userId string) ([]*entities.Workspace, error) {
+	query := `SELECT ` + workspaceColumns + `
+		FROM workspaces w
+		JOIN workspace_members wm ON wm.workspace_id = w.id AND wm.user_id = $1
+		ORDER BY w.personal_of IS NULL, w.workspace_name`
+
+	rows, err := reader(ctx, r.DB).Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	workspaces := make([]*entities.Workspace, 0)
+	for rows.Next() {
+		workspace, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear workspace: %w", err)
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro durante a iteração: %w", err)
+	}
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepositoryImpl) FindByID(ctx context.Context, userId, id string) (*entities.Workspace, error) {
+	query := `SELECT ` + workspaceColumns + `
+		FROM workspaces w
+		JOIN workspace_members wm ON wm.workspace_id = w.id AND wm.user_id = $1
+		WHERE w.id = $2`
+
+	workspace, err := scanWorkspace(reader(ctx, r.DB).QueryRow(ctx, query, userId, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+func (r *WorkspaceRepositoryImpl) Update(ctx context.Context, userId string, workspace *entities.Workspace) error {
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		if _, err := r.lockOwned(ctx, userId, workspace.Id); err != nil {
+			return err
+		}
+		_, err := conn(ctx, r.DB).Exec(ctx, `UPDATE workspaces SET workspace_name = $1 WHERE id = $2`, workspace.Name, workspace.Id)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar workspace: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete apaga o workspace. O conteúdo não vai junto: cada item volta para o
+// workspace pessoal de quem o criou, já na lixeira, e as colheitas seguem a planta.
+func (r *WorkspaceRepositoryImpl) Delete(ctx context.Context, userId, id string) error {
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		personal, err := r.lockOwned(ctx, userId, id)
+		if err != nil {
+			return err
+		}
+		if personal {
+			return entities.ErrPersonalWorkspace
+		}
+		for _, t := range trashTables {
+			query := fmt.Sprintf(`UPDATE %s x
+				SET workspace_id = w.id, deleted_at = COALESCE(x.deleted_at, CURRENT_TIMESTAMP)
+				FROM workspaces w
+				WHERE w.personal_of = x.user_id AND x.workspace_id = $1`, t.table)
+			if _, err := conn(ctx, r.DB).Exec(ctx, query, id); err != nil {
+				return fmt.Errorf("erro ao mover %s para a lixeira: %w", t.table, err)
+			}
+		}
+		query := `UPDATE harvests h SET workspace_id = p.workspace_id
+			FROM plants p
+			WHERE p.id = h.plant_id AND h.workspace_id = $1`
+		if _, err := conn(ctx, r.DB).Exec(ctx, query, id); err != nil {
+			return fmt.Errorf("erro ao mover colheitas: %w", err)
+		}
+		if _, err := conn(ctx, r.DB).Exec(ctx, `DELETE FROM workspaces WHERE id = $1`, id); err != nil {
+			return fmt.Errorf("erro ao excluir workspace: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *WorkspaceRepositoryImpl) FindMembers(ctx context.Context, userId, workspaceId string) ([]*entities.WorkspaceMember, error) {
+	query := `SELECT u.id, u.user_name, u.email, wm.member_role, wm.created_at
+		FROM workspace_members wm
+		JOIN users u ON u.id = wm.user_id
+		WHERE wm.workspace_id = $1
+			AND EXISTS (SELECT 1 FROM workspace_members me WHERE me.workspace_id = $1 AND me.user_id = $2)
+		ORDER BY wm.created_at, u.user_name`
+
+	rows, err := reader(ctx, r.DB).Query(ctx, query, workspaceId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar membros: %w", err)
+	}
+	defer rows.Close()
+
+	members := make([]*entities.WorkspaceMember, 0)
+	for rows.Next() {
+		var member entities.WorkspaceMember
+		if err := rows.Scan(&member.UserId, &member.Name, &member.Email, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("erro ao escanear membro: %w", err)
+		}
+		members = append(members, &member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro durante a iteração: %w", err)
+	}
+	// quem consulta é sempre membro; lista vazia é porque não tem acesso
+	if len(members) == 0 {
+		return nil, entities.ErrWorkspaceNotFound
+	}
+	return members, nil
+}
+
+func (r *WorkspaceRepositoryImpl) UpdateMemberRole(ctx context.Context, userId, workspaceId, memberId, role string) error {
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		if _, err := r.lockOwned(ctx, userId, workspaceId); err != nil {
+			return err
+		}
+		if role != entities.WorkspaceRoleOwner {
+			if err := r.keepsOwner(ctx, workspaceId, memberId); err != nil {
+				return err
+			}
+		}
+
+		result, err := conn(ctx, r.DB).Exec(ctx, `UPDATE workspace_members SET member_role = $1 WHERE workspace_id = $2 AND user_id = $3`,
+			role, workspaceId, memberId)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar membro: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return entities.ErrMemberNotFound
+		}
+		return nil
+	})
+}
+
+// RemoveMember tira alguém do workspace: o owner remove qualquer um e cada membro
+// pode sair por conta própria.
+func (r *WorkspaceRepositoryImpl) RemoveMember(ctx context.Context, userId, workspaceId, memberId string) error {
+	return withTx(ctx, r.DB, func(ctx context.Context) error {
+		role, personal, err := r.lockWorkspace(ctx, userId, workspaceId)
+		if err != nil {
+			return err
+		}
+		if personal {
+			return entities.ErrPersonalWorkspace
+		}
+		if memberId != userId && role != entities.WorkspaceRoleOwner {
+			return entities.ErrWorkspaceForbidden
+		}
+		if err := r.keepsOwner(ctx, workspaceId, memberId); err != nil {
+			return err
+		}
+
+		result, err := conn(ctx, r.DB).Exec(ctx, `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, workspaceId, memberId)
+		if err != nil {
+			return fmt.Errorf("erro ao remover membro: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return entities.ErrMemberNotFound
+		}
+		return nil
+	})
+}
+
+func (r *WorkspaceRepositoryImpl) CreateInvitation(ctx context.Context, userId string, invitation *entities.WorkspaceInvitation) (string, error) {
+	err := withTx(ctx, r.DB, func(ctx context.Context) error {
+		personal, err := r.lockOwned(ctx, userId, invitation.WorkspaceId)
+		if err != nil {
+			return err
+		}
+		if personal {
+			return entities.ErrPersonalWorkspace
+		}
+
+		_, err = conn(ctx, r.DB).Exec(ctx, `DELETE FROM workspace_invitations
+			WHERE workspace_id = $1 AND lower(email) = lower($2) AND accepted_at IS NULL`, invitation.WorkspaceId, invitation.Email)
+		if err != nil {
+			return fmt.Errorf("erro ao substituir convite: %w", err)
+		}
+
+		// a validade é calculada no banco, no mesmo relógio que a confere no aceite
+		query := `INSERT INTO workspace_invitations (id, workspace_id, email, member_role, invited_by, expires_at)
+			VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP + make_interval(secs => $6))
+			RETURNING expires_at, created_at,
+				(SELECT workspace_name FROM workspaces WHERE id = $2)`
+		err = conn(ctx, r.DB).QueryRow(ctx, query, invitation.Id, invitation.WorkspaceId, invitation.Email, invitation.Role,
+			userId, r.InvitationTTL.Seconds()).Scan(&invitation.ExpiresAt, &invitation.CreatedAt, &invitation.WorkspaceName)
+		if err != nil {
+			return fmt.Errorf("erro ao criar convite: %w", err)
+		}
+		invitation.InvitedBy = userId
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return invitation.Id, nil
+}
+
+func (r *WorkspaceRepositoryImpl) FindInvitationsByEmail(ctx context.Context, email string) ([]*entities.WorkspaceInvitation, error) {
+	query := `SELECT i.id, i.workspace_id, w.workspace_name, i.email, i.member_role, i.invited_by, i.expires_at, i.created_at
+		FROM workspace_invitations i
+		JOIN workspaces w ON w.id = i.workspace_id
+		WHERE lower(i.email) = lower($1) AND i.accepted_at IS NULL AND i.expires_at > CURRENT_TIMESTAMP
+		ORDER BY i.created_at DESC`
+
+	rows, err := reader(ctx, r.DB).Query(ctx, query, email)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar convites: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := make([]*entities.WorkspaceInvitation, 0)
+	for rows.Next() {
+		var invitation entities.WorkspaceInvitation
+		err := rows.Scan(&invitation.Id, &invitation.WorkspaceId, &invitation.WorkspaceName, &invitation.Email,
+			&invitation.Role, &invitation.InvitedBy, &invitation.ExpiresAt, &invitation.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear convite: %w", err)
+		}
+		invitations = append(invitations, &invitation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro durante a iteração: %w", err)
+	}
+	return invitations, nil
+}
+
+// AcceptInvitation adiciona o usuário ao workspace do convite. Quem já é membro
+// mantém o papel que tinha.
+func (r *WorkspaceRepositoryImpl) AcceptInvitation(ctx context.Context, userId, email, invitationId string) (string, error) {
+	var workspaceId string
+	err := withTx(ctx, r.DB, func(ctx context.Context) error {
+		var role string
+		query := `SELECT workspace_id, member_role FROM workspace_invitations
+			WHERE id = $1 AND lower(email) = lower($2) AND accepted_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+			FOR UPDATE`
+		err := conn(ctx, r.DB).QueryRow(ctx, query, invitationId, email).Scan(&workspaceId, &role)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entities.ErrInvitationNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao buscar convite: %w", err)
+		}
+
+		_, err = conn(ctx, r.DB).Exec(ctx, `INSERT INTO workspace_members (workspace_id, user_id, member_role) VALUES ($1, $2, $3)
+			ON CONFLICT (workspace_id, user_id) DO NOTHING`, workspaceId, userId, role)
+		if err != nil {
+			return fmt.Errorf("erro ao adicionar membro: %w", err)
+		}
+		_, err = conn(ctx, r.DB).Exec(ctx, `UPDATE workspace_invitations SET accepted_at = CURRENT_TIMESTAMP WHERE id = $1`, invitationId)
+		if err != nil {
+			return fmt.Errorf("erro ao aceitar convite: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return workspaceId, nil
+}
+
+func (r *WorkspaceRepositoryImpl) CoMembers(ctx context.Context, userId string) ([]string, error) {
+	query := `SELECT DISTINCT m2.user_id FROM workspace_members m1
+		JOIN workspace_members m2 ON m2.workspace_id = m1.workspace_id
+		WHERE m1.user_id = $1`
+
+	// vai ao primário: logo depois de um aceite a réplica ainda não conhece o membro novo
+	rows, err := primary(ctx, r.DB).Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar membros: %w", err)
+	}
+	defer rows.Close()
+
+	users := []string{userId}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("erro ao escanear membro: %w", err)
+		}
+		if id != userId {
+			users = append(users, id)
+		}
+	}
+	return users, rows.Err()
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+// memberOf restringe column aos workspaces de que o usuário em userParam é membro,
+// com qualquer papel. userParam é um placeholder ($1 ou ? do whereBuilder).
+func memberOf(column, userParam string) string {
+	return fmt.Sprintf(`%s IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = %s)`, column, userParam)
+}
+
+// editorOf é memberOf só com os papéis que alteram conteúdo.
+func editorOf(column, userParam string) string {
+	return fmt.Sprintf(`%s IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = %s AND wm.member_role IN ('owner', 'editor'))`,
+		column, userParam)
+}
+
+// writableWorkspace devolve onde o usuário vai gravar um registro novo: no
+// workspace pedido, se ele puder editar lá, ou no pessoal dele.
+func writableWorkspace(ctx context.Context, db *database.Cluster, userId, workspaceId string) (string, error) {
+	if workspaceId == "" {
+		err := primary(ctx, db).QueryRow(ctx, `SELECT id FROM workspaces WHERE personal_of = $1`, userId).Scan(&workspaceId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return "", entities.ErrWorkspaceNotFound
+			}
+			return "", fmt.Errorf("erro ao buscar workspace pessoal: %w", err)
+		}
+		return workspaceId, nil
+	}
+
+	var role string
+	err := primary(ctx, db).QueryRow(ctx, `SELECT member_role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceId, userId).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", entities.ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("erro ao verificar workspace: %w", err)
+	}
+	if role == entities.WorkspaceRoleViewer {
+		return "", entities.ErrWorkspaceForbidden
+	}
+	return workspaceId, nil
+}
+
+// writeDenied explica uma escrita que não afetou nenhuma linha: devolve
+// ErrWorkspaceForbidden se o usuário enxerga o registro mas não pode alterá-lo,
+// e nil quando o motivo é outro (não existe, está na lixeira, versão antiga).
+func writeDenied(ctx context.Context, db *database.Cluster, table, id, userId string) error {
+	query := fmt.Sprintf(`SELECT wm.member_role FROM %s x
+		JOIN workspace_members wm ON wm.workspace_id = x.workspace_id AND wm.user_id = $2
+		WHERE x.id = $1`, table)
+	var role string
+	err := primary(ctx, db).QueryRow(ctx, query, id, userId).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao verificar permissão: %w", err)
+	}
+	if role == entities.WorkspaceRoleViewer {
+		return entities.ErrWorkspaceForbidden
+	}
+	return nil
+}
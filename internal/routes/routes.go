@@ -1,34 +1,48 @@
 package routes
 
 import (
-	"database/sql"
+	"context"
 
 	"github.com/go-chi/chi"
 	"github.com/go-redis/redis/v8"
+	"github.com/lucasBiazon/botany-back/internal/cache"
+	"github.com/lucasBiazon/botany-back/internal/config"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/jobs"
 	"github.com/lucasBiazon/botany-back/internal/middleware"
 	"github.com/lucasBiazon/botany-back/internal/repositories"
+	usecases_care "github.com/lucasBiazon/botany-back/internal/usecases/care"
 	usecases_categoryplant "github.com/lucasBiazon/botany-back/internal/usecases/category-plant"
 	usecases_categoryTask "github.com/lucasBiazon/botany-back/internal/usecases/category-task"
 	usecases_garden "github.com/lucasBiazon/botany-back/internal/usecases/garden"
+	usecases_harvest "github.com/lucasBiazon/botany-back/internal/usecases/harvest"
 	usecases_plant "github.com/lucasBiazon/botany-back/internal/usecases/plant"
+	usecases_search "github.com/lucasBiazon/botany-back/internal/usecases/search"
 	usecases_specie "github.com/lucasBiazon/botany-back/internal/usecases/specie"
 	usecases_task "github.com/lucasBiazon/botany-back/internal/usecases/task"
+	usecases_trash "github.com/lucasBiazon/botany-back/internal/usecases/trash"
 	usecases "github.com/lucasBiazon/botany-back/internal/usecases/user"
+	usecases_workspace "github.com/lucasBiazon/botany-back/internal/usecases/workspace"
 
 	services "github.com/lucasBiazon/botany-back/internal/service"
 	handlers "github.com/lucasBiazon/botany-back/internal/web"
 )
 
-func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services.JWTService) (*chi.Mux, error) {
+func InitializeRoutes(ctx context.Context, db *database.Cluster, clientRedis *redis.Client, jwtService services.JWTService, cfg *config.Config) (*chi.Mux, error) {
+	emailService := services.NewEmailService(cfg.Email.SMTPHost, cfg.Email.SMTPPort, cfg.Email.User, cfg.Email.Password)
+	cacheStore := cache.NewFallback(clientRedis, cache.NewLRU(cfg.Cache.LocalSize))
+	readThrough := cache.NewReadThrough(cacheStore, cache.NewPGBus(db.Primary, cfg.Cache.InvalidationChannel))
+	readThrough.Settle = cfg.Database.ReplicaStickyWindow
+	go readThrough.Listen(ctx)
 
 	// User Routes
-	repository := repositories.NewUserRepository(db, clientRedis)
-	RegisterUserRoutes := usecases.NewRegisterUserUseCase(repository)
-	LoginUserRoutes := usecases.NewLoginUserUseCase(repository)
+	repository := repositories.NewUserRepository(db, cacheStore, cfg.Tokens.EmailVerificationTTL, cfg.Tokens.PasswordResetTTL)
+	RegisterUserRoutes := usecases.NewRegisterUserUseCase(repository, emailService)
+	LoginUserRoutes := usecases.NewLoginUserUseCase(repository, jwtService)
 	FindUserRoutes := usecases.NewFindUserByIdUseCase(repository)
 	DeleteUserRoutes := usecases.NewDeleteUserUseCase(repository)
 	UpdateUserRoutes := usecases.NewUpdateUserUseCase(repository)
-	RequestPasswordResetUserRoutes := usecases.NewRequestPasswordResetUseCase(repository, jwtService)
+	RequestPasswordResetUserRoutes := usecases.NewRequestPasswordResetUseCase(repository, jwtService, emailService, cfg.Tokens.PasswordResetTTL)
 	ResetPasswordUserRoutes := usecases.NewResetPasswordUserUseCase(repository, jwtService)
 
 	userHandlers := handlers.NewUserHandlers(
@@ -39,10 +53,38 @@ func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services
 		UpdateUserRoutes,
 		RequestPasswordResetUserRoutes,
 		ResetPasswordUserRoutes,
+		jwtService,
+	)
+
+	// workspace routes
+	repositoryWorkspace := repositories.NewCachedWorkspaceRepository(repositories.NewWorkspaceRepository(db, cfg.Tokens.WorkspaceInvitationTTL), readThrough)
+	CreateWorkspaceRoutes := usecases_workspace.NewCreateWorkspaceUseCase(repositoryWorkspace)
+	FindAllWorkspaceRoutes := usecases_workspace.NewFindAllWorkspaceUseCase(repositoryWorkspace)
+	UpdateWorkspaceRoutes := usecases_workspace.NewUpdateWorkspaceUseCase(repositoryWorkspace)
+	DeleteWorkspaceRoutes := usecases_workspace.NewDeleteWorkspaceUseCase(repositoryWorkspace)
+	FindMembersWorkspaceRoutes := usecases_workspace.NewFindMembersWorkspaceUseCase(repositoryWorkspace)
+	UpdateMemberWorkspaceRoutes := usecases_workspace.NewUpdateMemberWorkspaceUseCase(repositoryWorkspace)
+	RemoveMemberWorkspaceRoutes := usecases_workspace.NewRemoveMemberWorkspaceUseCase(repositoryWorkspace)
+	InviteWorkspaceRoutes := usecases_workspace.NewInviteWorkspaceUseCase(repositoryWorkspace, emailService)
+	FindInvitationsWorkspaceRoutes := usecases_workspace.NewFindInvitationsWorkspaceUseCase(repositoryWorkspace, repository)
+	AcceptInvitationWorkspaceRoutes := usecases_workspace.NewAcceptInvitationWorkspaceUseCase(repositoryWorkspace, repository)
+
+	workspaceHandlers := handlers.NewWorkspaceHandler(
+		CreateWorkspaceRoutes,
+		FindAllWorkspaceRoutes,
+		UpdateWorkspaceRoutes,
+		DeleteWorkspaceRoutes,
+		FindMembersWorkspaceRoutes,
+		UpdateMemberWorkspaceRoutes,
+		RemoveMemberWorkspaceRoutes,
+		InviteWorkspaceRoutes,
+		FindInvitationsWorkspaceRoutes,
+		AcceptInvitationWorkspaceRoutes,
+		jwtService,
 	)
 
 	// Category Plant Routes
-	repositoryCategoriesPlants := repositories.NewCategoryPlantRepository(db, clientRedis)
+	repositoryCategoriesPlants := repositories.NewCachedCategoryPlantRepository(repositories.NewCategoryPlantRepository(db), readThrough, cfg.Cache.ListTTL, cfg.Cache.ItemTTL, repositoryWorkspace)
 	CreateCategoryPlantRoutes := usecases_categoryplant.NewCreateCategoryPlantUseCase(repositoryCategoriesPlants)
 	FindAllCategoryPlantRoutes := usecases_categoryplant.NewFindAllCategoryPlantUseCase(repositoryCategoriesPlants)
 	FindByCategoryPlantRoutes := usecases_categoryplant.NewFindByIdCategoryPlantUseCase(repositoryCategoriesPlants)
@@ -57,22 +99,30 @@ func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services
 		FindByCategoryPlantRoutes,
 		FindByNameCategoryPlantRoutes,
 		UpdateCategoryPlantRoutes,
+		jwtService,
 	)
 
 	// specie Routes
-	repositorySpecies := repositories.NewSpeciesRepository(db, clientRedis)
+	repositorySpecies := repositories.NewCachedSpeciesRepository(repositories.NewSpeciesRepository(db), readThrough, cfg.Cache.SpeciesTTL)
 	FindAllSpecieRoutes := usecases_specie.NewFindAllSpecieUseCase(repositorySpecies)
 	FindByIdSpecieRoutes := usecases_specie.NewFindByIdSpecieUseCase(repositorySpecies)
 	FindByNameSpecieRoutes := usecases_specie.NewFindByNameSpecieUseCase(repositorySpecies)
 
+	ImportSpecieCatalogRoutes := usecases_specie.NewImportSpecieCatalogUseCase(repositorySpecies)
+	ExportSpecieCatalogRoutes := usecases_specie.NewExportSpecieCatalogUseCase(repositorySpecies)
+	specieCatalogHandlers := handlers.NewSpecieCatalogHandler(ImportSpecieCatalogRoutes, ExportSpecieCatalogRoutes)
+
 	specieHandlers := handlers.NewSpecieHandler(
 		FindAllSpecieRoutes,
 		FindByIdSpecieRoutes,
 		FindByNameSpecieRoutes,
+		jwtService,
 	)
 
+	uow := repositories.NewUnitOfWork(db)
+
 	// plant Routes
-	repositoryPlant := repositories.NewPlantRepositoryImpl(db, clientRedis)
+	repositoryPlant := repositories.NewCachedPlantRepository(repositories.NewPlantRepositoryImpl(db, clientRedis), readThrough, cfg.Cache.PlantTTL, repositoryWorkspace)
 	CreatePlantRoute := usecases_plant.NewCreatePlantUseCase(repositoryPlant, repositorySpecies)
 	DeletePlantRoute := usecases_plant.NewDeletePlantUseCase(repositoryPlant, repositorySpecies)
 	FindAllPlantRoute := usecases_plant.NewFindAllPlantUseCase(repositoryPlant)
@@ -80,8 +130,13 @@ func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services
 	FindByIdPlantRoute := usecases_plant.NewFindByIdPlantUseCase(repositoryPlant)
 	FindByNameCategoryPlantRoute := usecases_plant.NewFindByNameCategoryPlantUseCase(repositoryPlant)
 	FindBySpecieNamePlantRoute := usecases_plant.NewFindBySpecieNamePlantUseCase(repositoryPlant)
-	UpdatePlantRoute := usecases_plant.NewUpdatePlantUseCase(repositoryPlant)
+	UpdatePlantRoute := usecases_plant.NewUpdatePlantUseCase(repositoryPlant, repositorySpecies, uow)
 	FindAllHistoryPlantRoutes := usecases_plant.NewFindAllHistoryPlantUseCase(repositoryPlant)
+	SearchPlantRoutes := usecases_plant.NewSearchPlantUseCase(repositoryPlant)
+	SetAutoTasksPlantRoutes := usecases_plant.NewSetAutoTasksPlantUseCase(repositoryPlant)
+	LogCarePlantRoutes := usecases_plant.NewLogCarePlantUseCase(repositoryPlant)
+	FindGrowthPlantRoutes := usecases_plant.NewFindGrowthPlantUseCase(repositoryPlant, repositorySpecies)
+	PredictHarvestPlantRoutes := usecases_plant.NewPredictHarvestPlantUseCase(repositoryPlant, repositorySpecies)
 
 	plantHandlers := handlers.NewPlantHandler(
 		CreatePlantRoute,
@@ -92,12 +147,17 @@ func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services
 		FindByNameCategoryPlantRoute,
 		FindBySpecieNamePlantRoute,
 		UpdatePlantRoute,
-		FindByIdSpecieRoutes,
 		FindAllHistoryPlantRoutes,
+		SearchPlantRoutes,
+		SetAutoTasksPlantRoutes,
+		LogCarePlantRoutes,
+		FindGrowthPlantRoutes,
+		PredictHarvestPlantRoutes,
+		jwtService,
 	)
 
 	// category task routes
-	repositoryCategoriesTasks := repositories.NewCategoryTaskRepository(db, clientRedis)
+	repositoryCategoriesTasks := repositories.NewCachedCategoryTaskRepository(repositories.NewCategoryTaskRepository(db), readThrough, cfg.Cache.ListTTL, cfg.Cache.ItemTTL, repositoryWorkspace)
 	CreateCategoryTaskRoutes := usecases_categoryTask.NewCreateCategoryTaskUseCase(repositoryCategoriesTasks)
 	FindAllCategoryTaskRoutes := usecases_categoryTask.NewFindAllCategoryTaskUseCase(repositoryCategoriesTasks)
 	FindByCategoryTaskRoutes := usecases_categoryTask.NewFindByIdCategoryTaskUseCase(repositoryCategoriesTasks)
@@ -112,10 +172,11 @@ func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services
 		FindByCategoryTaskRoutes,
 		FindByNameCategoryTaskRoutes,
 		UpdateCategoryTaskRoutes,
+		jwtService,
 	)
 
 	// garden routes
-	repositoryGarden := repositories.NewGardenRepository(db)
+	repositoryGarden := repositories.NewCachedGardenRepository(repositories.NewGardenRepository(db), readThrough, cfg.Cache.GardenTTL, repositoryWorkspace)
 	CreateGardenRoutes := usecases_garden.NewCreateGardenUseCase(repositoryGarden)
 	DeleteGardenRoutes := usecases_garden.NewDeleteGardenUseCase(repositoryGarden)
 	FindAllGardenRoutes := usecases_garden.NewFindAllGardenUseCase(repositoryGarden)
@@ -123,8 +184,11 @@ func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services
 	FindByNameRoutes := usecases_garden.NewFindByNameGardenUseCase(repositoryGarden)
 	FindByCategoryGardenRoutes := usecases_garden.NewFindByCategoryNameGardenUseCase(repositoryGarden)
 	FindByLocatiopnGardenRoutes := usecases_garden.NewFindByLocationGardenUseCase(repositoryGarden)
-	UpdateGardenRoutes := usecases_garden.NewUpdateGardenUseCase(repositoryGarden)
+	UpdateGardenRoutes := usecases_garden.NewUpdateGardenUseCase(repositoryGarden, uow)
 	FindAllHistoryGardenRoutes := usecases_garden.NewFindAllHistoryGardenUseCase(repositoryGarden)
+	SearchGardenRoutes := usecases_garden.NewSearchGardenUseCase(repositoryGarden)
+	SetAutoTasksGardenRoutes := usecases_garden.NewSetAutoTasksGardenUseCase(repositoryGarden)
+	LogCareGardenRoutes := usecases_garden.NewLogCareGardenUseCase(repositoryGarden)
 	gardenHandlers := handlers.NewGardenHandler(
 		CreateGardenRoutes,
 		DeleteGardenRoutes,
@@ -135,10 +199,14 @@ func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services
 		FindByNameRoutes,
 		FindByCategoryGardenRoutes,
 		FindAllHistoryGardenRoutes,
+		SearchGardenRoutes,
+		SetAutoTasksGardenRoutes,
+		LogCareGardenRoutes,
+		jwtService,
 	)
 
 	// task routes
-	repositoryTask := repositories.NewTaskRepository(db)
+	repositoryTask := repositories.NewCachedTaskRepository(repositories.NewTaskRepository(db), readThrough, cfg.Cache.TaskTTL, repositoryWorkspace)
 	CreateTaskRoutes := usecases_task.NewCreateTaskUseCase(repositoryTask)
 	DeleteTaskRoutes := usecases_task.NewDeleteTaskUseCase(repositoryTask)
 	FindAllTaskRoutes := usecases_task.NewFindAllTaskUseCase(repositoryTask)
@@ -148,6 +216,7 @@ func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services
 	UpdateTaskRoutes := usecases_task.NewUpdateTaskUseCase(repositoryTask)
 	FindByStatusTaskRoutes := usecases_task.NewFindByStatusTaskUseCase(repositoryTask)
 	FindByUrgencyLevelTaskRoutes := usecases_task.NewFindByUrgencyLevelTaskUseCase(repositoryTask)
+	SearchTaskRoutes := usecases_task.NewSearchTaskUseCase(repositoryTask)
 
 	taskHandlers := handlers.NewTaskHandler(
 		CreateTaskRoutes,
@@ -159,91 +228,204 @@ func InitializeRoutes(db *sql.DB, clientRedis *redis.Client, jwtService services
 		FindByNameTaskRoutes,
 		FindByStatusTaskRoutes,
 		FindByUrgencyLevelTaskRoutes,
+		SearchTaskRoutes,
+		jwtService,
 	)
+
+	// search routes
+	repositorySearch := repositories.NewSearchRepository(db)
+	SearchRoutes := usecases_search.NewSearchUseCase(repositorySearch)
+	searchHandlers := handlers.NewSearchHandler(SearchRoutes, jwtService)
+
+	// trash routes
+	repositoryTrash := repositories.NewCachedTrashRepository(repositories.NewTrashRepository(db), readThrough, repositoryWorkspace)
+	ListTrashRoutes := usecases_trash.NewListTrashUseCase(repositoryTrash)
+	RestoreTrashRoutes := usecases_trash.NewRestoreTrashUseCase(repositoryTrash)
+	trashHandlers := handlers.NewTrashHandler(ListTrashRoutes, RestoreTrashRoutes, jwtService)
+
+	// harvest routes
+	repositoryHarvest := repositories.NewCachedHarvestRepository(repositories.NewHarvestRepository(db), readThrough, cfg.Cache.ListTTL, repositoryWorkspace)
+	CreateHarvestRoutes := usecases_harvest.NewCreateHarvestUseCase(repositoryHarvest)
+	FindAllHarvestRoutes := usecases_harvest.NewFindAllHarvestUseCase(repositoryHarvest)
+	DeleteHarvestRoutes := usecases_harvest.NewDeleteHarvestUseCase(repositoryHarvest)
+	StatsHarvestRoutes := usecases_harvest.NewStatsHarvestUseCase(repositoryHarvest)
+	harvestHandlers := handlers.NewHarvestHandler(CreateHarvestRoutes, FindAllHarvestRoutes, DeleteHarvestRoutes, StatsHarvestRoutes, jwtService)
+
+	// care routes
+	repositoryCare := repositories.NewCachedCareRepository(repositories.NewCareRepository(db), readThrough, repositoryWorkspace)
+	FindDueCareRoutes := usecases_care.NewFindDueCareUseCase(repositoryCare)
+	careHandlers := handlers.NewCareHandler(FindDueCareRoutes, jwtService)
+	if cfg.CareTasks.Enabled {
+		// fica aqui e não no main porque invalida as listagens de tarefas no cache
+		go jobs.NewCareTaskGenerator(repositoryCare, cfg.CareTasks.Horizon, cfg.CareTasks.Interval).Run(ctx)
+	}
+
+	// health routes
+	healthHandlers := handlers.NewHealthHandler(db, clientRedis)
+
 	// Routes
 	r := chi.NewRouter()
-	r.Use(middleware.ApiKeyMiddleware)
-	r.Use(middleware.RateLimitMiddleware(clientRedis))
-	r.Use(middleware.RetryMiddleware(3, 2))
-	r.Route("/api/v1", func(r chi.Router) {
-		r.Post("/register", userHandlers.RegisterUserHandler)
-		r.Post("/register/confirm", userHandlers.ConfirmEmailHandler)
-		r.Post("/register/resend-token", userHandlers.ResendTokenHandler)
-		r.Post("/login", userHandlers.LoginUserHandler)
-		r.Post("/password-reset/request", userHandlers.RequestPasswordResetUserHandler)
-		r.Post("/password-reset", userHandlers.ResetPasswordUserHandler)
-	})
-	r.Route("/api/v1/user", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware(jwtService))
-		r.Get("/", userHandlers.FindByIdUserHandler)
-		r.Delete("/", userHandlers.DeleteUserHandler)
-		r.Put("/", userHandlers.UpdateUserHandler)
-	})
 
-	r.Route("/api/v1/category-plant", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware(jwtService))
-		r.Post("/", categoryPlantHandlers.CreateCategoryPlantHandler)
-		r.Get("/", categoryPlantHandlers.FindAllCategoryPlantHandler)
-		r.Get("/id", categoryPlantHandlers.FindByIdCategoryPlantHandler)
-		r.Get("/name", categoryPlantHandlers.FindByNameCategoryPlantHandler)
-		r.Put("/", categoryPlantHandlers.UpdateCategoryPlantHandler)
-		r.Delete("/", categoryPlantHandlers.DeleteCategoryPlantHandler)
-	})
+	// probes ficam fora da api key e do rate limit para o orquestrador conseguir consultar
+	r.Get("/healthz", healthHandlers.LivenessHandler)
+	r.Get("/readyz", healthHandlers.ReadinessHandler)
 
-	r.Route("/api/v1/category-task", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware(jwtService))
-		r.Post("/", categoryTaskHandlers.CreateCategoryTaskHandler)
-		r.Get("/", categoryTaskHandlers.FindAllCategoryTaskHandler)
-		r.Get("/id", categoryTaskHandlers.FindByIdCategoryTaskHandler)
-		r.Get("/name", categoryTaskHandlers.FindByNameCategoryTaskHandler)
-		r.Put("/", categoryTaskHandlers.UpdateCategoryTaskHandler)
-		r.Delete("/", categoryTaskHandlers.DeleteCategoryTaskHandler)
-	})
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.ApiKeyMiddleware(cfg.Security.APIKey))
+		r.Use(middleware.RateLimitMiddleware(clientRedis, cfg.RateLimit))
+		r.Use(middleware.RetryMiddleware(3, 2))
+		r.Use(middleware.ReadAfterWriteMiddleware(cacheStore, cfg.Database.ReplicaStickyWindow))
+		r.Route("/api/v1", func(r chi.Router) {
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("auth")))
+			r.Post("/register", userHandlers.RegisterUserHandler)
+			r.Post("/register/confirm", userHandlers.ConfirmEmailHandler)
+			r.Post("/register/resend-token", userHandlers.ResendTokenHandler)
+			r.Post("/login", userHandlers.LoginUserHandler)
+			r.Post("/password-reset/request", userHandlers.RequestPasswordResetUserHandler)
+			r.Post("/password-reset", userHandlers.ResetPasswordUserHandler)
+		})
+		r.Route("/api/v1/user", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("user")))
+			r.Get("/", userHandlers.FindByIdUserHandler)
+			r.Delete("/", userHandlers.DeleteUserHandler)
+			r.Put("/", userHandlers.UpdateUserHandler)
+		})
 
-	r.Route("/api/v1/specie", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware(jwtService))
-		r.Get("/", specieHandlers.FindAllSpeciesHandler)
-		r.Get("/id", specieHandlers.FindByIdSpecieHandler)
-		r.Get("/name", specieHandlers.FindByNameSpecieHandler)
-	})
+		r.Route("/api/v1/workspace", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("workspace")))
+			r.Post("/", workspaceHandlers.CreateWorkspaceHandler)
+			r.Get("/", workspaceHandlers.FindAllWorkspaceHandler)
+			r.Put("/", workspaceHandlers.UpdateWorkspaceHandler)
+			r.Delete("/", workspaceHandlers.DeleteWorkspaceHandler)
+			r.Get("/members", workspaceHandlers.FindMembersWorkspaceHandler)
+			r.Put("/members", workspaceHandlers.UpdateMemberWorkspaceHandler)
+			r.Delete("/members", workspaceHandlers.RemoveMemberWorkspaceHandler)
+			r.Post("/invitations", workspaceHandlers.InviteWorkspaceHandler)
+			r.Get("/invitations", workspaceHandlers.FindInvitationsWorkspaceHandler)
+			r.Post("/invitations/accept", workspaceHandlers.AcceptInvitationWorkspaceHandler)
+		})
 
-	r.Route("/api/v1/plant", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware(jwtService))
-		r.Post("/", plantHandlers.CreatePlantHandler)
-		r.Delete("/", plantHandlers.DeletePlantHandler)
-		r.Get("/", plantHandlers.FindAllPlantHandler)
-		r.Get("/category-name", plantHandlers.FindByCategoryNamePlantHandler)
-		r.Get("/id", plantHandlers.FindByIdPlantHandler)
-		r.Get("/specie-plant-name", plantHandlers.FindBySpecieNamePlantHandler)
-		r.Get("/name", plantHandlers.FindByNamePlantHandler)
-		r.Put("/", plantHandlers.UpdatePlantHandler)
-		r.Get("/history", plantHandlers.FindAllHistoryPlantHandler)
-	})
+		r.Route("/api/v1/category-plant", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("category-plant")))
+			r.Post("/", categoryPlantHandlers.CreateCategoryPlantHandler)
+			r.Get("/", categoryPlantHandlers.FindAllCategoryPlantHandler)
+			r.Get("/id", categoryPlantHandlers.FindByIdCategoryPlantHandler)
+			r.Get("/name", categoryPlantHandlers.FindByNameCategoryPlantHandler)
+			r.Put("/", categoryPlantHandlers.UpdateCategoryPlantHandler)
+			r.Delete("/", categoryPlantHandlers.DeleteCategoryPlantHandler)
+		})
 
-	r.Route("/api/v1/garden", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware(jwtService))
-		r.Post("/", gardenHandlers.CreateGardenHandler)
-		r.Delete("/", gardenHandlers.DeleteGardenHandler)
-		r.Get("/", gardenHandlers.FindAllGardenHandler)
-		r.Get("/id", gardenHandlers.FindByIdGardenHandler)
-		r.Get("/name", gardenHandlers.FindByNameGardenHandler)
-		r.Get("/category-name", gardenHandlers.FindByCategoryNameGardenHandler)
-		r.Get("/location", gardenHandlers.FindByLocationGardenHandler)
-		r.Put("/", gardenHandlers.UpdateGardenHandler)
-		r.Get("/history", gardenHandlers.FindAllHistoryGardenHandler)
-	})
+		r.Route("/api/v1/category-task", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("category-task")))
+			r.Post("/", categoryTaskHandlers.CreateCategoryTaskHandler)
+			r.Get("/", categoryTaskHandlers.FindAllCategoryTaskHandler)
+			r.Get("/id", categoryTaskHandlers.FindByIdCategoryTaskHandler)
+			r.Get("/name", categoryTaskHandlers.FindByNameCategoryTaskHandler)
+			r.Put("/", categoryTaskHandlers.UpdateCategoryTaskHandler)
+			r.Delete("/", categoryTaskHandlers.DeleteCategoryTaskHandler)
+		})
+
+		r.Route("/api/v1/specie", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("specie")))
+			r.Get("/", specieHandlers.FindAllSpeciesHandler)
+			r.Get("/id", specieHandlers.FindByIdSpecieHandler)
+			r.Get("/name", specieHandlers.FindByNameSpecieHandler)
+		})
+
+		if cfg.Security.AdminKey != "" {
+			r.Route("/api/v1/admin", func(r chi.Router) {
+				r.Use(middleware.AdminKeyMiddleware(cfg.Security.AdminKey))
+				r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("admin")))
+				r.Post("/species/import", specieCatalogHandlers.ImportSpecieCatalogHandler)
+				r.Get("/species/export", specieCatalogHandlers.ExportSpecieCatalogHandler)
+			})
+		}
+
+		r.Route("/api/v1/plant", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("plant")))
+			r.Post("/", plantHandlers.CreatePlantHandler)
+			r.Delete("/", plantHandlers.DeletePlantHandler)
+			r.Get("/", plantHandlers.FindAllPlantHandler)
+			r.Get("/category-name", plantHandlers.FindByCategoryNamePlantHandler)
+			r.Get("/id", plantHandlers.FindByIdPlantHandler)
+			r.Get("/specie-plant-name", plantHandlers.FindBySpecieNamePlantHandler)
+			r.Get("/name", plantHandlers.FindByNamePlantHandler)
+			r.Put("/", plantHandlers.UpdatePlantHandler)
+			r.Get("/history", plantHandlers.FindAllHistoryPlantHandler)
+			r.Get("/search", plantHandlers.SearchPlantHandler)
+			r.Put("/auto-tasks", plantHandlers.SetAutoTasksPlantHandler)
+			r.Post("/care", plantHandlers.LogCareBulkPlantHandler)
+			r.Post("/{id}/care", plantHandlers.LogCarePlantHandler)
+			r.Get("/{id}/growth", plantHandlers.FindGrowthPlantHandler)
+			r.Get("/{id}/harvest-prediction", plantHandlers.PredictHarvestPlantHandler)
+		})
+
+		r.Route("/api/v1/garden", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("garden")))
+			r.Post("/", gardenHandlers.CreateGardenHandler)
+			r.Delete("/", gardenHandlers.DeleteGardenHandler)
+			r.Get("/", gardenHandlers.FindAllGardenHandler)
+			r.Get("/id", gardenHandlers.FindByIdGardenHandler)
+			r.Get("/name", gardenHandlers.FindByNameGardenHandler)
+			r.Get("/category-name", gardenHandlers.FindByCategoryNameGardenHandler)
+			r.Get("/location", gardenHandlers.FindByLocationGardenHandler)
+			r.Put("/", gardenHandlers.UpdateGardenHandler)
+			r.Get("/history", gardenHandlers.FindAllHistoryGardenHandler)
+			r.Get("/search", gardenHandlers.SearchGardenHandler)
+			r.Put("/auto-tasks", gardenHandlers.SetAutoTasksGardenHandler)
+			r.Post("/care", gardenHandlers.LogCareBulkGardenHandler)
+			r.Post("/{id}/care", gardenHandlers.LogCareGardenHandler)
+		})
+
+		r.Route("/api/v1/search", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("search")))
+			r.Get("/", searchHandlers.GlobalSearchHandler)
+		})
+
+		r.Route("/api/v1/harvest", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("harvest")))
+			r.Post("/", harvestHandlers.CreateHarvestHandler)
+			r.Get("/", harvestHandlers.FindAllHarvestHandler)
+			r.Delete("/", harvestHandlers.DeleteHarvestHandler)
+			r.Get("/stats", harvestHandlers.StatsHarvestHandler)
+		})
+
+		r.Route("/api/v1/care", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("care")))
+			r.Get("/due", careHandlers.FindDueCareHandler)
+		})
+
+		r.Route("/api/v1/trash", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("trash")))
+			r.Get("/", trashHandlers.ListTrashHandler)
+			r.Post("/restore", trashHandlers.RestoreTrashHandler)
+		})
 
-	r.Route("/api/v1/task", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware(jwtService))
-		r.Post("/", taskHandlers.CreateTaskHandler)
-		r.Delete("/", taskHandlers.DeleteTaskHandler)
-		r.Get("/", taskHandlers.FindAllTaskHandler)
-		r.Get("/category-name", taskHandlers.FindByCategoryNameTaskHandler)
-		r.Get("/id", taskHandlers.FindByIdTaskHandler)
-		r.Get("/name", taskHandlers.FindByNameTaskHandler)
-		r.Put("/", taskHandlers.UpdateTaskHandler)
-		r.Get("/status", taskHandlers.FindByStatusTaskHandler)
-		r.Get("/urgency-level", taskHandlers.FindByUrgencyLevelTaskHandler)
+		r.Route("/api/v1/task", func(r chi.Router) {
+			r.Use(middleware.AuthMiddleware(jwtService))
+			r.Use(middleware.TimeoutMiddleware(cfg.Server.Timeout("task")))
+			r.Post("/", taskHandlers.CreateTaskHandler)
+			r.Delete("/", taskHandlers.DeleteTaskHandler)
+			r.Get("/", taskHandlers.FindAllTaskHandler)
+			r.Get("/category-name", taskHandlers.FindByCategoryNameTaskHandler)
+			r.Get("/id", taskHandlers.FindByIdTaskHandler)
+			r.Get("/name", taskHandlers.FindByNameTaskHandler)
+			r.Put("/", taskHandlers.UpdateTaskHandler)
+			r.Get("/status", taskHandlers.FindByStatusTaskHandler)
+			r.Get("/urgency-level", taskHandlers.FindByUrgencyLevelTaskHandler)
+			r.Get("/search", taskHandlers.SearchTaskHandler)
+		})
 	})
 
 	return r, nil
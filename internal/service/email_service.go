@@ -3,9 +3,9 @@ package services
 import (
 	"crypto/rand"
 	"fmt"
+	"html"
 	"log"
 	"math/big"
-	"os"
 
 	gomail "gopkg.in/mail.v2"
 )
@@ -14,13 +14,23 @@ type EmailService interface {
 	GenerateCode() (string, error)
 	SendEmail(inputEmail string, code string) error
 	SendEmailResetPassword(inputEmail string, code string) error
+	SendWorkspaceInvitation(inputEmail string, workspaceName string) error
 }
 
 type EmailServiceImpl struct {
+	host     string
+	port     int
+	user     string
+	password string
 }
 
-func NewEmailService() *EmailServiceImpl {
-	return &EmailServiceImpl{}
+func NewEmailService(host string, port int, user, password string) *EmailServiceImpl {
+	return &EmailServiceImpl{
+		host:     host,
+		port:     port,
+		user:     user,
+		password: password,
+	}
 }
 
 func (e *EmailServiceImpl) GenerateCode() (string, error) {
@@ -88,11 +98,11 @@ func (e *EmailServiceImpl) SendEmail(inputEmail string, code string) error {
     `, code)
 
 	message := gomail.NewMessage()
-	message.SetHeader("From", os.Getenv("EMAIL_USER"))
+	message.SetHeader("From", e.user)
 	message.SetHeader("To", inputEmail)
 	message.SetHeader("Subject", "Verificação de Email")
 	message.SetBody("text/html", htmlCorpo)
-	dialer := gomail.NewDialer("smtp.gmail.com", 587, os.Getenv("EMAIL_USER"), os.Getenv("EMAIL_PASSWORD"))
+	dialer := gomail.NewDialer(e.host, e.port, e.user, e.password)
 
 	if err := dialer.DialAndSend(message); err != nil {
 		fmt.Println("Error:", err)
@@ -159,11 +169,11 @@ func (e *EmailServiceImpl) SendEmailResetPassword(inputEmail, code string) error
     `, code)
 
 	message := gomail.NewMessage()
-	message.SetHeader("From", os.Getenv("EMAIL_USER"))
+	message.SetHeader("From", e.user)
 	message.SetHeader("To", inputEmail)
 	message.SetHeader("Subject", "Recuperação de Senha")
 	message.SetBody("text/html", htmlCorpo)
-	dialer := gomail.NewDialer("smtp.gmail.com", 587, os.Getenv("EMAIL_USER"), os.Getenv("EMAIL_PASSWORD"))
+	dialer := gomail.NewDialer(e.host, e.port, e.user, e.password)
 
 	if err := dialer.DialAndSend(message); err != nil {
 		log.Println("Error:", err)
@@ -173,3 +183,73 @@ func (e *EmailServiceImpl) SendEmailResetPassword(inputEmail, code string) error
 	}
 	return nil
 }
+
+func (e *EmailServiceImpl) SendWorkspaceInvitation(inputEmail, workspaceName string) error {
+	log.Println("Sending workspace invitation to:", inputEmail)
+	htmlCorpo := fmt.Sprintf(`
+        <!DOCTYPE html>
+        <html lang="pt-BR">
+        <head>
+            <meta charset="UTF-8">
+            <meta name="viewport" content="width=device-width, initial-scale=1.0">
+            <title>Convite para Workspace</title>
+            <style>
+                body {
+                    font-family: Arial, sans-serif;
+                    color: #333;
+                    margin: 0;
+                    padding: 0;
+                    background-color: #f4f4f4;
+                }
+                .container {
+                    max-width: 600px;
+                    margin: 0 auto;
+                    background-color: #fff;
+                    padding: 20px;
+                    border-radius: 5px;
+                    box-shadow: 0 2px 4px rgba(0, 0, 0, 0.1);
+                }
+                h1 {
+                    color: #4CAF50;
+                }
+                p {
+                    font-size: 16px;
+                    line-height: 1.5;
+                }
+                .code {
+                    font-size: 24px;
+                    font-weight: bold;
+                    color: #4CAF50;
+                    background-color: #f0f0f0;
+                    padding: 10px;
+                    border-radius: 5px;
+                    text-align: center;
+                }
+            </style>
+        </head>
+        <body>
+            <div class="container">
+                <h1>Convite para Workspace</h1>
+                <p>Olá! Você foi convidado para cuidar junto do workspace abaixo:</p>
+                <div class="code">%s</div>
+                <p>Entre no Botany com este email para aceitar o convite. Se não esperava por ele, ignore este email.</p>
+                <p>Atenciosamente,equipe Botany!</p>
+            </div>
+        </body>
+        </html>
+    `, html.EscapeString(workspaceName))
+
+	message := gomail.NewMessage()
+	message.SetHeader("From", e.user)
+	message.SetHeader("To", inputEmail)
+	message.SetHeader("Subject", "Convite para Workspace")
+	message.SetBody("text/html", htmlCorpo)
+	dialer := gomail.NewDialer(e.host, e.port, e.user, e.password)
+
+	if err := dialer.DialAndSend(message); err != nil {
+		log.Println("Error:", err)
+		return err
+	}
+	log.Println("Workspace invitation sent successfully!")
+	return nil
+}
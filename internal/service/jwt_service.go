@@ -10,23 +10,30 @@ import (
 
 type JWTService interface {
 	GenerateToken(userID interface{}) (string, error)
+	GenerateTokenWithTTL(userID interface{}, ttl time.Duration) (string, error)
 	ValidateToken(tokenString string) (*jwt.Token, error)
 }
 
 type JWTServiceImpl struct {
 	secretKey string
+	ttl       time.Duration
 }
 
-func NewJWTService(secretKey string) *JWTServiceImpl {
+func NewJWTService(secretKey string, ttl time.Duration) *JWTServiceImpl {
 	return &JWTServiceImpl{
 		secretKey: secretKey,
+		ttl:       ttl,
 	}
 }
 
 func (j *JWTServiceImpl) GenerateToken(userID interface{}) (string, error) {
+	return j.GenerateTokenWithTTL(userID, j.ttl)
+}
+
+func (j *JWTServiceImpl) GenerateTokenWithTTL(userID interface{}, ttl time.Duration) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 72).Unix(),
+		"exp":     time.Now().Add(ttl).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(j.secretKey))
@@ -0,0 +1,73 @@
+package usecases_care
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+const (
+	DefaultDueWithin = 7 * 24 * time.Hour
+	MaxDueWithin     = 90 * 24 * time.Hour
+)
+
+var ErrInvalidWithin = errors.New("o período deve estar entre 0 e 90 dias")
+
+type FindDueCareUseCase struct {
+	Repository entities.CareRepository
+}
+
+type FindDueCareUseCaseInputDTO struct {
+	UserId string        `json:"user_id"`
+	Within time.Duration `json:"within"`
+}
+
+// FindDueCareUseCaseOutputDTO separa o que está atrasado, do mais atrasado para o
+// menos, do que vence dentro do período, do mais próximo para o mais distante.
+type FindDueCareUseCaseOutputDTO struct {
+	Overdue  []*entities.CareItem `json:"overdue"`
+	Upcoming []*entities.CareItem `json:"upcoming"`
+}
+
+func NewFindDueCareUseCase(repository entities.CareRepository) *FindDueCareUseCase {
+	return &FindDueCareUseCase{Repository: repository}
+}
+
+func (u *FindDueCareUseCase) Execute(ctx context.Context, input FindDueCareUseCaseInputDTO) (*FindDueCareUseCaseOutputDTO, error) {
+	if input.Within < 0 || input.Within > MaxDueWithin {
+		return nil, ErrInvalidWithin
+	}
+
+	subjects, err := u.Repository.FindSubjects(ctx, input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	limit := now.Add(input.Within)
+	output := &FindDueCareUseCaseOutputDTO{
+		Overdue:  make([]*entities.CareItem, 0),
+		Upcoming: make([]*entities.CareItem, 0),
+	}
+	for _, subject := range subjects {
+		for _, item := range subject.Schedule(now) {
+			switch {
+			case item.Overdue:
+				output.Overdue = append(output.Overdue, item)
+			case !item.NextDue.After(limit):
+				output.Upcoming = append(output.Upcoming, item)
+			}
+		}
+	}
+
+	sort.SliceStable(output.Overdue, func(i, j int) bool {
+		return output.Overdue[i].NextDue.Before(output.Overdue[j].NextDue)
+	})
+	sort.SliceStable(output.Upcoming, func(i, j int) bool {
+		return output.Upcoming[i].NextDue.Before(output.Upcoming[j].NextDue)
+	})
+	return output, nil
+}
@@ -11,6 +11,8 @@ import (
 type CreateCategoryPlantInputDTO struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// vazio grava no workspace pessoal
+	WorkspaceId string `json:"workspace_id"`
 }
 
 type CreateCategoryPlantUseCase struct {
@@ -30,6 +32,8 @@ func (uc *CreateCategoryPlantUseCase) Execute(ctx context.Context, input CreateC
 		return nil, errors.Wrap(err, "error creating new category plant")
 	}
 
+	newCategoryPlant.WorkspaceId = input.WorkspaceId
+
 	existingCategoryPlant, err := uc.categoryPlantRepository.FindByName(ctx, userId, input.Name)
 	if err != nil {
 		return nil, errors.Wrap(err, "error finding category plant by name")
@@ -11,6 +11,8 @@ import (
 type CreateCategoryTaskInputDTO struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// vazio grava no workspace pessoal
+	WorkspaceId string `json:"workspace_id"`
 }
 
 type CreateCategoryTaskUseCase struct {
@@ -30,6 +32,8 @@ func (uc *CreateCategoryTaskUseCase) Execute(ctx context.Context, input CreateCa
 		return nil, errors.Wrap(err, "error creating new category Task")
 	}
 
+	newCategoryTask.WorkspaceId = input.WorkspaceId
+
 	existingCategoryTask, err := uc.categoryTaskRepository.FindByName(ctx, userId, input.Name)
 	if err != nil {
 		return nil, errors.Wrap(err, "error finding category Task by name")
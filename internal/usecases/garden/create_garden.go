@@ -23,6 +23,8 @@ type CreateGardenUseCaseInputDTO struct {
 	FertilizationWeek int       `json:"fertilization_week"`
 	CategoriesPlantId []string  `json:"categories_plant"`
 	PlantsId          []string  `json:"plants_id"`
+	// vazio grava no workspace pessoal
+	WorkspaceID string `json:"workspace_id"`
 }
 
 type CreateGardenUseCase struct {
@@ -55,6 +57,8 @@ func (uc *CreateGardenUseCase) Execute(ctx context.Context, input CreateGardenUs
 	if err != nil {
 		return nil, err
 	}
+	garden.UserId = input.UserID
+	garden.WorkspaceId = input.WorkspaceID
 
 	id, err := uc.Repository.Create(ctx, garden)
 	if err != nil {
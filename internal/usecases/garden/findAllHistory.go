@@ -7,7 +7,9 @@ import (
 )
 
 type FindAllHistoryGardenUseCaseInputDTO struct {
-	GardenId string `json:"garden_id"`
+	GardenId string               `json:"garden_id"`
+	UserID   string               `json:"user_id"`
+	Page     entities.PageRequest `json:"-"`
 }
 
 type FindAllHistoryGardenUseCase struct {
@@ -20,8 +22,8 @@ func NewFindAllHistoryGardenUseCase(gardenRepository entities.GardenRepository)
 	}
 }
 
-func (u *FindAllHistoryGardenUseCase) Execute(ctx context.Context, input FindAllHistoryGardenUseCaseInputDTO) ([]*entities.HistoryGarden, error) {
-	historyGardens, err := u.GardenRepo.FindAllHistoryByGardenID(ctx, input.GardenId)
+func (u *FindAllHistoryGardenUseCase) Execute(ctx context.Context, input FindAllHistoryGardenUseCaseInputDTO) (*entities.Page[*entities.HistoryGarden], error) {
+	historyGardens, err := u.GardenRepo.FindAllHistoryByGardenID(ctx, input.UserID, input.GardenId, input.Page)
 	if err != nil {
 		return nil, err
 	}
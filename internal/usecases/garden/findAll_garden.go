@@ -7,7 +7,8 @@ import (
 )
 
 type FindAllGardenUseCaseInputDTO struct {
-	UserId string `json:"user_id"`
+	UserId string               `json:"user_id"`
+	Page   entities.PageRequest `json:"-"`
 }
 
 type FindAllGardenUseCase struct {
@@ -18,8 +19,8 @@ func NewFindAllGardenUseCase(findAllGardenRepository entities.GardenRepository)
 	return &FindAllGardenUseCase{FindAllGardenRepository: findAllGardenRepository}
 }
 
-func (useCase *FindAllGardenUseCase) Execute(ctx context.Context, input FindAllGardenUseCaseInputDTO) ([]*entities.GardenOutputDTO, error) {
-	gardens, err := useCase.FindAllGardenRepository.FindAll(ctx, input.UserId)
+func (useCase *FindAllGardenUseCase) Execute(ctx context.Context, input FindAllGardenUseCaseInputDTO) (*entities.Page[*entities.GardenOutputDTO], error) {
+	gardens, err := useCase.FindAllGardenRepository.FindAll(ctx, input.UserId, input.Page)
 	if err != nil {
 		return nil, err
 	}
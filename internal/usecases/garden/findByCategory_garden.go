@@ -12,8 +12,9 @@ type FindByCategoryNameGardenUseCase struct {
 }
 
 type FindByCategoryNameGardenUseCaseInputDTO struct {
-	UserId       string `json:"user_id"`
-	CategoryName string `json:"category_name"`
+	UserId       string               `json:"user_id"`
+	CategoryName string               `json:"category_name"`
+	Page         entities.PageRequest `json:"-"`
 }
 
 func NewFindByCategoryNameGardenUseCase(repository entities.GardenRepository) *FindByCategoryNameGardenUseCase {
@@ -22,9 +23,9 @@ func NewFindByCategoryNameGardenUseCase(repository entities.GardenRepository) *F
 	}
 }
 
-func (uc *FindByCategoryNameGardenUseCase) Execute(ctx context.Context, input FindByCategoryNameGardenUseCaseInputDTO) ([]*entities.GardenOutputDTO, error) {
+func (uc *FindByCategoryNameGardenUseCase) Execute(ctx context.Context, input FindByCategoryNameGardenUseCaseInputDTO) (*entities.Page[*entities.GardenOutputDTO], error) {
 	log.Println("FindByCategoryNameUseCase - Execute")
-	Gardens, err := uc.GardenRepository.FindByCategoryName(ctx, input.UserId, input.CategoryName)
+	Gardens, err := uc.GardenRepository.FindByCategoryName(ctx, input.UserId, input.CategoryName, input.Page)
 	if err != nil {
 		return nil, err
 	}
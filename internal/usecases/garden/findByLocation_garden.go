@@ -11,16 +11,17 @@ type FindByLocationGardenUseCase struct {
 }
 
 type FindByLocationGardenUseCaseInputDTO struct {
-	Location string `json:"location"`
-	UserId   string `json:"userId"`
+	Location string               `json:"location"`
+	UserId   string               `json:"userId"`
+	Page     entities.PageRequest `json:"-"`
 }
 
 func NewFindByLocationGardenUseCase(repository entities.GardenRepository) *FindByLocationGardenUseCase {
 	return &FindByLocationGardenUseCase{Repository: repository}
 }
 
-func (u *FindByLocationGardenUseCase) Execute(ctx context.Context, input FindByLocationGardenUseCaseInputDTO) ([]*entities.GardenOutputDTO, error) {
-	garden, err := u.Repository.FindByLocation(ctx, input.UserId, input.Location)
+func (u *FindByLocationGardenUseCase) Execute(ctx context.Context, input FindByLocationGardenUseCaseInputDTO) (*entities.Page[*entities.GardenOutputDTO], error) {
+	garden, err := u.Repository.FindByLocation(ctx, input.UserId, input.Location, input.Page)
 	if err != nil {
 		return nil, err
 	}
@@ -11,16 +11,17 @@ type FindByNameGardenUseCase struct {
 }
 
 type FindByNameGardenUseCaseInputDTO struct {
-	Name   string `json:"name"`
-	UserId string `json:"userId"`
+	Name   string               `json:"name"`
+	UserId string               `json:"userId"`
+	Page   entities.PageRequest `json:"-"`
 }
 
 func NewFindByNameGardenUseCase(repository entities.GardenRepository) *FindByNameGardenUseCase {
 	return &FindByNameGardenUseCase{Repository: repository}
 }
 
-func (u *FindByNameGardenUseCase) Execute(ctx context.Context, input FindByNameGardenUseCaseInputDTO) ([]*entities.GardenOutputDTO, error) {
-	garden, err := u.Repository.FindByName(ctx, input.UserId, input.Name)
+func (u *FindByNameGardenUseCase) Execute(ctx context.Context, input FindByNameGardenUseCaseInputDTO) (*entities.Page[*entities.GardenOutputDTO], error) {
+	garden, err := u.Repository.FindByName(ctx, input.UserId, input.Name, input.Page)
 	if err != nil {
 		return nil, err
 	}
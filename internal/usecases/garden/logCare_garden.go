@@ -0,0 +1,55 @@
+package usecases_garden
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type LogCareGardenUseCase struct {
+	GardenRepository entities.GardenRepository
+}
+
+type LogCareGardenUseCaseInputDTO struct {
+	Ids         []string   `json:"ids"`
+	UserID      string     `json:"user_id"`
+	Type        string     `json:"type"`
+	Amount      *float64   `json:"amount"`
+	Height      *float64   `json:"height"`
+	Width       *float64   `json:"width"`
+	Notes       string     `json:"notes"`
+	PerformedAt *time.Time `json:"performed_at"`
+}
+
+type LogCareGardenUseCaseOutputDTO struct {
+	Ids         []string  `json:"ids"`
+	Type        string    `json:"type"`
+	PerformedAt time.Time `json:"performed_at"`
+}
+
+func NewLogCareGardenUseCase(repository entities.GardenRepository) *LogCareGardenUseCase {
+	return &LogCareGardenUseCase{
+		GardenRepository: repository,
+	}
+}
+
+func (uc *LogCareGardenUseCase) Execute(ctx context.Context, input LogCareGardenUseCaseInputDTO) (*LogCareGardenUseCaseOutputDTO, error) {
+	ids, err := entities.CareLogIds(input.Ids)
+	if err != nil {
+		return nil, err
+	}
+	care, err := entities.NewCareLog(input.Type, input.UserID, input.Notes, input.PerformedAt, input.Amount, input.Height, input.Width)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.GardenRepository.LogCare(ctx, care, ids); err != nil {
+		return nil, err
+	}
+	return &LogCareGardenUseCaseOutputDTO{
+		Ids:         ids,
+		Type:        care.CareType,
+		PerformedAt: care.PerformedAt,
+	}, nil
+}
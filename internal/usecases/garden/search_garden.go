@@ -0,0 +1,30 @@
+package usecases_garden
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type SearchGardenUseCase struct {
+	Repository entities.GardenRepository
+}
+
+type SearchGardenUseCaseInputDTO struct {
+	UserId string                `json:"user_id"`
+	Filter entities.GardenFilter `json:"-"`
+	Page   entities.PageRequest  `json:"-"`
+}
+
+func NewSearchGardenUseCase(repository entities.GardenRepository) *SearchGardenUseCase {
+	return &SearchGardenUseCase{Repository: repository}
+}
+
+func (u *SearchGardenUseCase) Execute(ctx context.Context, input SearchGardenUseCaseInputDTO) (*entities.Page[*entities.GardenOutputDTO], error) {
+	result, err := u.Repository.Search(ctx, input.UserId, input.Filter, input.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package usecases_garden
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+var ErrMissingAutoTasks = errors.New("auto_tasks é obrigatório")
+
+type SetAutoTasksGardenUseCase struct {
+	GardenRepository entities.GardenRepository
+}
+
+type SetAutoTasksGardenUseCaseInputDTO struct {
+	Id        string `json:"id"`
+	UserID    string `json:"user_id"`
+	AutoTasks *bool  `json:"auto_tasks"`
+}
+
+func NewSetAutoTasksGardenUseCase(repository entities.GardenRepository) *SetAutoTasksGardenUseCase {
+	return &SetAutoTasksGardenUseCase{
+		GardenRepository: repository,
+	}
+}
+
+func (uc *SetAutoTasksGardenUseCase) Execute(ctx context.Context, input SetAutoTasksGardenUseCaseInputDTO) (*entities.GardenOutputDTO, error) {
+	if input.AutoTasks == nil {
+		return nil, ErrMissingAutoTasks
+	}
+
+	garden, err := uc.GardenRepository.FindByID(ctx, input.UserID, input.Id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar jardim: %w", err)
+	}
+	if garden == nil {
+		return nil, errors.New("jardim não encontrado")
+	}
+
+	if err := uc.GardenRepository.SetAutoTasks(ctx, input.UserID, input.Id, *input.AutoTasks); err != nil {
+		return nil, err
+	}
+	return uc.GardenRepository.FindByID(ctx, input.UserID, input.Id)
+}
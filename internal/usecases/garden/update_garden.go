@@ -30,20 +30,27 @@ type UpdateGardenUseCaseInputDTO struct {
 	IrrigationHistory    bool      `json:"irrigation_history"`
 	FertilizationHistory bool      `json:"fertilization_history"`
 	HealthStatus         string    `json:"health_status"`
+	Version              int       `json:"version"`
 }
 
 type UpdateGardenUseCase struct {
 	Repository entities.GardenRepository
+	Uow        entities.UnitOfWork
 }
 
-func NewUpdateGardenUseCase(repository entities.GardenRepository) *UpdateGardenUseCase {
+func NewUpdateGardenUseCase(repository entities.GardenRepository, uow entities.UnitOfWork) *UpdateGardenUseCase {
 	return &UpdateGardenUseCase{
 		Repository: repository,
+		Uow:        uow,
 	}
 }
 
 func (uc *UpdateGardenUseCase) Execute(ctx context.Context, input UpdateGardenUseCaseInputDTO) (*entities.GardenOutputDTO, error) {
-	existingGarden, err := uc.Repository.FindByID(ctx, input.UserID, input.GardenName)
+	if input.Version == 0 {
+		return nil, entities.ErrVersionRequired
+	}
+
+	existingGarden, err := uc.Repository.FindByID(ctx, input.UserID, input.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -51,15 +58,12 @@ func (uc *UpdateGardenUseCase) Execute(ctx context.Context, input UpdateGardenUs
 		return nil, errors.New("jardim não encontrado")
 	}
 
-	gardenName, err := uc.Repository.FindByName(ctx, input.UserID, input.GardenName)
+	nameInUse, err := uc.Repository.NameInUse(ctx, input.UserID, input.GardenName, input.ID)
 	if err != nil {
 		return nil, err
 	}
-
-	for _, garden := range gardenName {
-		if garden.Id != input.ID {
-			return nil, errors.New("nome do jardim já está em uso")
-		}
+	if nameInUse {
+		return nil, errors.New("nome do jardim já está em uso")
 	}
 
 	updatedFields := make(map[string]interface{})
@@ -128,11 +132,13 @@ func (uc *UpdateGardenUseCase) Execute(ctx context.Context, input UpdateGardenUs
 		PlantsId:          input.PlantsId,
 		UpdatedAt:         time.Now(),
 		UserId:            input.UserID,
+		Version:           input.Version,
 	}
 
 	historyGarden := &entities.HistoryGarden{
 		ID:                uuid.New().String(),
 		GardenID:          input.ID,
+		UserID:            input.UserID,
 		GardenLocation:    input.GardenLocation,
 		TotalArea:         input.TotalArea,
 		Height:            input.CurrentingHeight,
@@ -147,14 +153,25 @@ func (uc *UpdateGardenUseCase) Execute(ctx context.Context, input UpdateGardenUs
 		HealthStatus:      input.HealthStatus,
 	}
 
-	if err := uc.Repository.CreateHistory(ctx, historyGarden); err != nil {
-		return nil, err
+	err = uc.Uow.Do(ctx, func(ctx context.Context) error {
+		if err := uc.Repository.CreateHistory(ctx, historyGarden); err != nil {
+			return err
+		}
+		return uc.Repository.Update(ctx, updatedGarden)
+	})
+	if errors.Is(err, entities.ErrVersionConflict) {
+		// devolve o estado atual para o cliente mesclar com o que enviou
+		current, findErr := uc.Repository.FindByID(ctx, input.UserID, input.ID)
+		if findErr != nil {
+			return nil, findErr
+		}
+		return current, entities.ErrVersionConflict
 	}
-	if err := uc.Repository.Update(ctx, updatedGarden); err != nil {
+	if err != nil {
 		return nil, err
 	}
 
-	garden, err := uc.Repository.FindByID(ctx, input.UserID, input.GardenName)
+	garden, err := uc.Repository.FindByID(ctx, input.UserID, input.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,48 @@
+package usecases_harvest
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type CreateHarvestUseCase struct {
+	Repository entities.HarvestRepository
+}
+
+// CreateHarvestUseCaseInputDTO recebe a colheita; PlantStatus, quando informado, passa
+// a ser o status da planta (por exemplo "Colhida").
+type CreateHarvestUseCaseInputDTO struct {
+	UserId       string     `json:"user_id"`
+	PlantId      string     `json:"plant_id"`
+	GardenId     string     `json:"garden_id"`
+	HarvestDate  *time.Time `json:"harvest_date"`
+	Quantity     float64    `json:"quantity"`
+	Unit         string     `json:"unit"`
+	QualityGrade string     `json:"quality_grade"`
+	Notes        string     `json:"notes"`
+	PlantStatus  string     `json:"plant_status"`
+}
+
+func NewCreateHarvestUseCase(repository entities.HarvestRepository) *CreateHarvestUseCase {
+	return &CreateHarvestUseCase{Repository: repository}
+}
+
+func (u *CreateHarvestUseCase) Execute(ctx context.Context, input CreateHarvestUseCaseInputDTO) (*entities.Harvest, error) {
+	harvest, err := entities.NewHarvest(input.PlantId, input.GardenId, input.UserId, input.Unit, input.QualityGrade,
+		input.Notes, input.HarvestDate, input.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	// plant_status é VARCHAR(50) na tabela de plantas
+	if utf8.RuneCountInString(input.PlantStatus) > 50 {
+		return nil, entities.ErrInvalidPlantStatus
+	}
+
+	if err := u.Repository.Create(ctx, harvest, input.PlantStatus); err != nil {
+		return nil, err
+	}
+	return harvest, nil
+}
@@ -0,0 +1,30 @@
+package usecases_harvest
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+var ErrMissingId = errors.New("id é obrigatório")
+
+type DeleteHarvestUseCase struct {
+	Repository entities.HarvestRepository
+}
+
+type DeleteHarvestUseCaseInputDTO struct {
+	UserId string `json:"user_id"`
+	Id     string `json:"id"`
+}
+
+func NewDeleteHarvestUseCase(repository entities.HarvestRepository) *DeleteHarvestUseCase {
+	return &DeleteHarvestUseCase{Repository: repository}
+}
+
+func (u *DeleteHarvestUseCase) Execute(ctx context.Context, input DeleteHarvestUseCaseInputDTO) error {
+	if input.Id == "" {
+		return ErrMissingId
+	}
+	return u.Repository.Delete(ctx, input.UserId, input.Id)
+}
@@ -0,0 +1,25 @@
+package usecases_harvest
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type FindAllHarvestUseCase struct {
+	Repository entities.HarvestRepository
+}
+
+type FindAllHarvestUseCaseInputDTO struct {
+	UserId string
+	Filter entities.HarvestFilter
+	Page   entities.PageRequest
+}
+
+func NewFindAllHarvestUseCase(repository entities.HarvestRepository) *FindAllHarvestUseCase {
+	return &FindAllHarvestUseCase{Repository: repository}
+}
+
+func (u *FindAllHarvestUseCase) Execute(ctx context.Context, input FindAllHarvestUseCaseInputDTO) (*entities.Page[*entities.Harvest], error) {
+	return u.Repository.FindAll(ctx, input.UserId, input.Filter, input.Page)
+}
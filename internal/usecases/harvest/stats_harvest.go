@@ -0,0 +1,32 @@
+package usecases_harvest
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type StatsHarvestUseCase struct {
+	Repository entities.HarvestRepository
+}
+
+type StatsHarvestUseCaseInputDTO struct {
+	UserId string
+	Query  entities.HarvestStatsQuery
+}
+
+func NewStatsHarvestUseCase(repository entities.HarvestRepository) *StatsHarvestUseCase {
+	return &StatsHarvestUseCase{Repository: repository}
+}
+
+// Execute soma o rendimento e a precisão das estimativas por grupo; sem agrupamento,
+// agrupa por planta.
+func (u *StatsHarvestUseCase) Execute(ctx context.Context, input StatsHarvestUseCaseInputDTO) ([]*entities.HarvestStats, error) {
+	if input.Query.GroupBy == "" {
+		input.Query.GroupBy = entities.HarvestGroupPlant
+	}
+	if !entities.IsHarvestGroup(input.Query.GroupBy) {
+		return nil, entities.ErrInvalidHarvestGroup
+	}
+	return u.Repository.Stats(ctx, input.UserId, input.Query)
+}
@@ -30,7 +30,8 @@ type CreatePlantUseCaseInputDTO struct {
 	UserID               string    `json:"user_id"`
 	SpeciesID            string    `json:"species_id"`
 	CategoriesPlant      []string  `json:"categories_plant"`
-	SpecieHaverstTime    int       `json:"specie_harvest_time"`
+	// vazio grava no workspace pessoal
+	WorkspaceID string `json:"workspace_id"`
 }
 
 func NewCreatePlantUseCase(plantRepository entities.PlantRepository, specieRepository entities.SpecieRepository) *CreatePlantUseCase {
@@ -41,9 +42,9 @@ func NewCreatePlantUseCase(plantRepository entities.PlantRepository, specieRepos
 }
 
 func CalculateEstimatedHarvestDate(plantingDate, estimatedHarvestDate time.Time, harvestTime int) time.Time {
-	// Se a data estimada de colheita já existir, ajusta com base no HarvestTime
+	// A data informada pelo cliente prevalece sobre o ciclo da espécie
 	if !estimatedHarvestDate.IsZero() {
-		return estimatedHarvestDate.AddDate(0, 0, harvestTime)
+		return estimatedHarvestDate
 	}
 	// Caso contrário, calcula a partir da data de plantio
 	return plantingDate.AddDate(0, 0, harvestTime)
@@ -74,8 +75,23 @@ func (uc *CreatePlantUseCase) Execute(ctx context.Context, input CreatePlantUseC
 		return nil, err
 	}
 
-	estimatedHarvestDate := CalculateEstimatedHarvestDate(newPlant.PlantingDate, newPlant.EstimatedHarvestDate, input.SpecieHaverstTime)
-	newPlant.EstimatedHarvestDate = estimatedHarvestDate
+	specie, err := findSpecie(ctx, uc.SpecieRepository, newPlant.SpeciesId)
+	if err != nil {
+		return nil, err
+	}
+	// NewPlant preenche a data vazia, então a escolha do cliente vem do input
+	if input.EstimatedHarvestDate.IsZero() {
+		newPlant.EstimatedHarvestDate, err = predictHarvestDate(&entities.PlantWithCategory{
+			SpeciesId:     newPlant.SpeciesId,
+			PlantingDate:  newPlant.PlantingDate,
+			CurrentHeight: newPlant.CurrentHeight,
+			CurrentWidth:  newPlant.CurrentWidth,
+		}, specie, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	newPlant.WorkspaceId = input.WorkspaceID
 	id, err := uc.PlantRepository.Create(ctx, newPlant)
 	if err != nil {
 		return nil, err
@@ -11,7 +11,9 @@ type FindAllHistoryPlantUseCase struct {
 }
 
 type FindAllHistoryPlantUseCaseInputDTO struct {
-	PlantId string `json:"plant_id"`
+	PlantId string               `json:"plant_id"`
+	UserID  string               `json:"user_id"`
+	Page    entities.PageRequest `json:"-"`
 }
 
 func NewFindAllHistoryPlantUseCase(plantRepository entities.PlantRepository) *FindAllHistoryPlantUseCase {
@@ -20,8 +22,8 @@ func NewFindAllHistoryPlantUseCase(plantRepository entities.PlantRepository) *Fi
 	}
 }
 
-func (u *FindAllHistoryPlantUseCase) Execute(ctx context.Context, input FindAllHistoryPlantUseCaseInputDTO) ([]*entities.HistoryPlant, error) {
-	historyPlants, err := u.PlantRepo.FindAllHistoryByPlantID(ctx, input.PlantId)
+func (u *FindAllHistoryPlantUseCase) Execute(ctx context.Context, input FindAllHistoryPlantUseCaseInputDTO) (*entities.Page[*entities.HistoryPlant], error) {
+	historyPlants, err := u.PlantRepo.FindAllHistoryByPlantID(ctx, input.UserID, input.PlantId, input.Page)
 	if err != nil {
 		return nil, err
 	}
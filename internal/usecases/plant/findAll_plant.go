@@ -12,7 +12,8 @@ type FindAllPlantUseCase struct {
 }
 
 type FindAllPlantUseCaseInputDTO struct {
-	UserId string `json:"userId"`
+	UserId string               `json:"userId"`
+	Page   entities.PageRequest `json:"-"`
 }
 
 func NewFindAllPlantUseCase(repository entities.PlantRepository) *FindAllPlantUseCase {
@@ -21,9 +22,9 @@ func NewFindAllPlantUseCase(repository entities.PlantRepository) *FindAllPlantUs
 	}
 }
 
-func (uc *FindAllPlantUseCase) Execute(ctx context.Context, input FindAllPlantUseCaseInputDTO) ([]*entities.PlantWithCategory, error) {
+func (uc *FindAllPlantUseCase) Execute(ctx context.Context, input FindAllPlantUseCaseInputDTO) (*entities.Page[*entities.PlantWithCategory], error) {
 	log.Println("FindAllPlantUseCase - Execute")
-	plants, err := uc.PlantRepository.FindAll(ctx, input.UserId)
+	plants, err := uc.PlantRepository.FindAll(ctx, input.UserId, input.Page)
 	if err != nil {
 		return nil, err
 	}
@@ -12,8 +12,9 @@ type FindByCategoryNamePlantUseCase struct {
 }
 
 type FindByCategoryNamePlantUseCaseInputDTO struct {
-	UserId       string `json:"user_id"`
-	CategoryName string `json:"category_name"`
+	UserId       string               `json:"user_id"`
+	CategoryName string               `json:"category_name"`
+	Page         entities.PageRequest `json:"-"`
 }
 
 func NewFindByCategoryNamePlantUseCase(repository entities.PlantRepository) *FindByCategoryNamePlantUseCase {
@@ -22,9 +23,9 @@ func NewFindByCategoryNamePlantUseCase(repository entities.PlantRepository) *Fin
 	}
 }
 
-func (uc *FindByCategoryNamePlantUseCase) Execute(ctx context.Context, input FindByCategoryNamePlantUseCaseInputDTO) ([]*entities.PlantWithCategory, error) {
+func (uc *FindByCategoryNamePlantUseCase) Execute(ctx context.Context, input FindByCategoryNamePlantUseCaseInputDTO) (*entities.Page[*entities.PlantWithCategory], error) {
 	log.Println("FindByCategoryNameUseCase - Execute")
-	plants, err := uc.PlantRepository.FindByCategoryName(ctx, input.UserId, input.CategoryName)
+	plants, err := uc.PlantRepository.FindByCategoryName(ctx, input.UserId, input.CategoryName, input.Page)
 	if err != nil {
 		return nil, err
 	}
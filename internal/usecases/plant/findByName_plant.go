@@ -13,8 +13,9 @@ type FindByNamePlantUseCase struct {
 }
 
 type FindByNamePlantUseCaseInputDTO struct {
-	Name   string `json:"name"`
-	UserId string `json:"user_id"`
+	Name   string               `json:"name"`
+	UserId string               `json:"user_id"`
+	Page   entities.PageRequest `json:"-"`
 }
 
 func NewFindByNameCategoryPlantUseCase(repository entities.PlantRepository) *FindByNamePlantUseCase {
@@ -23,12 +24,12 @@ func NewFindByNameCategoryPlantUseCase(repository entities.PlantRepository) *Fin
 	}
 }
 
-func (uc *FindByNamePlantUseCase) Execute(ctx context.Context, input FindByNamePlantUseCaseInputDTO) ([]*entities.PlantWithCategory, error) {
+func (uc *FindByNamePlantUseCase) Execute(ctx context.Context, input FindByNamePlantUseCaseInputDTO) (*entities.Page[*entities.PlantWithCategory], error) {
 	log.Println("FindByNamePlant - Execute")
 	if input.Name == "" {
 		return nil, errors.New("name is required")
 	}
-	plants, err := uc.PlantRepository.FindByName(ctx, input.UserId, input.Name)
+	plants, err := uc.PlantRepository.FindByName(ctx, input.UserId, input.Name, input.Page)
 	if err != nil {
 		return nil, err
 	}
@@ -12,8 +12,9 @@ type FindBySpecieNamePlantUseCase struct {
 }
 
 type FindBySpecieNamePlantUseCaseInputDTO struct {
-	UserId     string `json:"user_id"`
-	SpecieName string `json:"category_name"`
+	UserId     string               `json:"user_id"`
+	SpecieName string               `json:"category_name"`
+	Page       entities.PageRequest `json:"-"`
 }
 
 func NewFindBySpecieNamePlantUseCase(repository entities.PlantRepository) *FindBySpecieNamePlantUseCase {
@@ -22,9 +23,9 @@ func NewFindBySpecieNamePlantUseCase(repository entities.PlantRepository) *FindB
 	}
 }
 
-func (uc *FindBySpecieNamePlantUseCase) Execute(ctx context.Context, input FindBySpecieNamePlantUseCaseInputDTO) ([]*entities.PlantWithCategory, error) {
+func (uc *FindBySpecieNamePlantUseCase) Execute(ctx context.Context, input FindBySpecieNamePlantUseCaseInputDTO) (*entities.Page[*entities.PlantWithCategory], error) {
 	log.Println("FindBySpecieNamePlantUseCase - Execute")
-	plants, err := uc.PlantRepository.FindBySpeciesName(ctx, input.UserId, input.SpecieName)
+	plants, err := uc.PlantRepository.FindBySpeciesName(ctx, input.UserId, input.SpecieName, input.Page)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,47 @@
+package usecases_plant
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type FindGrowthPlantUseCase struct {
+	PlantRepository  entities.PlantRepository
+	SpecieRepository entities.SpecieRepository
+}
+
+type FindGrowthPlantUseCaseInputDTO struct {
+	Id     string `json:"id"`
+	UserID string `json:"user_id"`
+}
+
+func NewFindGrowthPlantUseCase(plantRepository entities.PlantRepository, specieRepository entities.SpecieRepository) *FindGrowthPlantUseCase {
+	return &FindGrowthPlantUseCase{
+		PlantRepository:  plantRepository,
+		SpecieRepository: specieRepository,
+	}
+}
+
+func (uc *FindGrowthPlantUseCase) Execute(ctx context.Context, input FindGrowthPlantUseCaseInputDTO) (*entities.GrowthAnalytics, error) {
+	// a busca da planta também confere se o usuário pode vê-la
+	plant, err := uc.PlantRepository.FindByID(ctx, input.UserID, input.Id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar planta: %w", err)
+	}
+	if plant == nil {
+		return nil, entities.ErrPlantNotFound
+	}
+
+	specie, err := uc.SpecieRepository.FindById(ctx, plant.SpeciesId)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar espécie: %w", err)
+	}
+	samples, err := uc.PlantRepository.FindGrowthSamples(ctx, plant.PlantId)
+	if err != nil {
+		return nil, err
+	}
+	return entities.AnalyzeGrowth(plant, specie, samples, time.Now()), nil
+}
@@ -0,0 +1,55 @@
+package usecases_plant
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type LogCarePlantUseCase struct {
+	PlantRepository entities.PlantRepository
+}
+
+type LogCarePlantUseCaseInputDTO struct {
+	Ids         []string   `json:"ids"`
+	UserID      string     `json:"user_id"`
+	Type        string     `json:"type"`
+	Amount      *float64   `json:"amount"`
+	Height      *float64   `json:"height"`
+	Width       *float64   `json:"width"`
+	Notes       string     `json:"notes"`
+	PerformedAt *time.Time `json:"performed_at"`
+}
+
+type LogCarePlantUseCaseOutputDTO struct {
+	Ids         []string  `json:"ids"`
+	Type        string    `json:"type"`
+	PerformedAt time.Time `json:"performed_at"`
+}
+
+func NewLogCarePlantUseCase(repository entities.PlantRepository) *LogCarePlantUseCase {
+	return &LogCarePlantUseCase{
+		PlantRepository: repository,
+	}
+}
+
+func (uc *LogCarePlantUseCase) Execute(ctx context.Context, input LogCarePlantUseCaseInputDTO) (*LogCarePlantUseCaseOutputDTO, error) {
+	ids, err := entities.CareLogIds(input.Ids)
+	if err != nil {
+		return nil, err
+	}
+	care, err := entities.NewCareLog(input.Type, input.UserID, input.Notes, input.PerformedAt, input.Amount, input.Height, input.Width)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.PlantRepository.LogCare(ctx, care, ids); err != nil {
+		return nil, err
+	}
+	return &LogCarePlantUseCaseOutputDTO{
+		Ids:         ids,
+		Type:        care.CareType,
+		PerformedAt: care.PerformedAt,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package usecases_plant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type PredictHarvestPlantUseCase struct {
+	PlantRepository  entities.PlantRepository
+	SpecieRepository entities.SpecieRepository
+}
+
+type PredictHarvestPlantUseCaseInputDTO struct {
+	Id     string `json:"id"`
+	UserID string `json:"user_id"`
+}
+
+func NewPredictHarvestPlantUseCase(plantRepository entities.PlantRepository, specieRepository entities.SpecieRepository) *PredictHarvestPlantUseCase {
+	return &PredictHarvestPlantUseCase{
+		PlantRepository:  plantRepository,
+		SpecieRepository: specieRepository,
+	}
+}
+
+func (uc *PredictHarvestPlantUseCase) Execute(ctx context.Context, input PredictHarvestPlantUseCaseInputDTO) (*entities.HarvestPrediction, error) {
+	plant, err := uc.PlantRepository.FindByID(ctx, input.UserID, input.Id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar planta: %w", err)
+	}
+	if plant == nil {
+		return nil, entities.ErrPlantNotFound
+	}
+
+	specie, err := findSpecie(ctx, uc.SpecieRepository, plant.SpeciesId)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := uc.PlantRepository.FindGrowthSamples(ctx, plant.PlantId)
+	if err != nil {
+		return nil, err
+	}
+	return entities.PredictHarvest(plant, specie, samples, time.Now())
+}
+
+func findSpecie(ctx context.Context, specieRepository entities.SpecieRepository, id string) (*entities.Specie, error) {
+	specie, err := specieRepository.FindById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar espécie: %w", err)
+	}
+	if specie == nil {
+		return nil, entities.ErrSpecieNotFound
+	}
+	return specie, nil
+}
+
+// predictHarvestDate é a data gravada na planta: o centro da faixa prevista, ou o
+// ciclo da espécie a partir do plantio quando não há como prever.
+func predictHarvestDate(plant *entities.PlantWithCategory, specie *entities.Specie, samples []*entities.GrowthSample) (time.Time, error) {
+	prediction, err := entities.PredictHarvest(plant, specie, samples, time.Now())
+	if errors.Is(err, entities.ErrHarvestPredictionUnavailable) {
+		return CalculateEstimatedHarvestDate(plant.PlantingDate, time.Time{}, specie.HarvestTime), nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return prediction.EstimatedDate, nil
+}
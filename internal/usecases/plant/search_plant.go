@@ -0,0 +1,30 @@
+package usecases_plant
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type SearchPlantUseCase struct {
+	Repository entities.PlantRepository
+}
+
+type SearchPlantUseCaseInputDTO struct {
+	UserId string               `json:"user_id"`
+	Filter entities.PlantFilter `json:"-"`
+	Page   entities.PageRequest `json:"-"`
+}
+
+func NewSearchPlantUseCase(repository entities.PlantRepository) *SearchPlantUseCase {
+	return &SearchPlantUseCase{Repository: repository}
+}
+
+func (u *SearchPlantUseCase) Execute(ctx context.Context, input SearchPlantUseCaseInputDTO) (*entities.Page[*entities.PlantWithCategory], error) {
+	result, err := u.Repository.Search(ctx, input.UserId, input.Filter, input.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package usecases_plant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+var ErrMissingAutoTasks = errors.New("auto_tasks é obrigatório")
+
+type SetAutoTasksPlantUseCase struct {
+	PlantRepository entities.PlantRepository
+}
+
+type SetAutoTasksPlantUseCaseInputDTO struct {
+	Id        string `json:"id"`
+	UserID    string `json:"user_id"`
+	AutoTasks *bool  `json:"auto_tasks"`
+}
+
+func NewSetAutoTasksPlantUseCase(repository entities.PlantRepository) *SetAutoTasksPlantUseCase {
+	return &SetAutoTasksPlantUseCase{
+		PlantRepository: repository,
+	}
+}
+
+func (uc *SetAutoTasksPlantUseCase) Execute(ctx context.Context, input SetAutoTasksPlantUseCaseInputDTO) (*entities.PlantWithCategory, error) {
+	if input.AutoTasks == nil {
+		return nil, ErrMissingAutoTasks
+	}
+
+	plant, err := uc.PlantRepository.FindByID(ctx, input.UserID, input.Id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar planta: %w", err)
+	}
+	if plant == nil {
+		return nil, errors.New("planta não encontrada")
+	}
+
+	if err := uc.PlantRepository.SetAutoTasks(ctx, input.UserID, input.Id, *input.AutoTasks); err != nil {
+		return nil, err
+	}
+	return uc.PlantRepository.FindByID(ctx, input.UserID, input.Id)
+}
@@ -31,19 +31,28 @@ type UpdatePlantUseCaseInputDTO struct {
 	Note                 string    `json:"note"`
 	IrrigationHistory    bool      `json:"irrigation_history"`
 	FertilizationHistory bool      `json:"fertilization_history"`
+	Version              int       `json:"version"`
 }
 
 type UpdatePlantUseCase struct {
-	PlantRepo entities.PlantRepository
+	PlantRepo  entities.PlantRepository
+	SpecieRepo entities.SpecieRepository
+	Uow        entities.UnitOfWork
 }
 
-func NewUpdatePlantUseCase(plantRepository entities.PlantRepository) *UpdatePlantUseCase {
+func NewUpdatePlantUseCase(plantRepository entities.PlantRepository, specieRepository entities.SpecieRepository, uow entities.UnitOfWork) *UpdatePlantUseCase {
 	return &UpdatePlantUseCase{
-		PlantRepo: plantRepository,
+		PlantRepo:  plantRepository,
+		SpecieRepo: specieRepository,
+		Uow:        uow,
 	}
 }
 
 func (u *UpdatePlantUseCase) Execute(ctx context.Context, input UpdatePlantUseCaseInputDTO) (*entities.PlantWithCategory, error) {
+	if input.Version == 0 {
+		return nil, entities.ErrVersionRequired
+	}
+
 	// Verificar se a planta existe
 	existingPlant, err := u.PlantRepo.FindByID(ctx, input.UserID, input.ID)
 	if err != nil {
@@ -54,14 +63,17 @@ func (u *UpdatePlantUseCase) Execute(ctx context.Context, input UpdatePlantUseCa
 	}
 
 	// Verificar se o nome da planta já existe (excluindo a própria planta)
-	plantWithSameName, err := u.PlantRepo.FindByName(ctx, input.UserID, input.PlantName)
+	nameInUse, err := u.PlantRepo.NameInUse(ctx, input.UserID, input.PlantName, input.ID)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao verificar nome da planta: %w", err)
+		return nil, err
 	}
-	for _, plant := range plantWithSameName {
-		if plant.PlantId != input.ID {
-			return nil, errors.New("nome da planta já está em uso")
-		}
+	if nameInUse {
+		return nil, errors.New("nome da planta já está em uso")
+	}
+
+	// sem espécie no corpo, a planta mantém a atual
+	if input.SpeciesID == "" {
+		input.SpeciesID = existingPlant.SpeciesId
 	}
 
 	// Identificar campos alterados
@@ -116,6 +128,37 @@ func (u *UpdatePlantUseCase) Execute(ctx context.Context, input UpdatePlantUseCa
 		updatedFields["CategoriesPlant"] = input.CategoriesPlant
 	}
 
+	// a data estimada acompanha o plantio e a espécie, a menos que o cliente envie outra junto
+	_, plantingChanged := updatedFields["PlantingDate"]
+	_, specieChanged := updatedFields["SpeciesID"]
+	_, harvestChanged := updatedFields["EstimatedHarvestDate"]
+	clientHarvest := harvestChanged && !input.EstimatedHarvestDate.IsZero()
+	recompute := (plantingChanged || specieChanged) && !clientHarvest
+	var specie *entities.Specie
+	// a espécie nova é conferida mesmo quando o cliente fixa a data
+	if specieChanged || recompute {
+		specie, err = findSpecie(ctx, u.SpecieRepo, input.SpeciesID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if recompute {
+		samples, err := u.PlantRepo.FindGrowthSamples(ctx, existingPlant.PlantId)
+		if err != nil {
+			return nil, err
+		}
+		input.EstimatedHarvestDate, err = predictHarvestDate(&entities.PlantWithCategory{
+			PlantId:       existingPlant.PlantId,
+			SpeciesId:     input.SpeciesID,
+			PlantingDate:  input.PlantingDate,
+			CurrentHeight: input.CurrentHeight,
+			CurrentWidth:  input.CurrentWidth,
+		}, specie, samples)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Atualizar planta
 	updatedPlant := &entities.Plant{
 		Id:                   input.ID,
@@ -136,6 +179,7 @@ func (u *UpdatePlantUseCase) Execute(ctx context.Context, input UpdatePlantUseCa
 		SpeciesId:            input.SpeciesID,
 		CategoriesPlant:      input.CategoriesPlant,
 		UpdatedAt:            time.Now(),
+		Version:              input.Version,
 	}
 
 	historyPlant := &entities.HistoryPlant{
@@ -153,11 +197,26 @@ func (u *UpdatePlantUseCase) Execute(ctx context.Context, input UpdatePlantUseCa
 		Irrigation:        input.IrrigationHistory,
 		Fertilization:     input.FertilizationHistory,
 	}
-	if err := u.PlantRepo.CreateHistory(ctx, historyPlant); err != nil {
-		return nil, fmt.Errorf("erro ao criar histórico da planta: %w", err)
+	// histórico e atualização são gravados juntos ou nenhum dos dois
+	err = u.Uow.Do(ctx, func(ctx context.Context) error {
+		if err := u.PlantRepo.CreateHistory(ctx, historyPlant); err != nil {
+			return fmt.Errorf("erro ao criar histórico da planta: %w", err)
+		}
+		if err := u.PlantRepo.Update(ctx, updatedPlant); err != nil {
+			return fmt.Errorf("erro ao atualizar planta: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, entities.ErrVersionConflict) {
+		// devolve o estado atual para o cliente mesclar com o que enviou
+		current, findErr := u.PlantRepo.FindByID(ctx, input.UserID, input.ID)
+		if findErr != nil {
+			return nil, fmt.Errorf("erro ao buscar planta: %w", findErr)
+		}
+		return current, entities.ErrVersionConflict
 	}
-	if err := u.PlantRepo.Update(ctx, updatedPlant); err != nil {
-		return nil, fmt.Errorf("erro ao atualizar planta: %w", err)
+	if err != nil {
+		return nil, err
 	}
 
 	newPlant, err := u.PlantRepo.FindByID(ctx, input.UserID, input.ID)
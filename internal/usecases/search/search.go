@@ -0,0 +1,55 @@
+package usecases_search
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+const (
+	ModeFull         = "full"
+	ModeAutocomplete = "autocomplete"
+
+	defaultSearchLimit       = 20
+	defaultAutocompleteLimit = 10
+	maxSearchLimit           = 50
+)
+
+var ErrEmptyQuery = errors.New("termo de busca é obrigatório")
+
+type SearchUseCase struct {
+	Repository entities.SearchRepository
+}
+
+type SearchUseCaseInputDTO struct {
+	UserId string `json:"user_id"`
+	Query  string `json:"q"`
+	Mode   string `json:"mode"`
+	Limit  int    `json:"limit"`
+}
+
+func NewSearchUseCase(repository entities.SearchRepository) *SearchUseCase {
+	return &SearchUseCase{Repository: repository}
+}
+
+func (u *SearchUseCase) Execute(ctx context.Context, input SearchUseCaseInputDTO) ([]*entities.SearchResult, error) {
+	query := strings.TrimSpace(input.Query)
+	if query == "" {
+		return nil, ErrEmptyQuery
+	}
+
+	limit := input.Limit
+	if input.Mode == ModeAutocomplete {
+		if limit <= 0 {
+			limit = defaultAutocompleteLimit
+		}
+		return u.Repository.Autocomplete(ctx, input.UserId, query, min(limit, maxSearchLimit))
+	}
+
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	return u.Repository.Search(ctx, input.UserId, query, min(limit, maxSearchLimit))
+}
@@ -0,0 +1,334 @@
+package usecases_specie
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+const (
+	CatalogFormatCSV  = "csv"
+	CatalogFormatJSON = "json"
+)
+
+var ErrInvalidCatalogFormat = errors.New("formato inválido: use csv ou json")
+
+// CatalogValidationError reúne todos os problemas do arquivo, para corrigir tudo de uma vez.
+type CatalogValidationError struct {
+	Problems []string
+}
+
+func (e *CatalogValidationError) Error() string {
+	return "catálogo inválido: " + strings.Join(e.Problems, "; ")
+}
+
+// SpecieCatalogItem é a espécie como vai no arquivo do catálogo: sem id nem datas,
+// que mudam de um ambiente para outro. O nome científico identifica a espécie.
+type SpecieCatalogItem struct {
+	CommonName          string  `json:"common_name"`
+	SpecieDescription   string  `json:"specie_description"`
+	ScientificName      string  `json:"scientific_name"`
+	BotanicalFamily     string  `json:"botanical_family"`
+	GrowthType          string  `json:"growth_type"`
+	IdealTemperature    float64 `json:"ideal_temperature"`
+	IdealClimate        string  `json:"ideal_climate"`
+	LifeCycle           string  `json:"life_cycle"`
+	PlantingSeason      string  `json:"planting_season"`
+	HarvestTime         int     `json:"harvest_time"`
+	AverageHeight       float64 `json:"average_height"`
+	AverageWidth        float64 `json:"average_width"`
+	IrrigationWeight    float64 `json:"irrigation_weight"`
+	FertilizationWeight float64 `json:"fertilization_weight"`
+	SunWeight           float64 `json:"sun_weight"`
+	ImageURL            string  `json:"image_url"`
+}
+
+// catalogField liga uma coluna do arquivo ao campo do item e concentra o que o CSV,
+// a validação e o diff precisam saber dela. Os limites seguem as colunas da tabela species.
+type catalogField struct {
+	name      string
+	get       func(*SpecieCatalogItem) string
+	set       func(*SpecieCatalogItem, string) error
+	normalize func(*SpecieCatalogItem)
+	check     func(*SpecieCatalogItem) string
+}
+
+var catalogFields = []catalogField{
+	textField("common_name", 50, func(s *SpecieCatalogItem) *string { return &s.CommonName }),
+	textField("specie_description", 100, func(s *SpecieCatalogItem) *string { return &s.SpecieDescription }),
+	textField("scientific_name", 50, func(s *SpecieCatalogItem) *string { return &s.ScientificName }),
+	textField("botanical_family", 50, func(s *SpecieCatalogItem) *string { return &s.BotanicalFamily }),
+	textField("growth_type", 50, func(s *SpecieCatalogItem) *string { return &s.GrowthType }),
+	floatField("ideal_temperature", -30, 50, -1, func(s *SpecieCatalogItem) *float64 { return &s.IdealTemperature }),
+	textField("ideal_climate", 50, func(s *SpecieCatalogItem) *string { return &s.IdealClimate }),
+	textField("life_cycle", 50, func(s *SpecieCatalogItem) *string { return &s.LifeCycle }),
+	textField("planting_season", 50, func(s *SpecieCatalogItem) *string { return &s.PlantingSeason }),
+	intField("harvest_time", 1, 3650, func(s *SpecieCatalogItem) *int { return &s.HarvestTime }),
+	floatField("average_height", 0.01, 99999999.99, 2, func(s *SpecieCatalogItem) *float64 { return &s.AverageHeight }),
+	floatField("average_width", 0.01, 99999999.99, 2, func(s *SpecieCatalogItem) *float64 { return &s.AverageWidth }),
+	floatField("irrigation_weight", 0, 1, 2, func(s *SpecieCatalogItem) *float64 { return &s.IrrigationWeight }),
+	floatField("fertilization_weight", 0, 1, 2, func(s *SpecieCatalogItem) *float64 { return &s.FertilizationWeight }),
+	floatField("sun_weight", 0, 1, 2, func(s *SpecieCatalogItem) *float64 { return &s.SunWeight }),
+	urlField("image_url", 300, func(s *SpecieCatalogItem) *string { return &s.ImageURL }),
+}
+
+func textField(name string, max int, field func(*SpecieCatalogItem) *string) catalogField {
+	return catalogField{
+		name: name,
+		get:  func(s *SpecieCatalogItem) string { return *field(s) },
+		set: func(s *SpecieCatalogItem, value string) error {
+			*field(s) = value
+			return nil
+		},
+		normalize: func(s *SpecieCatalogItem) { *field(s) = strings.TrimSpace(*field(s)) },
+		check: func(s *SpecieCatalogItem) string {
+			value := *field(s)
+			if value == "" {
+				return "é obrigatório"
+			}
+			if utf8.RuneCountInString(value) > max {
+				return fmt.Sprintf("deve ter até %d caracteres", max)
+			}
+			return ""
+		},
+	}
+}
+
+func urlField(name string, max int, field func(*SpecieCatalogItem) *string) catalogField {
+	text := textField(name, max, field)
+	check := text.check
+	text.check = func(s *SpecieCatalogItem) string {
+		if problem := check(s); problem != "" {
+			return problem
+		}
+		parsed, err := url.Parse(*field(s))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return "deve ser uma URL http ou https"
+		}
+		return ""
+	}
+	return text
+}
+
+// floatField arredonda para scale casas, a escala da coluna NUMERIC, para o diff não
+// acusar diferença que o banco descartaria; scale negativo não arredonda.
+func floatField(name string, min, max float64, scale int, field func(*SpecieCatalogItem) *float64) catalogField {
+	return catalogField{
+		name: name,
+		get:  func(s *SpecieCatalogItem) string { return strconv.FormatFloat(*field(s), 'f', -1, 64) },
+		set: func(s *SpecieCatalogItem, value string) error {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return fmt.Errorf("valor %q não é um número", value)
+			}
+			*field(s) = parsed
+			return nil
+		},
+		normalize: func(s *SpecieCatalogItem) {
+			if scale >= 0 {
+				pow := math.Pow(10, float64(scale))
+				*field(s) = math.Round(*field(s)*pow) / pow
+			}
+		},
+		check: func(s *SpecieCatalogItem) string {
+			value := *field(s)
+			if math.IsNaN(value) || value < min || value > max {
+				return fmt.Sprintf("deve estar entre %s e %s", strconv.FormatFloat(min, 'f', -1, 64), strconv.FormatFloat(max, 'f', -1, 64))
+			}
+			return ""
+		},
+	}
+}
+
+func intField(name string, min, max int, field func(*SpecieCatalogItem) *int) catalogField {
+	return catalogField{
+		name: name,
+		get:  func(s *SpecieCatalogItem) string { return strconv.Itoa(*field(s)) },
+		set: func(s *SpecieCatalogItem, value string) error {
+			parsed, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("valor %q não é um número inteiro", value)
+			}
+			*field(s) = parsed
+			return nil
+		},
+		normalize: func(s *SpecieCatalogItem) {},
+		check: func(s *SpecieCatalogItem) string {
+			if value := *field(s); value < min || value > max {
+				return fmt.Sprintf("deve estar entre %d e %d", min, max)
+			}
+			return ""
+		},
+	}
+}
+
+// validateCatalog normaliza os itens e devolve todos os problemas encontrados,
+// inclusive nomes científicos repetidos no mesmo arquivo.
+func validateCatalog(items []SpecieCatalogItem) error {
+	var problems []string
+	seen := make(map[string]int, len(items))
+	for i := range items {
+		item := &items[i]
+		for _, field := range catalogFields {
+			field.normalize(item)
+			if problem := field.check(item); problem != "" {
+				problems = append(problems, fmt.Sprintf("item %d, %s: %s", i+1, field.name, problem))
+			}
+		}
+		if first, ok := seen[item.ScientificName]; ok && item.ScientificName != "" {
+			problems = append(problems, fmt.Sprintf("item %d: scientific_name %q repete o item %d", i+1, item.ScientificName, first))
+			continue
+		}
+		seen[item.ScientificName] = i + 1
+	}
+	if len(problems) > 0 {
+		return &CatalogValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// DecodeSpecieCatalog lê o catálogo em CSV (com cabeçalho) ou JSON (lista de objetos).
+func DecodeSpecieCatalog(r io.Reader, format string) ([]SpecieCatalogItem, error) {
+	switch format {
+	case CatalogFormatJSON:
+		var items []SpecieCatalogItem
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&items); err != nil {
+			return nil, &CatalogValidationError{Problems: []string{fmt.Sprintf("json inválido: %v", err)}}
+		}
+		return items, nil
+	case CatalogFormatCSV:
+		return decodeCatalogCSV(r)
+	}
+	return nil, ErrInvalidCatalogFormat
+}
+
+func decodeCatalogCSV(r io.Reader) ([]SpecieCatalogItem, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, &CatalogValidationError{Problems: []string{fmt.Sprintf("cabeçalho do csv: %v", err)}}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// planilhas costumam gravar o BOM do UTF-8 antes da primeira coluna
+		columns[strings.TrimPrefix(strings.TrimSpace(name), "\ufeff")] = i
+	}
+	var problems []string
+	for _, field := range catalogFields {
+		if _, ok := columns[field.name]; !ok {
+			problems = append(problems, fmt.Sprintf("cabeçalho do csv: falta a coluna %s", field.name))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &CatalogValidationError{Problems: problems}
+	}
+
+	var items []SpecieCatalogItem
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		var item SpecieCatalogItem
+		for _, field := range catalogFields {
+			if err := field.set(&item, record[columns[field.name]]); err != nil {
+				problems = append(problems, fmt.Sprintf("linha %d, %s: %v", line, field.name, err))
+			}
+		}
+		items = append(items, item)
+	}
+	if len(problems) > 0 {
+		return nil, &CatalogValidationError{Problems: problems}
+	}
+	return items, nil
+}
+
+// EncodeSpecieCatalog grava o catálogo no mesmo formato que DecodeSpecieCatalog lê.
+func EncodeSpecieCatalog(w io.Writer, format string, items []SpecieCatalogItem) error {
+	switch format {
+	case CatalogFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+	case CatalogFormatCSV:
+		writer := csv.NewWriter(w)
+		header := make([]string, len(catalogFields))
+		for i, field := range catalogFields {
+			header[i] = field.name
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for i := range items {
+			record := make([]string, len(catalogFields))
+			for j, field := range catalogFields {
+				record[j] = field.get(&items[i])
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	return ErrInvalidCatalogFormat
+}
+
+func catalogItemFromSpecie(specie *entities.Specie) SpecieCatalogItem {
+	return SpecieCatalogItem{
+		CommonName:          specie.CommonName,
+		SpecieDescription:   specie.SpecieDescription,
+		ScientificName:      specie.ScientificName,
+		BotanicalFamily:     specie.BotanicalFamily,
+		GrowthType:          specie.GrowthType,
+		IdealTemperature:    specie.IdealTemperature,
+		IdealClimate:        specie.IdealClimate,
+		LifeCycle:           specie.LifeCycle,
+		PlantingSeason:      specie.PlantingSeason,
+		HarvestTime:         specie.HarvestTime,
+		AverageHeight:       specie.AverageHeight,
+		AverageWidth:        specie.AverageWidth,
+		IrrigationWeight:    specie.IrrigationWeight,
+		FertilizationWeight: specie.FertilizationWeight,
+		SunWeight:           specie.SunWeight,
+		ImageURL:            specie.ImageURL,
+	}
+}
+
+func (item SpecieCatalogItem) toSpecie() *entities.Specie {
+	return &entities.Specie{
+		CommonName:          item.CommonName,
+		SpecieDescription:   item.SpecieDescription,
+		ScientificName:      item.ScientificName,
+		BotanicalFamily:     item.BotanicalFamily,
+		GrowthType:          item.GrowthType,
+		IdealTemperature:    item.IdealTemperature,
+		IdealClimate:        item.IdealClimate,
+		LifeCycle:           item.LifeCycle,
+		PlantingSeason:      item.PlantingSeason,
+		HarvestTime:         item.HarvestTime,
+		AverageHeight:       item.AverageHeight,
+		AverageWidth:        item.AverageWidth,
+		IrrigationWeight:    item.IrrigationWeight,
+		FertilizationWeight: item.FertilizationWeight,
+		SunWeight:           item.SunWeight,
+		ImageURL:            item.ImageURL,
+	}
+}
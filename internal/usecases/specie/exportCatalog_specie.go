@@ -0,0 +1,33 @@
+package usecases_specie
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type ExportSpecieCatalogUseCase struct {
+	SpecieRepository entities.SpecieRepository
+}
+
+func NewExportSpecieCatalogUseCase(specieRepository entities.SpecieRepository) *ExportSpecieCatalogUseCase {
+	return &ExportSpecieCatalogUseCase{SpecieRepository: specieRepository}
+}
+
+// Execute devolve o catálogo ordenado pelo nome científico, para exportações
+// seguidas darem diffs pequenos no controle de versão.
+func (uc *ExportSpecieCatalogUseCase) Execute(ctx context.Context) ([]SpecieCatalogItem, error) {
+	log.Println("ExportSpecieCatalogUseCase - Execute")
+	species, err := uc.SpecieRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]SpecieCatalogItem, len(species))
+	for i, specie := range species {
+		items[i] = catalogItemFromSpecie(specie)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ScientificName < items[j].ScientificName })
+	return items, nil
+}
@@ -0,0 +1,96 @@
+package usecases_specie
+
+import (
+	"context"
+	"log"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type ImportSpecieCatalogInputDTO struct {
+	Items  []SpecieCatalogItem `json:"items"`
+	DryRun bool                `json:"dry_run"`
+}
+
+type SpecieFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+type SpecieCatalogChange struct {
+	ScientificName string              `json:"scientific_name"`
+	Changes        []SpecieFieldChange `json:"changes"`
+}
+
+// ImportSpecieCatalogOutputDTO é o diff entre o arquivo e o catálogo atual. Em dry run
+// nada foi gravado.
+type ImportSpecieCatalogOutputDTO struct {
+	DryRun    bool                  `json:"dry_run"`
+	Created   []string              `json:"created"`
+	Updated   []SpecieCatalogChange `json:"updated"`
+	Unchanged int                   `json:"unchanged"`
+}
+
+type ImportSpecieCatalogUseCase struct {
+	SpecieRepository entities.SpecieRepository
+}
+
+func NewImportSpecieCatalogUseCase(specieRepository entities.SpecieRepository) *ImportSpecieCatalogUseCase {
+	return &ImportSpecieCatalogUseCase{SpecieRepository: specieRepository}
+}
+
+func (uc *ImportSpecieCatalogUseCase) Execute(ctx context.Context, input ImportSpecieCatalogInputDTO) (*ImportSpecieCatalogOutputDTO, error) {
+	log.Println("ImportSpecieCatalogUseCase - Execute")
+	if len(input.Items) == 0 {
+		return nil, &CatalogValidationError{Problems: []string{"o catálogo não tem espécies"}}
+	}
+	if err := validateCatalog(input.Items); err != nil {
+		return nil, err
+	}
+
+	current, err := uc.SpecieRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]SpecieCatalogItem, len(current))
+	for _, specie := range current {
+		existing[specie.ScientificName] = catalogItemFromSpecie(specie)
+	}
+
+	output := &ImportSpecieCatalogOutputDTO{
+		DryRun:  input.DryRun,
+		Created: []string{},
+		Updated: []SpecieCatalogChange{},
+	}
+	var changed []*entities.Specie
+	for i := range input.Items {
+		item := &input.Items[i]
+		old, ok := existing[item.ScientificName]
+		if !ok {
+			output.Created = append(output.Created, item.ScientificName)
+			changed = append(changed, item.toSpecie())
+			continue
+		}
+		var changes []SpecieFieldChange
+		for _, field := range catalogFields {
+			if before, after := field.get(&old), field.get(item); before != after {
+				changes = append(changes, SpecieFieldChange{Field: field.name, Old: before, New: after})
+			}
+		}
+		if len(changes) == 0 {
+			output.Unchanged++
+			continue
+		}
+		output.Updated = append(output.Updated, SpecieCatalogChange{ScientificName: item.ScientificName, Changes: changes})
+		changed = append(changed, item.toSpecie())
+	}
+
+	if input.DryRun || len(changed) == 0 {
+		return output, nil
+	}
+	if err := uc.SpecieRepository.Upsert(ctx, changed); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -21,6 +21,8 @@ type CreateTaskUseCaseInputDTO struct {
 	CategoriesId []string  `json:"categories_id"`
 	GardensId    []string  `json:"gardens_id"`
 	PlantsId     []string  `json:"plants_id"`
+	// vazio grava no workspace pessoal
+	WorkspaceId string `json:"workspace_id"`
 }
 
 func NewCreateTaskUseCase(repository entities.TaskRepository) *CreateTaskUseCase {
@@ -44,6 +46,7 @@ func (uc *CreateTaskUseCase) Execute(ctx context.Context, input CreateTaskUseCas
 	if err != nil {
 		return nil, err
 	}
+	task.WorkspaceId = input.WorkspaceId
 
 	id, err := uc.Repository.Create(ctx, task)
 	if err != nil {
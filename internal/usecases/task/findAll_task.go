@@ -11,16 +11,17 @@ type FindAllTaskUseCase struct {
 }
 
 type FindAllTaskInputDTO struct {
-	UserId string `json:"user_id"`
+	UserId string               `json:"user_id"`
+	Page   entities.PageRequest `json:"-"`
 }
 
 func NewFindAllTaskUseCase(repository entities.TaskRepository) *FindAllTaskUseCase {
 	return &FindAllTaskUseCase{Repository: repository}
 }
 
-func (u *FindAllTaskUseCase) Execute(ctx context.Context, input FindAllTaskInputDTO) ([]*entities.TaskOutputDTO, error) {
+func (u *FindAllTaskUseCase) Execute(ctx context.Context, input FindAllTaskInputDTO) (*entities.Page[*entities.TaskOutputDTO], error) {
 
-	task, err := u.Repository.FindAll(ctx, input.UserId)
+	task, err := u.Repository.FindAll(ctx, input.UserId, input.Page)
 	if err != nil {
 		return nil, err
 	}
@@ -11,17 +11,18 @@ type FindByCategoryNameTaskUseCase struct {
 }
 
 type FindByCategoryNameTaskInputDTO struct {
-	UserId           string `json:"user_id"`
-	TaskCategoryName string `json:"category_name"`
+	UserId           string               `json:"user_id"`
+	TaskCategoryName string               `json:"category_name"`
+	Page             entities.PageRequest `json:"-"`
 }
 
 func NewFindByCategoryNameTaskUseCase(repository entities.TaskRepository) *FindByCategoryNameTaskUseCase {
 	return &FindByCategoryNameTaskUseCase{Repository: repository}
 }
 
-func (u *FindByCategoryNameTaskUseCase) Execute(ctx context.Context, input FindByCategoryNameTaskInputDTO) ([]*entities.TaskOutputDTO, error) {
+func (u *FindByCategoryNameTaskUseCase) Execute(ctx context.Context, input FindByCategoryNameTaskInputDTO) (*entities.Page[*entities.TaskOutputDTO], error) {
 
-	task, err := u.Repository.FindByCategoryName(ctx, input.UserId, input.TaskCategoryName)
+	task, err := u.Repository.FindByCategoryName(ctx, input.UserId, input.TaskCategoryName, input.Page)
 	if err != nil {
 		return nil, err
 	}
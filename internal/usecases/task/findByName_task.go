@@ -11,17 +11,18 @@ type FindByNameTaskUseCase struct {
 }
 
 type FindByNameTaskInputDTO struct {
-	UserId   string `json:"user_id"`
-	TaskName string `json:"name"`
+	UserId   string               `json:"user_id"`
+	TaskName string               `json:"name"`
+	Page     entities.PageRequest `json:"-"`
 }
 
 func NewFindByNameTaskUseCase(repository entities.TaskRepository) *FindByNameTaskUseCase {
 	return &FindByNameTaskUseCase{Repository: repository}
 }
 
-func (u *FindByNameTaskUseCase) Execute(ctx context.Context, input FindByNameTaskInputDTO) ([]*entities.TaskOutputDTO, error) {
+func (u *FindByNameTaskUseCase) Execute(ctx context.Context, input FindByNameTaskInputDTO) (*entities.Page[*entities.TaskOutputDTO], error) {
 
-	task, err := u.Repository.FindByName(ctx, input.UserId, input.TaskName)
+	task, err := u.Repository.FindByName(ctx, input.UserId, input.TaskName, input.Page)
 	if err != nil {
 		return nil, err
 	}
@@ -11,17 +11,18 @@ type FindByStatusTaskUseCase struct {
 }
 
 type FindByStatusTaskInputDTO struct {
-	UserId     string `json:"user_id"`
-	TaskStatus string `json:"status"`
+	UserId     string               `json:"user_id"`
+	TaskStatus string               `json:"status"`
+	Page       entities.PageRequest `json:"-"`
 }
 
 func NewFindByStatusTaskUseCase(repository entities.TaskRepository) *FindByStatusTaskUseCase {
 	return &FindByStatusTaskUseCase{Repository: repository}
 }
 
-func (u *FindByStatusTaskUseCase) Execute(ctx context.Context, input FindByStatusTaskInputDTO) ([]*entities.TaskOutputDTO, error) {
+func (u *FindByStatusTaskUseCase) Execute(ctx context.Context, input FindByStatusTaskInputDTO) (*entities.Page[*entities.TaskOutputDTO], error) {
 
-	task, err := u.Repository.FindByStatus(ctx, input.UserId, input.TaskStatus)
+	task, err := u.Repository.FindByStatus(ctx, input.UserId, input.TaskStatus, input.Page)
 	if err != nil {
 		return nil, err
 	}
@@ -11,17 +11,18 @@ type FindByUrgencyLevelTaskUseCase struct {
 }
 
 type FindByUrgencyLevelTaskInputDTO struct {
-	UserId           string `json:"user_id"`
-	TaskUrgencyLevel int    `json:"UrgencyLevel"`
+	UserId           string               `json:"user_id"`
+	TaskUrgencyLevel int                  `json:"UrgencyLevel"`
+	Page             entities.PageRequest `json:"-"`
 }
 
 func NewFindByUrgencyLevelTaskUseCase(repository entities.TaskRepository) *FindByUrgencyLevelTaskUseCase {
 	return &FindByUrgencyLevelTaskUseCase{Repository: repository}
 }
 
-func (u *FindByUrgencyLevelTaskUseCase) Execute(ctx context.Context, input FindByUrgencyLevelTaskInputDTO) ([]*entities.TaskOutputDTO, error) {
+func (u *FindByUrgencyLevelTaskUseCase) Execute(ctx context.Context, input FindByUrgencyLevelTaskInputDTO) (*entities.Page[*entities.TaskOutputDTO], error) {
 
-	task, err := u.Repository.FindByUrgencyLevel(ctx, input.UserId, input.TaskUrgencyLevel)
+	task, err := u.Repository.FindByUrgencyLevel(ctx, input.UserId, input.TaskUrgencyLevel, input.Page)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,30 @@
+package usecases_task
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type SearchTaskUseCase struct {
+	Repository entities.TaskRepository
+}
+
+type SearchTaskUseCaseInputDTO struct {
+	UserId string               `json:"user_id"`
+	Filter entities.TaskFilter  `json:"-"`
+	Page   entities.PageRequest `json:"-"`
+}
+
+func NewSearchTaskUseCase(repository entities.TaskRepository) *SearchTaskUseCase {
+	return &SearchTaskUseCase{Repository: repository}
+}
+
+func (u *SearchTaskUseCase) Execute(ctx context.Context, input SearchTaskUseCaseInputDTO) (*entities.Page[*entities.TaskOutputDTO], error) {
+	result, err := u.Repository.Search(ctx, input.UserId, input.Filter, input.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
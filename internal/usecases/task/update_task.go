@@ -23,6 +23,7 @@ type UpdateTaskUseCaseInputDTO struct {
 	CategoriesId []string  `json:"categories_id"`
 	GardensId    []string  `json:"gardens_id"`
 	PlantsId     []string  `json:"plants_id"`
+	Version      int       `json:"version"`
 }
 
 func NewUpdateTaskUseCase(repository entities.TaskRepository) *UpdateTaskUseCase {
@@ -32,6 +33,10 @@ func NewUpdateTaskUseCase(repository entities.TaskRepository) *UpdateTaskUseCase
 }
 
 func (uc *UpdateTaskUseCase) Execute(ctx context.Context, input UpdateTaskUseCaseInputDTO) (*entities.TaskOutputDTO, error) {
+	if input.Version == 0 {
+		return nil, entities.ErrVersionRequired
+	}
+
 	// Verifica se a tarefa existe pelo ID
 	existingTask, err := uc.Repository.FindByID(ctx, input.UserId, input.Id)
 	if err != nil {
@@ -42,14 +47,12 @@ func (uc *UpdateTaskUseCase) Execute(ctx context.Context, input UpdateTaskUseCas
 	}
 
 	// Verifica se já existe uma tarefa com o mesmo nome para o usuário
-	taskByName, err := uc.Repository.FindByName(ctx, input.UserId, input.Name)
+	nameInUse, err := uc.Repository.NameInUse(ctx, input.UserId, input.Name, input.Id)
 	if err != nil {
 		return nil, err
 	}
-	for _, task := range taskByName {
-		if task.Id != input.Id {
-			return nil, errors.New("já existe uma tarefa com este nome")
-		}
+	if nameInUse {
+		return nil, errors.New("já existe uma tarefa com este nome")
 	}
 
 	// Cria um mapa para os campos atualizados
@@ -102,10 +105,19 @@ func (uc *UpdateTaskUseCase) Execute(ctx context.Context, input UpdateTaskUseCas
 		GardensId:    input.GardensId,
 		PlantsId:     input.PlantsId,
 		UpdatedAt:    time.Now(),
+		Version:      input.Version,
 	}
 
 	// Atualiza a tarefa no repositório
 	if err := uc.Repository.Update(ctx, updatedTask); err != nil {
+		if errors.Is(err, entities.ErrVersionConflict) {
+			// devolve o estado atual para o cliente mesclar com o que enviou
+			current, findErr := uc.Repository.FindByID(ctx, input.UserId, input.Id)
+			if findErr != nil {
+				return nil, findErr
+			}
+			return current, err
+		}
 		return nil, err
 	}
 
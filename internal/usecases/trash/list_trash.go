@@ -0,0 +1,44 @@
+package usecases_trash
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type ListTrashUseCase struct {
+	Repository entities.TrashRepository
+}
+
+type ListTrashUseCaseInputDTO struct {
+	UserId string `json:"user_id"`
+	Type   string `json:"type"`
+}
+
+func NewListTrashUseCase(repository entities.TrashRepository) *ListTrashUseCase {
+	return &ListTrashUseCase{Repository: repository}
+}
+
+// Execute lista a lixeira do usuário, do item excluído mais recente ao mais antigo;
+// com Type preenchido, só os daquele tipo.
+func (u *ListTrashUseCase) Execute(ctx context.Context, input ListTrashUseCaseInputDTO) ([]*entities.TrashItem, error) {
+	if input.Type != "" && !entities.IsTrashType(input.Type) {
+		return nil, ErrInvalidType
+	}
+
+	items, err := u.Repository.List(ctx, input.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if input.Type == "" {
+		return items, nil
+	}
+
+	filtered := make([]*entities.TrashItem, 0, len(items))
+	for _, item := range items {
+		if item.Type == input.Type {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,37 @@
+package usecases_trash
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+var (
+	ErrInvalidType = errors.New("tipo inválido: use plant, garden, task, category_plant ou category_task")
+	ErrMissingId   = errors.New("id é obrigatório")
+)
+
+type RestoreTrashUseCase struct {
+	Repository entities.TrashRepository
+}
+
+type RestoreTrashUseCaseInputDTO struct {
+	UserId string `json:"user_id"`
+	Type   string `json:"type"`
+	Id     string `json:"id"`
+}
+
+func NewRestoreTrashUseCase(repository entities.TrashRepository) *RestoreTrashUseCase {
+	return &RestoreTrashUseCase{Repository: repository}
+}
+
+func (u *RestoreTrashUseCase) Execute(ctx context.Context, input RestoreTrashUseCaseInputDTO) error {
+	if !entities.IsTrashType(input.Type) {
+		return ErrInvalidType
+	}
+	if input.Id == "" {
+		return ErrMissingId
+	}
+	return u.Repository.Restore(ctx, input.UserId, input.Type, input.Id)
+}
@@ -3,7 +3,6 @@ package usecases
 import (
 	"context"
 	"log"
-	"os"
 
 	"github.com/lucasBiazon/botany-back/internal/entities"
 	services "github.com/lucasBiazon/botany-back/internal/service"
@@ -16,11 +15,13 @@ type LoginUserInputDTO struct {
 
 type LoginUserUseCase struct {
 	UserRepository entities.UserRepository
+	JWTService     services.JWTService
 }
 
-func NewLoginUserUseCase(userRepo entities.UserRepository) *LoginUserUseCase {
+func NewLoginUserUseCase(userRepo entities.UserRepository, jwtService services.JWTService) *LoginUserUseCase {
 	return &LoginUserUseCase{
 		UserRepository: userRepo,
+		JWTService:     jwtService,
 	}
 }
 
@@ -37,8 +38,7 @@ func (uc *LoginUserUseCase) Execute(ctx context.Context, input LoginUserInputDTO
 		return "", err
 	}
 
-	secretKey := os.Getenv("JWT_SECRET_KEY")
-	tokenJWT, err := services.NewJWTService(secretKey).GenerateToken(ID)
+	tokenJWT, err := uc.JWTService.GenerateToken(ID)
 	if err != nil {
 		return "", err
 	}
@@ -25,10 +25,14 @@ type ResendTokenInputDTO struct {
 }
 type RegisterUserUseCase struct {
 	userRepository entities.UserRepository
+	emailService   services.EmailService
 }
 
-func NewRegisterUserUseCase(userRepository entities.UserRepository) *RegisterUserUseCase {
-	return &RegisterUserUseCase{userRepository: userRepository}
+func NewRegisterUserUseCase(userRepository entities.UserRepository, emailService services.EmailService) *RegisterUserUseCase {
+	return &RegisterUserUseCase{
+		userRepository: userRepository,
+		emailService:   emailService,
+	}
 }
 
 func (uc *RegisterUserUseCase) StartRegistration(ctx context.Context, input RegisterUserInputDTO) error {
@@ -51,7 +55,7 @@ func (uc *RegisterUserUseCase) StartRegistration(ctx context.Context, input Regi
 		return err
 	}
 
-	tokenVerification, err := services.NewEmailService().GenerateCode()
+	tokenVerification, err := uc.emailService.GenerateCode()
 	if err != nil {
 		return err
 	}
@@ -60,7 +64,7 @@ func (uc *RegisterUserUseCase) StartRegistration(ctx context.Context, input Regi
 		return err
 	}
 
-	if err = services.NewEmailService().SendEmail(user.Email, tokenVerification); err != nil {
+	if err = uc.emailService.SendEmail(user.Email, tokenVerification); err != nil {
 		return err
 	}
 	return nil
@@ -77,7 +81,7 @@ func (uc *RegisterUserUseCase) ConfirmEmail(ctx context.Context, input ConfirmEm
 
 func (uc *RegisterUserUseCase) ResendToken(ctx context.Context, input ResendTokenInputDTO) error {
 	log.Println("ResendTokenUseCase - Execute")
-	newToken, err := services.NewEmailService().GenerateCode()
+	newToken, err := uc.emailService.GenerateCode()
 	if err != nil {
 		return err
 	}
@@ -85,7 +89,7 @@ func (uc *RegisterUserUseCase) ResendToken(ctx context.Context, input ResendToke
 	if err != nil {
 		return err
 	}
-	err = services.NewEmailService().SendEmail(input.Email, token)
+	err = uc.emailService.SendEmail(input.Email, token)
 	if err != nil {
 		return err
 	}
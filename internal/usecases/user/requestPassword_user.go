@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/lucasBiazon/botany-back/internal/entities"
 	services "github.com/lucasBiazon/botany-back/internal/service"
@@ -16,12 +17,16 @@ type RequestPasswordResetUserInputDTO struct {
 type RequestPasswordResetUserUseCase struct {
 	UserRepository entities.UserRepository
 	JWTService     services.JWTService
+	EmailService   services.EmailService
+	ResetTokenTTL  time.Duration
 }
 
-func NewRequestPasswordResetUseCase(userRepo entities.UserRepository, jwtService services.JWTService) *RequestPasswordResetUserUseCase {
+func NewRequestPasswordResetUseCase(userRepo entities.UserRepository, jwtService services.JWTService, emailService services.EmailService, resetTokenTTL time.Duration) *RequestPasswordResetUserUseCase {
 	return &RequestPasswordResetUserUseCase{
 		UserRepository: userRepo,
 		JWTService:     jwtService,
+		EmailService:   emailService,
+		ResetTokenTTL:  resetTokenTTL,
 	}
 }
 
@@ -32,12 +37,12 @@ func (uc *RequestPasswordResetUserUseCase) Execute(ctx context.Context, input Re
 		return errors.New("usuário não encontrado")
 	}
 
-	resetToken, err := uc.JWTService.GenerateToken(user.Id)
+	resetToken, err := uc.JWTService.GenerateTokenWithTTL(user.Id, uc.ResetTokenTTL)
 	if err != nil {
 		return errors.New("erro ao gerar token de redefinição de senha")
 	}
 
-	err = services.NewEmailService().SendEmailResetPassword(user.Email, resetToken)
+	err = uc.EmailService.SendEmailResetPassword(user.Email, resetToken)
 	if err != nil {
 		return errors.New("erro ao enviar email de redefinição de senha")
 	}
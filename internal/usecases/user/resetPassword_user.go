@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"log"
-	"os"
 
 	"github.com/lucasBiazon/botany-back/internal/entities"
 	services "github.com/lucasBiazon/botany-back/internal/service"
@@ -34,7 +33,7 @@ func (uc *ResetPasswordUserUseCase) Execute(ctx context.Context, input ResetPass
 		return errors.New("token inválido ou já utilizado")
 	}
 
-	userID, err := services.ExtractUserIDFromToken(input.Token, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userID, err := services.ExtractUserIDFromToken(input.Token, uc.JWTService)
 	if err != nil {
 		return err
 	}
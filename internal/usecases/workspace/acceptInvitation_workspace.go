@@ -0,0 +1,45 @@
+package usecases_workspace
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type AcceptInvitationWorkspaceUseCase struct {
+	Repository     entities.WorkspaceRepository
+	UserRepository entities.UserRepository
+}
+
+type AcceptInvitationWorkspaceUseCaseInputDTO struct {
+	UserId       string `json:"user_id"`
+	InvitationId string `json:"invitation_id"`
+}
+
+func NewAcceptInvitationWorkspaceUseCase(repository entities.WorkspaceRepository, userRepository entities.UserRepository) *AcceptInvitationWorkspaceUseCase {
+	return &AcceptInvitationWorkspaceUseCase{
+		Repository:     repository,
+		UserRepository: userRepository,
+	}
+}
+
+// Execute aceita o convite se ele foi feito para o email da conta do usuário.
+func (u *AcceptInvitationWorkspaceUseCase) Execute(ctx context.Context, input AcceptInvitationWorkspaceUseCaseInputDTO) (*entities.Workspace, error) {
+	if input.InvitationId == "" {
+		return nil, ErrMissingId
+	}
+	user, err := u.UserRepository.FindByID(ctx, input.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	workspaceId, err := u.Repository.AcceptInvitation(ctx, input.UserId, user.Email, input.InvitationId)
+	if err != nil {
+		return nil, err
+	}
+	return u.Repository.FindByID(ctx, input.UserId, workspaceId)
+}
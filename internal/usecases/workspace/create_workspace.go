@@ -0,0 +1,37 @@
+package usecases_workspace
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+var ErrMissingId = errors.New("id é obrigatório")
+
+type CreateWorkspaceUseCase struct {
+	Repository entities.WorkspaceRepository
+}
+
+type CreateWorkspaceUseCaseInputDTO struct {
+	UserId string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func NewCreateWorkspaceUseCase(repository entities.WorkspaceRepository) *CreateWorkspaceUseCase {
+	return &CreateWorkspaceUseCase{Repository: repository}
+}
+
+// Execute cria um workspace compartilhável com quem o criou como owner.
+func (u *CreateWorkspaceUseCase) Execute(ctx context.Context, input CreateWorkspaceUseCaseInputDTO) (*entities.Workspace, error) {
+	workspace, err := entities.NewWorkspace(input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := u.Repository.Create(ctx, input.UserId, workspace)
+	if err != nil {
+		return nil, err
+	}
+	return u.Repository.FindByID(ctx, input.UserId, id)
+}
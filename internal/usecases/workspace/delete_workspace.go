@@ -0,0 +1,29 @@
+package usecases_workspace
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type DeleteWorkspaceUseCase struct {
+	Repository entities.WorkspaceRepository
+}
+
+type DeleteWorkspaceUseCaseInputDTO struct {
+	UserId string `json:"user_id"`
+	Id     string `json:"id"`
+}
+
+func NewDeleteWorkspaceUseCase(repository entities.WorkspaceRepository) *DeleteWorkspaceUseCase {
+	return &DeleteWorkspaceUseCase{Repository: repository}
+}
+
+// Execute exclui o workspace com jardins, plantas, tarefas e categorias dele.
+// Só o owner pode, e o workspace pessoal não pode ser excluído.
+func (u *DeleteWorkspaceUseCase) Execute(ctx context.Context, input DeleteWorkspaceUseCaseInputDTO) error {
+	if input.Id == "" {
+		return ErrMissingId
+	}
+	return u.Repository.Delete(ctx, input.UserId, input.Id)
+}
@@ -0,0 +1,24 @@
+package usecases_workspace
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type FindAllWorkspaceUseCase struct {
+	Repository entities.WorkspaceRepository
+}
+
+type FindAllWorkspaceUseCaseInputDTO struct {
+	UserId string `json:"user_id"`
+}
+
+func NewFindAllWorkspaceUseCase(repository entities.WorkspaceRepository) *FindAllWorkspaceUseCase {
+	return &FindAllWorkspaceUseCase{Repository: repository}
+}
+
+// Execute lista os workspaces de que o usuário é membro, o pessoal primeiro.
+func (u *FindAllWorkspaceUseCase) Execute(ctx context.Context, input FindAllWorkspaceUseCaseInputDTO) ([]*entities.Workspace, error) {
+	return u.Repository.FindAll(ctx, input.UserId)
+}
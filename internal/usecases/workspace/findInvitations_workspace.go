@@ -0,0 +1,36 @@
+package usecases_workspace
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type FindInvitationsWorkspaceUseCase struct {
+	Repository     entities.WorkspaceRepository
+	UserRepository entities.UserRepository
+}
+
+type FindInvitationsWorkspaceUseCaseInputDTO struct {
+	UserId string `json:"user_id"`
+}
+
+func NewFindInvitationsWorkspaceUseCase(repository entities.WorkspaceRepository, userRepository entities.UserRepository) *FindInvitationsWorkspaceUseCase {
+	return &FindInvitationsWorkspaceUseCase{
+		Repository:     repository,
+		UserRepository: userRepository,
+	}
+}
+
+// Execute lista os convites pendentes para o email da conta do usuário.
+func (u *FindInvitationsWorkspaceUseCase) Execute(ctx context.Context, input FindInvitationsWorkspaceUseCaseInputDTO) ([]*entities.WorkspaceInvitation, error) {
+	user, err := u.UserRepository.FindByID(ctx, input.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	return u.Repository.FindInvitationsByEmail(ctx, user.Email)
+}
@@ -0,0 +1,28 @@
+package usecases_workspace
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type FindMembersWorkspaceUseCase struct {
+	Repository entities.WorkspaceRepository
+}
+
+type FindMembersWorkspaceUseCaseInputDTO struct {
+	UserId      string `json:"user_id"`
+	WorkspaceId string `json:"workspace_id"`
+}
+
+func NewFindMembersWorkspaceUseCase(repository entities.WorkspaceRepository) *FindMembersWorkspaceUseCase {
+	return &FindMembersWorkspaceUseCase{Repository: repository}
+}
+
+// Execute lista os membros do workspace para qualquer um deles.
+func (u *FindMembersWorkspaceUseCase) Execute(ctx context.Context, input FindMembersWorkspaceUseCaseInputDTO) ([]*entities.WorkspaceMember, error) {
+	if input.WorkspaceId == "" {
+		return nil, ErrMissingId
+	}
+	return u.Repository.FindMembers(ctx, input.UserId, input.WorkspaceId)
+}
@@ -0,0 +1,54 @@
+package usecases_workspace
+
+import (
+	"context"
+	"log"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+	services "github.com/lucasBiazon/botany-back/internal/service"
+)
+
+type InviteWorkspaceUseCase struct {
+	Repository   entities.WorkspaceRepository
+	EmailService services.EmailService
+}
+
+type InviteWorkspaceUseCaseInputDTO struct {
+	UserId      string `json:"user_id"`
+	WorkspaceId string `json:"workspace_id"`
+	Email       string `json:"email"`
+	// vazio convida como editor
+	Role string `json:"role"`
+}
+
+func NewInviteWorkspaceUseCase(repository entities.WorkspaceRepository, emailService services.EmailService) *InviteWorkspaceUseCase {
+	return &InviteWorkspaceUseCase{
+		Repository:   repository,
+		EmailService: emailService,
+	}
+}
+
+// Execute convida um email para o workspace. Só o owner convida, e um convite
+// novo para o mesmo email substitui o pendente.
+func (u *InviteWorkspaceUseCase) Execute(ctx context.Context, input InviteWorkspaceUseCaseInputDTO) (*entities.WorkspaceInvitation, error) {
+	if input.WorkspaceId == "" {
+		return nil, ErrMissingId
+	}
+	if input.Role == "" {
+		input.Role = entities.WorkspaceRoleEditor
+	}
+	invitation, err := entities.NewWorkspaceInvitation(input.WorkspaceId, input.Email, input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.Repository.CreateInvitation(ctx, input.UserId, invitation); err != nil {
+		return nil, err
+	}
+
+	// o convite já vale e aparece para o convidado no app; o email é só o aviso
+	if err := u.EmailService.SendWorkspaceInvitation(invitation.Email, invitation.WorkspaceName); err != nil {
+		log.Printf("Erro ao enviar convite %s: %v", invitation.Id, err)
+	}
+	return invitation, nil
+}
@@ -0,0 +1,33 @@
+package usecases_workspace
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type RemoveMemberWorkspaceUseCase struct {
+	Repository entities.WorkspaceRepository
+}
+
+type RemoveMemberWorkspaceUseCaseInputDTO struct {
+	UserId      string `json:"user_id"`
+	WorkspaceId string `json:"workspace_id"`
+	// vazio é o próprio usuário saindo do workspace
+	MemberId string `json:"member_id"`
+}
+
+func NewRemoveMemberWorkspaceUseCase(repository entities.WorkspaceRepository) *RemoveMemberWorkspaceUseCase {
+	return &RemoveMemberWorkspaceUseCase{Repository: repository}
+}
+
+// Execute tira um membro do workspace. O que ele gravou lá continua no workspace.
+func (u *RemoveMemberWorkspaceUseCase) Execute(ctx context.Context, input RemoveMemberWorkspaceUseCaseInputDTO) error {
+	if input.WorkspaceId == "" {
+		return ErrMissingId
+	}
+	if input.MemberId == "" {
+		input.MemberId = input.UserId
+	}
+	return u.Repository.RemoveMember(ctx, input.UserId, input.WorkspaceId, input.MemberId)
+}
@@ -0,0 +1,38 @@
+package usecases_workspace
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type UpdateMemberWorkspaceUseCase struct {
+	Repository entities.WorkspaceRepository
+}
+
+type UpdateMemberWorkspaceUseCaseInputDTO struct {
+	UserId      string `json:"user_id"`
+	WorkspaceId string `json:"workspace_id"`
+	MemberId    string `json:"member_id"`
+	Role        string `json:"role"`
+}
+
+func NewUpdateMemberWorkspaceUseCase(repository entities.WorkspaceRepository) *UpdateMemberWorkspaceUseCase {
+	return &UpdateMemberWorkspaceUseCase{Repository: repository}
+}
+
+// Execute troca o papel de um membro. Só o owner pode, e o workspace não fica
+// sem nenhum owner.
+func (u *UpdateMemberWorkspaceUseCase) Execute(ctx context.Context, input UpdateMemberWorkspaceUseCaseInputDTO) ([]*entities.WorkspaceMember, error) {
+	if input.WorkspaceId == "" || input.MemberId == "" {
+		return nil, ErrMissingId
+	}
+	if !entities.IsWorkspaceRole(input.Role) {
+		return nil, entities.ErrInvalidWorkspaceRole
+	}
+
+	if err := u.Repository.UpdateMemberRole(ctx, input.UserId, input.WorkspaceId, input.MemberId, input.Role); err != nil {
+		return nil, err
+	}
+	return u.Repository.FindMembers(ctx, input.UserId, input.WorkspaceId)
+}
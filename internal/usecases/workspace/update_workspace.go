@@ -0,0 +1,38 @@
+package usecases_workspace
+
+import (
+	"context"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+type UpdateWorkspaceUseCase struct {
+	Repository entities.WorkspaceRepository
+}
+
+type UpdateWorkspaceUseCaseInputDTO struct {
+	UserId string `json:"user_id"`
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+}
+
+func NewUpdateWorkspaceUseCase(repository entities.WorkspaceRepository) *UpdateWorkspaceUseCase {
+	return &UpdateWorkspaceUseCase{Repository: repository}
+}
+
+// Execute renomeia o workspace; só o owner pode.
+func (u *UpdateWorkspaceUseCase) Execute(ctx context.Context, input UpdateWorkspaceUseCaseInputDTO) (*entities.Workspace, error) {
+	if input.Id == "" {
+		return nil, ErrMissingId
+	}
+	workspace, err := entities.NewWorkspace(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	workspace.Id = input.Id
+
+	if err := u.Repository.Update(ctx, input.UserId, workspace); err != nil {
+		return nil, err
+	}
+	return u.Repository.FindByID(ctx, input.UserId, input.Id)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+	services "github.com/lucasBiazon/botany-back/internal/service"
+	usecases_care "github.com/lucasBiazon/botany-back/internal/usecases/care"
+	"github.com/lucasBiazon/botany-back/internal/utils"
+)
+
+// careLogErrorStatus estende writeErrorStatus com as validações do registro de cuidados.
+func careLogErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrInvalidCareType),
+		errors.Is(err, entities.ErrMissingMeasurement),
+		errors.Is(err, entities.ErrInvalidMeasurement),
+		errors.Is(err, entities.ErrInvalidCareAmount),
+		errors.Is(err, entities.ErrCareInFuture),
+		errors.Is(err, entities.ErrCareNotesTooLong),
+		errors.Is(err, entities.ErrMissingCareIds),
+		errors.Is(err, entities.ErrTooManyCareIds),
+		errors.Is(err, entities.ErrInvalidCareId):
+		return http.StatusBadRequest
+	case errors.Is(err, entities.ErrCareSubjectNotFound):
+		return http.StatusNotFound
+	}
+	return writeErrorStatus(err)
+}
+
+type CareHandler struct {
+	FindDueCareUseCase *usecases_care.FindDueCareUseCase
+	JWTService         services.JWTService
+}
+
+func NewCareHandler(findDueCareUseCase *usecases_care.FindDueCareUseCase, jwtService services.JWTService) *CareHandler {
+	return &CareHandler{
+		FindDueCareUseCase: findDueCareUseCase,
+		JWTService:         jwtService,
+	}
+}
+
+// FindDueCareHandler atende GET /api/v1/care/due?within=3d
+func (h *CareHandler) FindDueCareHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+
+	within, err := queryDuration(r.URL.Query(), "within")
+	if err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		return
+	}
+
+	input := usecases_care.FindDueCareUseCaseInputDTO{
+		UserId: userId,
+		Within: usecases_care.DefaultDueWithin,
+	}
+	if within != nil {
+		input.Within = *within
+	}
+	output, err := h.FindDueCareUseCase.Execute(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, usecases_care.ErrInvalidWithin) {
+			utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+			return
+		}
+		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Cuidados encontrados", output)
+}
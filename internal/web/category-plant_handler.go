@@ -1,10 +1,8 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
-	"os"
 
 	services "github.com/lucasBiazon/botany-back/internal/service"
 	usecase_categoryplant "github.com/lucasBiazon/botany-back/internal/usecases/category-plant"
@@ -18,6 +16,7 @@ type CategoryPlantHandlers struct {
 	FindByIdCategoryPlantUseCase   *usecase_categoryplant.FindByIdCategoryPlantUseCase
 	FindByNameCategoryPlantUseCase *usecase_categoryplant.FindByNameCategoryPlantUseCase
 	UpdateCategoryPlantUseCase     *usecase_categoryplant.UpdateCategoryPlantUseCase
+	JWTService                     services.JWTService
 }
 
 func NewCategoryPlantHandler(
@@ -27,6 +26,7 @@ func NewCategoryPlantHandler(
 	findByIdCategoryPlantUseCase *usecase_categoryplant.FindByIdCategoryPlantUseCase,
 	findByNameCategoryPlantUseCase *usecase_categoryplant.FindByNameCategoryPlantUseCase,
 	updateCategoryPlantUseCase *usecase_categoryplant.UpdateCategoryPlantUseCase,
+	jwtService services.JWTService,
 ) *CategoryPlantHandlers {
 	return &CategoryPlantHandlers{
 		CreateCategoryPlantUseCase:     createCategoryPlantUseCase,
@@ -35,6 +35,7 @@ func NewCategoryPlantHandler(
 		FindByIdCategoryPlantUseCase:   findByIdCategoryPlantUseCase,
 		FindByNameCategoryPlantUseCase: findByNameCategoryPlantUseCase,
 		UpdateCategoryPlantUseCase:     updateCategoryPlantUseCase,
+		JWTService:                     jwtService,
 	}
 }
 
@@ -45,14 +46,14 @@ func (h *CategoryPlantHandlers) CreateCategoryPlantHandler(w http.ResponseWriter
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
-	categoryPlant, err := h.CreateCategoryPlantUseCase.Execute(context.Background(), input, userId)
+	categoryPlant, err := h.CreateCategoryPlantUseCase.Execute(r.Context(), input, userId)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao criar categoria de planta", err.Error())
+		utils.JsonResponse(w, writeErrorStatus(err), "error", "Erro ao criar categoria de planta", err.Error())
 		return
 	}
 	if categoryPlant == nil {
@@ -69,28 +70,28 @@ func (h *CategoryPlantHandlers) DeleteCategoryPlantHandler(w http.ResponseWriter
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	if err := h.DeleteCategoryPlantUseCase.Execute(context.Background(), input); err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao deletar categoria de planta", err.Error())
+	if err := h.DeleteCategoryPlantUseCase.Execute(r.Context(), input); err != nil {
+		utils.JsonResponse(w, writeErrorStatus(err), "error", "Erro ao deletar categoria de planta", err.Error())
 		return
 	}
-	utils.JsonResponse(w, http.StatusOK, "success", "Categoria de planta deletado com sucesso", nil)
+	utils.JsonResponse(w, http.StatusOK, "success", "Categoria de planta movida para a lixeira", nil)
 }
 
 func (h *CategoryPlantHandlers) FindAllCategoryPlantHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 
-	categoryPlants, err := h.FindAllCategoryPlantUseCase.Execute(context.Background(), userId)
+	categoryPlants, err := h.FindAllCategoryPlantUseCase.Execute(r.Context(), userId)
 	if len(categoryPlants) == 0 {
 		utils.JsonResponse(w, http.StatusNotFound, "error", "Categorias de planta não encontradas", nil)
 		return
@@ -109,13 +110,13 @@ func (h *CategoryPlantHandlers) FindByIdCategoryPlantHandler(w http.ResponseWrit
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	categoryPlant, err := h.FindByIdCategoryPlantUseCase.Execute(context.Background(), input)
+	categoryPlant, err := h.FindByIdCategoryPlantUseCase.Execute(r.Context(), input)
 	if categoryPlant == nil {
 		utils.JsonResponse(w, http.StatusNotFound, "error", "Categoria de planta não encontrada", nil)
 		return
@@ -135,13 +136,13 @@ func (h *CategoryPlantHandlers) FindByNameCategoryPlantHandler(w http.ResponseWr
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	categoryPlant, err := h.FindByNameCategoryPlantUseCase.Execute(context.Background(), input)
+	categoryPlant, err := h.FindByNameCategoryPlantUseCase.Execute(r.Context(), input)
 	if len(categoryPlant) == 0 {
 		utils.JsonResponse(w, http.StatusNotFound, "error", "Categoria de planta não encontrada", nil)
 		return
@@ -160,16 +161,16 @@ func (h *CategoryPlantHandlers) UpdateCategoryPlantHandler(w http.ResponseWriter
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 
 	input.UserId = userId
-	categoryPlant, err := h.UpdateCategoryPlantUseCase.Execute(context.Background(), input)
+	categoryPlant, err := h.UpdateCategoryPlantUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao atualizar categoria de planta", err.Error())
+		utils.JsonResponse(w, writeErrorStatus(err), "error", "Erro ao atualizar categoria de planta", err.Error())
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Categoria de planta atualizada com sucesso", categoryPlant)
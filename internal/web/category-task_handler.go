@@ -1,10 +1,8 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
-	"os"
 
 	services "github.com/lucasBiazon/botany-back/internal/service"
 	usecases_categoryTask "github.com/lucasBiazon/botany-back/internal/usecases/category-task"
@@ -18,6 +16,7 @@ type CategoryTaskHandlers struct {
 	FindByIdCategoryTaskUseCase   *usecases_categoryTask.FindByIdCategoryTaskUseCase
 	FindByNameCategoryTaskUseCase *usecases_categoryTask.FindByNameCategoryTaskUseCase
 	UpdateCategoryTaskUseCase     *usecases_categoryTask.UpdateCategoryTaskUseCase
+	JWTService                    services.JWTService
 }
 
 func NewCategoryTaskHandler(
@@ -27,6 +26,7 @@ func NewCategoryTaskHandler(
 	findByIdCategoryTaskUseCase *usecases_categoryTask.FindByIdCategoryTaskUseCase,
 	findByNameCategoryTaskUseCase *usecases_categoryTask.FindByNameCategoryTaskUseCase,
 	updateCategoryTaskUseCase *usecases_categoryTask.UpdateCategoryTaskUseCase,
+	jwtService services.JWTService,
 ) *CategoryTaskHandlers {
 	return &CategoryTaskHandlers{
 		CreateCategoryTaskUseCase:     createCategoryTaskUseCase,
@@ -35,6 +35,7 @@ func NewCategoryTaskHandler(
 		FindByIdCategoryTaskUseCase:   findByIdCategoryTaskUseCase,
 		FindByNameCategoryTaskUseCase: findByNameCategoryTaskUseCase,
 		UpdateCategoryTaskUseCase:     updateCategoryTaskUseCase,
+		JWTService:                    jwtService,
 	}
 }
 
@@ -45,14 +46,14 @@ func (h *CategoryTaskHandlers) CreateCategoryTaskHandler(w http.ResponseWriter,
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
-	categoryTask, err := h.CreateCategoryTaskUseCase.Execute(context.Background(), input, userId)
+	categoryTask, err := h.CreateCategoryTaskUseCase.Execute(r.Context(), input, userId)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao criar categoria de Taska", err.Error())
+		utils.JsonResponse(w, writeErrorStatus(err), "error", "Erro ao criar categoria de Taska", err.Error())
 		return
 	}
 	if categoryTask == nil {
@@ -69,28 +70,28 @@ func (h *CategoryTaskHandlers) DeleteCategoryTaskHandler(w http.ResponseWriter,
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	if err := h.DeleteCategoryTaskUseCase.Execute(context.Background(), input); err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao deletar categoria de Taska", err.Error())
+	if err := h.DeleteCategoryTaskUseCase.Execute(r.Context(), input); err != nil {
+		utils.JsonResponse(w, writeErrorStatus(err), "error", "Erro ao deletar categoria de Taska", err.Error())
 		return
 	}
-	utils.JsonResponse(w, http.StatusOK, "success", "Categoria de Taska deletado com sucesso", nil)
+	utils.JsonResponse(w, http.StatusOK, "success", "Categoria de tarefa movida para a lixeira", nil)
 }
 
 func (h *CategoryTaskHandlers) FindAllCategoryTaskHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 
-	categoryTasks, err := h.FindAllCategoryTaskUseCase.Execute(context.Background(), userId)
+	categoryTasks, err := h.FindAllCategoryTaskUseCase.Execute(r.Context(), userId)
 	if len(categoryTasks) == 0 {
 		utils.JsonResponse(w, http.StatusNotFound, "error", "Categorias de Taska não encontradas", nil)
 		return
@@ -109,13 +110,13 @@ func (h *CategoryTaskHandlers) FindByIdCategoryTaskHandler(w http.ResponseWriter
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	categoryTask, err := h.FindByIdCategoryTaskUseCase.Execute(context.Background(), input)
+	categoryTask, err := h.FindByIdCategoryTaskUseCase.Execute(r.Context(), input)
 	if categoryTask == nil {
 		utils.JsonResponse(w, http.StatusNotFound, "error", "Categoria de Taska não encontrada", nil)
 		return
@@ -135,13 +136,13 @@ func (h *CategoryTaskHandlers) FindByNameCategoryTaskHandler(w http.ResponseWrit
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	categoryTask, err := h.FindByNameCategoryTaskUseCase.Execute(context.Background(), input)
+	categoryTask, err := h.FindByNameCategoryTaskUseCase.Execute(r.Context(), input)
 	if len(categoryTask) == 0 {
 		utils.JsonResponse(w, http.StatusNotFound, "error", "Categoria de Taska não encontrada", nil)
 		return
@@ -160,16 +161,16 @@ func (h *CategoryTaskHandlers) UpdateCategoryTaskHandler(w http.ResponseWriter,
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 
 	input.UserId = userId
-	categoryTask, err := h.UpdateCategoryTaskUseCase.Execute(context.Background(), input)
+	categoryTask, err := h.UpdateCategoryTaskUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao atualizar categoria de Taska", err.Error())
+		utils.JsonResponse(w, writeErrorStatus(err), "error", "Erro ao atualizar categoria de Taska", err.Error())
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Categoria de Taska atualizada com sucesso", categoryTask)
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+var errInvalidIfMatch = errors.New("cabeçalho If-Match inválido")
+
+// setETag expõe a versão do registro para o cliente devolver no If-Match.
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion lê a versão do If-Match ("3" ou W/"3"). Sem o cabeçalho vale
+// a versão enviada no corpo.
+func ifMatchVersion(r *http.Request, bodyVersion int) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return bodyVersion, nil
+	}
+	tag, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// updateErrorStatus separa versão ausente e conflito de versão de falhas internas.
+func updateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrVersionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, entities.ErrVersionConflict):
+		return http.StatusConflict
+	}
+	return writeErrorStatus(err)
+}
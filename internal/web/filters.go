@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+)
+
+const dateLayout = "2006-01-02"
+
+// queryDate aceita RFC3339 ou só a data. Para limites superiores (endOfDay) uma
+// data sem hora cobre o dia inteiro.
+func queryDate(query url.Values, key string, endOfDay bool) (*time.Time, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("parâmetro %s inválido: use AAAA-MM-DD ou RFC3339", key)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Microsecond)
+	}
+	return &t, nil
+}
+
+func queryInt(query url.Values, key string) (*int, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("parâmetro %s inválido: esperado número inteiro", key)
+	}
+	return &n, nil
+}
+
+// queryDuration aceita dias ("3d") além das durações do Go ("12h", "90m").
+func queryDuration(query url.Values, key string) (*time.Duration, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return nil, fmt.Errorf("parâmetro %s inválido: use dias (3d) ou horas (12h)", key)
+		}
+		d := time.Duration(n) * 24 * time.Hour
+		return &d, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("parâmetro %s inválido: use dias (3d) ou horas (12h)", key)
+	}
+	return &d, nil
+}
+
+// queryList aceita o parâmetro repetido ou separado por vírgula.
+func queryList(query url.Values, key string) []string {
+	var values []string
+	for _, raw := range query[key] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func parsePlantFilter(query url.Values) (entities.PlantFilter, error) {
+	filter := entities.PlantFilter{
+		Name:         query.Get("name"),
+		HealthStatus: query.Get("health_status"),
+		PlantStatus:  query.Get("plant_status"),
+		Species:      query.Get("species"),
+		Category:     query.Get("category"),
+		Garden:       query.Get("garden"),
+	}
+	var err error
+	if filter.PlantedFrom, err = queryDate(query, "planted_from", false); err != nil {
+		return filter, err
+	}
+	if filter.PlantedTo, err = queryDate(query, "planted_to", true); err != nil {
+		return filter, err
+	}
+	if filter.LastIrrigationBefore, err = queryDate(query, "last_irrigation_before", false); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func parseGardenFilter(query url.Values) (entities.GardenFilter, error) {
+	filter := entities.GardenFilter{
+		Name:     query.Get("name"),
+		Location: query.Get("location"),
+		Category: query.Get("category"),
+		Plant:    query.Get("plant"),
+	}
+	var err error
+	if filter.PlantedFrom, err = queryDate(query, "planted_from", false); err != nil {
+		return filter, err
+	}
+	if filter.PlantedTo, err = queryDate(query, "planted_to", true); err != nil {
+		return filter, err
+	}
+	if filter.LastIrrigationBefore, err = queryDate(query, "last_irrigation_before", false); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func parseTaskFilter(query url.Values) (entities.TaskFilter, error) {
+	filter := entities.TaskFilter{
+		Name:     query.Get("name"),
+		Category: query.Get("category"),
+		Plant:    query.Get("plant"),
+		Garden:   query.Get("garden"),
+		Statuses: queryList(query, "status"),
+	}
+	var err error
+	if filter.UrgencyMin, err = queryInt(query, "urgency_min"); err != nil {
+		return filter, err
+	}
+	if filter.UrgencyMax, err = queryInt(query, "urgency_max"); err != nil {
+		return filter, err
+	}
+	if filter.TaskDateFrom, err = queryDate(query, "task_date_from", false); err != nil {
+		return filter, err
+	}
+	if filter.TaskDateTo, err = queryDate(query, "task_date_to", true); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"os"
 
+	"github.com/go-chi/chi"
 	services "github.com/lucasBiazon/botany-back/internal/service"
 	usecases_garden "github.com/lucasBiazon/botany-back/internal/usecases/garden"
 	"github.com/lucasBiazon/botany-back/internal/utils"
@@ -21,6 +21,10 @@ type GardenHandler struct {
 	FindByNameGardenUseCase         *usecases_garden.FindByNameGardenUseCase
 	FindByCategoryNameGardenUseCase *usecases_garden.FindByCategoryNameGardenUseCase
 	FindAllHistoryGardenUseCase     *usecases_garden.FindAllHistoryGardenUseCase
+	SearchGardenUseCase             *usecases_garden.SearchGardenUseCase
+	SetAutoTasksGardenUseCase       *usecases_garden.SetAutoTasksGardenUseCase
+	LogCareGardenUseCase            *usecases_garden.LogCareGardenUseCase
+	JWTService                      services.JWTService
 }
 
 func NewGardenHandler(
@@ -33,6 +37,10 @@ func NewGardenHandler(
 	findByNameGardenUseCase *usecases_garden.FindByNameGardenUseCase,
 	findByCategoryNameGardenUseCase *usecases_garden.FindByCategoryNameGardenUseCase,
 	findAllHistoryGardenUseCase *usecases_garden.FindAllHistoryGardenUseCase,
+	searchGardenUseCase *usecases_garden.SearchGardenUseCase,
+	setAutoTasksGardenUseCase *usecases_garden.SetAutoTasksGardenUseCase,
+	logCareGardenUseCase *usecases_garden.LogCareGardenUseCase,
+	jwtService services.JWTService,
 ) *GardenHandler {
 	return &GardenHandler{
 		CreateGardenUseCase:             createGardenUseCase,
@@ -44,6 +52,10 @@ func NewGardenHandler(
 		FindByNameGardenUseCase:         findByNameGardenUseCase,
 		FindByCategoryNameGardenUseCase: findByCategoryNameGardenUseCase,
 		FindAllHistoryGardenUseCase:     findAllHistoryGardenUseCase,
+		SearchGardenUseCase:             searchGardenUseCase,
+		SetAutoTasksGardenUseCase:       setAutoTasksGardenUseCase,
+		LogCareGardenUseCase:            logCareGardenUseCase,
+		JWTService:                      jwtService,
 	}
 }
 
@@ -55,15 +67,15 @@ func (h *GardenHandler) CreateGardenHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserID = userId
-	garden, err := h.CreateGardenUseCase.Execute(context.Background(), input)
+	garden, err := h.CreateGardenUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, writeErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusCreated, "success", "Jardim criado com sucesso", garden)
@@ -76,18 +88,100 @@ func (h *GardenHandler) DeleteGardenHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input.UserID = userId
+	err = h.DeleteGardenUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, writeErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Jardim movido para a lixeira", nil)
+}
+
+// SetAutoTasksGardenHandler atende PUT /api/v1/garden/auto-tasks com {"id": "...", "auto_tasks": false}.
+func (h *GardenHandler) SetAutoTasksGardenHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_garden.SetAutoTasksGardenUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input.UserID = userId
+	garden, err := h.SetAutoTasksGardenUseCase.Execute(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, usecases_garden.ErrMissingAutoTasks) {
+			utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+			return
+		}
+		utils.JsonResponse(w, writeErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	setETag(w, garden.Version)
+	utils.JsonResponse(w, http.StatusOK, "success", "Tarefas automáticas do jardim atualizadas", garden)
+}
+
+// LogCareGardenHandler atende POST /api/v1/garden/{id}/care com {"type": "irrigation", "amount": 500}
+// e devolve o jardim atualizado.
+func (h *GardenHandler) LogCareGardenHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_garden.LogCareGardenUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserID = userId
-	err = h.DeleteGardenUseCase.Execute(context.Background(), input)
+	input.Ids = []string{chi.URLParam(r, "id")}
+	if _, err := h.LogCareGardenUseCase.Execute(r.Context(), input); err != nil {
+		utils.JsonResponse(w, careLogErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+
+	garden, err := h.FindByIdGardenUseCase.Execute(r.Context(), usecases_garden.FindByIdGardenUseCaseInputDTO{ID: input.Ids[0], UserId: userId})
 	if err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
 		return
 	}
-	utils.JsonResponse(w, http.StatusOK, "success", "Jardim deletado com sucesso", nil)
+	if garden != nil {
+		setETag(w, garden.Version)
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Cuidado registrado", garden)
+}
+
+// LogCareBulkGardenHandler atende POST /api/v1/garden/care com {"ids": [...], "type": "..."}; o
+// cuidado vale para todos os ids ou para nenhum.
+func (h *GardenHandler) LogCareBulkGardenHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_garden.LogCareGardenUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input.UserID = userId
+	output, err := h.LogCareGardenUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, careLogErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Cuidado registrado", output)
 }
 
 func (h *GardenHandler) FindAllGardenHandler(w http.ResponseWriter, r *http.Request) {
@@ -97,15 +191,16 @@ func (h *GardenHandler) FindAllGardenHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	gardens, err := h.FindAllGardenUseCase.Execute(context.Background(), input)
+	input.Page = parsePageRequest(r)
+	gardens, err := h.FindAllGardenUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Jardins encontrados", gardens)
@@ -118,17 +213,20 @@ func (h *GardenHandler) FindByIdGardenHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	garden, err := h.FindByIdGardenUseCase.Execute(context.Background(), input)
+	garden, err := h.FindByIdGardenUseCase.Execute(r.Context(), input)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
 		return
 	}
+	if garden != nil {
+		setETag(w, garden.Version)
+	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Jardim encontrado", garden)
 }
 
@@ -139,17 +237,29 @@ func (h *GardenHandler) UpdateGardenHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserID = userId
-	garden, err := h.UpdateGardenUseCase.Execute(context.Background(), input)
+	input.Version, err = ifMatchVersion(r, input.Version)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
 		return
 	}
+	garden, err := h.UpdateGardenUseCase.Execute(r.Context(), input)
+	if err != nil {
+		status := updateErrorStatus(err)
+		if status == http.StatusConflict && garden != nil {
+			setETag(w, garden.Version)
+			utils.JsonResponse(w, status, "error", err.Error(), garden)
+			return
+		}
+		utils.JsonResponse(w, status, "error", err.Error(), nil)
+		return
+	}
+	setETag(w, garden.Version)
 	utils.JsonResponse(w, http.StatusOK, "success", "Jardim atualizado com sucesso", garden)
 }
 
@@ -160,15 +270,16 @@ func (h *GardenHandler) FindByLocationGardenHandler(w http.ResponseWriter, r *ht
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	gardens, err := h.FindByLocationGardenUseCase.Execute(context.Background(), input)
+	input.Page = parsePageRequest(r)
+	gardens, err := h.FindByLocationGardenUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Jardins encontrados", gardens)
@@ -181,15 +292,16 @@ func (h *GardenHandler) FindByNameGardenHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	gardens, err := h.FindByNameGardenUseCase.Execute(context.Background(), input)
+	input.Page = parsePageRequest(r)
+	gardens, err := h.FindByNameGardenUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Jardins encontrados", gardens)
@@ -202,15 +314,16 @@ func (h *GardenHandler) FindByCategoryNameGardenHandler(w http.ResponseWriter, r
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	gardens, err := h.FindByCategoryNameGardenUseCase.Execute(context.Background(), input)
+	input.Page = parsePageRequest(r)
+	gardens, err := h.FindByCategoryNameGardenUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Jardins encontrados", gardens)
@@ -223,15 +336,42 @@ func (h *GardenHandler) FindAllHistoryGardenHandler(w http.ResponseWriter, r *ht
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	_, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
-	historyGardens, err := h.FindAllHistoryGardenUseCase.Execute(context.Background(), input)
+	input.UserID = userId
+	input.Page = parsePageRequest(r)
+	historyGardens, err := h.FindAllHistoryGardenUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Histórico encontrado", historyGardens)
 }
+
+func (h *GardenHandler) SearchGardenHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	filter, err := parseGardenFilter(r.URL.Query())
+	if err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		return
+	}
+	input := usecases_garden.SearchGardenUseCaseInputDTO{
+		UserId: userId,
+		Filter: filter,
+		Page:   parsePageRequest(r),
+	}
+	result, err := h.SearchGardenUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Jardins encontrados", result)
+}
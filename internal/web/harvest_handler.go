@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+	services "github.com/lucasBiazon/botany-back/internal/service"
+	usecases_harvest "github.com/lucasBiazon/botany-back/internal/usecases/harvest"
+	"github.com/lucasBiazon/botany-back/internal/utils"
+)
+
+// harvestErrorStatus estende writeErrorStatus com as validações de colheitas.
+func harvestErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrMissingHarvestPlant),
+		errors.Is(err, entities.ErrInvalidHarvestQuantity),
+		errors.Is(err, entities.ErrInvalidHarvestUnit),
+		errors.Is(err, entities.ErrInvalidHarvestGrade),
+		errors.Is(err, entities.ErrHarvestInFuture),
+		errors.Is(err, entities.ErrHarvestNotesTooLong),
+		errors.Is(err, entities.ErrInvalidPlantStatus),
+		errors.Is(err, entities.ErrInvalidHarvestGroup),
+		errors.Is(err, usecases_harvest.ErrMissingId),
+		errors.Is(err, entities.ErrInvalidCursor),
+		errors.Is(err, entities.ErrInvalidSort):
+		return http.StatusBadRequest
+	case errors.Is(err, entities.ErrPlantNotFound),
+		errors.Is(err, entities.ErrHarvestNotFound),
+		errors.Is(err, entities.ErrHarvestGardenNotFound):
+		return http.StatusNotFound
+	}
+	return writeErrorStatus(err)
+}
+
+type HarvestHandler struct {
+	CreateHarvestUseCase  *usecases_harvest.CreateHarvestUseCase
+	FindAllHarvestUseCase *usecases_harvest.FindAllHarvestUseCase
+	DeleteHarvestUseCase  *usecases_harvest.DeleteHarvestUseCase
+	StatsHarvestUseCase   *usecases_harvest.StatsHarvestUseCase
+	JWTService            services.JWTService
+}
+
+func NewHarvestHandler(
+	createHarvestUseCase *usecases_harvest.CreateHarvestUseCase,
+	findAllHarvestUseCase *usecases_harvest.FindAllHarvestUseCase,
+	deleteHarvestUseCase *usecases_harvest.DeleteHarvestUseCase,
+	statsHarvestUseCase *usecases_harvest.StatsHarvestUseCase,
+	jwtService services.JWTService,
+) *HarvestHandler {
+	return &HarvestHandler{
+		CreateHarvestUseCase:  createHarvestUseCase,
+		FindAllHarvestUseCase: findAllHarvestUseCase,
+		DeleteHarvestUseCase:  deleteHarvestUseCase,
+		StatsHarvestUseCase:   statsHarvestUseCase,
+		JWTService:            jwtService,
+	}
+}
+
+// CreateHarvestHandler atende POST /api/v1/harvest.
+func (h *HarvestHandler) CreateHarvestHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_harvest.CreateHarvestUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input.UserId = userId
+
+	harvest, err := h.CreateHarvestUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, harvestErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusCreated, "success", "Colheita registrada com sucesso", harvest)
+}
+
+// FindAllHarvestHandler atende GET /api/v1/harvest?plant_id=...&garden_id=...&from=...&to=...
+func (h *HarvestHandler) FindAllHarvestHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	query := r.URL.Query()
+	from, err := queryDate(query, "from", false)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		return
+	}
+	to, err := queryDate(query, "to", true)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		return
+	}
+
+	input := usecases_harvest.FindAllHarvestUseCaseInputDTO{
+		UserId: userId,
+		Filter: entities.HarvestFilter{
+			PlantId:  query.Get("plant_id"),
+			GardenId: query.Get("garden_id"),
+			From:     from,
+			To:       to,
+		},
+		Page: parsePageRequest(r),
+	}
+	harvests, err := h.FindAllHarvestUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, harvestErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Colheitas encontradas", harvests)
+}
+
+// DeleteHarvestHandler atende DELETE /api/v1/harvest com {"id": "..."}.
+func (h *HarvestHandler) DeleteHarvestHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_harvest.DeleteHarvestUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input.UserId = userId
+
+	if err := h.DeleteHarvestUseCase.Execute(r.Context(), input); err != nil {
+		utils.JsonResponse(w, harvestErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Colheita excluída com sucesso", nil)
+}
+
+// StatsHarvestHandler atende GET /api/v1/harvest/stats?group_by=species&from=...&to=...
+func (h *HarvestHandler) StatsHarvestHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	query := r.URL.Query()
+	from, err := queryDate(query, "from", false)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		return
+	}
+	to, err := queryDate(query, "to", true)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		return
+	}
+
+	input := usecases_harvest.StatsHarvestUseCaseInputDTO{
+		UserId: userId,
+		Query: entities.HarvestStatsQuery{
+			GroupBy: query.Get("group_by"),
+			From:    from,
+			To:      to,
+		},
+	}
+	stats, err := h.StatsHarvestUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, harvestErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Estatísticas de colheita calculadas", stats)
+}
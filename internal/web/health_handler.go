@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/lucasBiazon/botany-back/internal/database"
+	"github.com/lucasBiazon/botany-back/internal/utils"
+)
+
+type HealthHandler struct {
+	DB *database.Cluster
+	RD *redis.Client
+}
+
+func NewHealthHandler(db *database.Cluster, rd *redis.Client) *HealthHandler {
+	return &HealthHandler{
+		DB: db,
+		RD: rd,
+	}
+}
+
+// LivenessHandler só indica que o processo está de pé; não toca em dependências externas.
+func (h *HealthHandler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	utils.JsonResponse(w, http.StatusOK, "success", "ok", nil)
+}
+
+func (h *HealthHandler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	checks := map[string]string{
+		"postgres":   "ok",
+		"redis":      "ok",
+		"migrations": "ok",
+	}
+	ready := true
+	degraded := false
+
+	if err := h.DB.Primary.Ping(ctx); err != nil {
+		checks["postgres"] = err.Error()
+		ready = false
+	}
+
+	// réplica fora só tira as leituras dela; o primário continua atendendo
+	if h.DB.Replica != nil {
+		checks["replica"] = "ok"
+		if !h.DB.ReplicaHealthy() {
+			checks["replica"] = "degraded: indisponível, lendo do primário"
+			degraded = true
+		}
+	}
+
+	// sem Redis a API continua atendendo pelo Postgres, então só marca como degradado
+	if err := h.RD.Ping(ctx).Err(); err != nil {
+		checks["redis"] = "degraded: " + err.Error()
+		degraded = true
+	}
+
+	if status := h.migrationStatus(ctx); status != "" {
+		checks["migrations"] = status
+		ready = false
+	}
+
+	if !ready {
+		utils.JsonResponse(w, http.StatusServiceUnavailable, "error", "Serviço indisponível", checks)
+		return
+	}
+	if degraded {
+		utils.JsonResponse(w, http.StatusOK, "success", "Serviço pronto em modo degradado", checks)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Serviço pronto", checks)
+}
+
+func (h *HealthHandler) migrationStatus(ctx context.Context) string {
+	version, dirty, err := database.MigrationState(ctx, h.DB.Primary)
+	if err != nil {
+		return err.Error()
+	}
+	if dirty {
+		return fmt.Sprintf("migration %d em estado dirty", version)
+	}
+	latest, err := database.LatestMigrationVersion()
+	if err != nil {
+		return err.Error()
+	}
+	if version < latest {
+		return fmt.Sprintf("migrations pendentes: aplicada %d, esperada %d", version, latest)
+	}
+	return ""
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+	"github.com/lucasBiazon/botany-back/internal/utils"
+)
+
+// parsePageRequest lê limit, cursor, sort e order da query string das listagens.
+func parsePageRequest(r *http.Request) entities.PageRequest {
+	query := r.URL.Query()
+	return entities.PageRequest{
+		Limit:  utils.ParseQueryInt(query.Get("limit"), entities.DefaultPageLimit),
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+		Order:  query.Get("order"),
+	}
+}
+
+// listErrorStatus separa parâmetros de paginação inválidos de falhas internas.
+func listErrorStatus(err error) int {
+	if errors.Is(err, entities.ErrInvalidCursor) || errors.Is(err, entities.ErrInvalidSort) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -2,12 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"os"
 
+	"github.com/go-chi/chi"
+	"github.com/lucasBiazon/botany-back/internal/entities"
 	services "github.com/lucasBiazon/botany-back/internal/service"
 	usecases_plant "github.com/lucasBiazon/botany-back/internal/usecases/plant"
-	usecases_specie "github.com/lucasBiazon/botany-back/internal/usecases/specie"
 	"github.com/lucasBiazon/botany-back/internal/utils"
 )
 
@@ -20,8 +21,13 @@ type PlantHandler struct {
 	FindByNamePlantUseCase         *usecases_plant.FindByNamePlantUseCase
 	FindBySpecieNamePlantUseCase   *usecases_plant.FindBySpecieNamePlantUseCase
 	UpdatePlantUseCase             *usecases_plant.UpdatePlantUseCase
-	FindByIdSpecieUseCase          *usecases_specie.FindByIdSpecieUseCase
 	FindAllHistoryPlantUseCase     *usecases_plant.FindAllHistoryPlantUseCase
+	SearchPlantUseCase             *usecases_plant.SearchPlantUseCase
+	SetAutoTasksPlantUseCase       *usecases_plant.SetAutoTasksPlantUseCase
+	LogCarePlantUseCase            *usecases_plant.LogCarePlantUseCase
+	FindGrowthPlantUseCase         *usecases_plant.FindGrowthPlantUseCase
+	PredictHarvestPlantUseCase     *usecases_plant.PredictHarvestPlantUseCase
+	JWTService                     services.JWTService
 }
 
 func NewPlantHandler(
@@ -33,8 +39,13 @@ func NewPlantHandler(
 	findByNamePlantUseCase *usecases_plant.FindByNamePlantUseCase,
 	findBySpecieNamePlantUseCase *usecases_plant.FindBySpecieNamePlantUseCase,
 	updatePlantUseCase *usecases_plant.UpdatePlantUseCase,
-	findByIdSpecieUseCase *usecases_specie.FindByIdSpecieUseCase,
 	findAllHistoryPlantUseCase *usecases_plant.FindAllHistoryPlantUseCase,
+	searchPlantUseCase *usecases_plant.SearchPlantUseCase,
+	setAutoTasksPlantUseCase *usecases_plant.SetAutoTasksPlantUseCase,
+	logCarePlantUseCase *usecases_plant.LogCarePlantUseCase,
+	findGrowthPlantUseCase *usecases_plant.FindGrowthPlantUseCase,
+	predictHarvestPlantUseCase *usecases_plant.PredictHarvestPlantUseCase,
+	jwtService services.JWTService,
 ) *PlantHandler {
 	return &PlantHandler{
 		CreatePlantUseCase:             createPlantUseCase,
@@ -45,8 +56,13 @@ func NewPlantHandler(
 		FindByNamePlantUseCase:         findByNamePlantUseCase,
 		FindBySpecieNamePlantUseCase:   findBySpecieNamePlantUseCase,
 		UpdatePlantUseCase:             updatePlantUseCase,
-		FindByIdSpecieUseCase:          findByIdSpecieUseCase,
 		FindAllHistoryPlantUseCase:     findAllHistoryPlantUseCase,
+		SearchPlantUseCase:             searchPlantUseCase,
+		SetAutoTasksPlantUseCase:       setAutoTasksPlantUseCase,
+		LogCarePlantUseCase:            logCarePlantUseCase,
+		FindGrowthPlantUseCase:         findGrowthPlantUseCase,
+		PredictHarvestPlantUseCase:     predictHarvestPlantUseCase,
+		JWTService:                     jwtService,
 	}
 }
 
@@ -57,21 +73,19 @@ func (h *PlantHandler) CreatePlantHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
-	specieHaverstTime, err := h.FindByIdSpecieUseCase.Execute(r.Context(), usecases_specie.FindByIdSpecieInputDTO{Id: input.SpeciesID})
-	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
-		return
-	}
 	input.UserID = userId
-	input.SpecieHaverstTime = specieHaverstTime.HarvestTime
 	plant, err := h.CreatePlantUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		if errors.Is(err, entities.ErrSpecieNotFound) {
+			utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+			return
+		}
+		utils.JsonResponse(w, writeErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusCreated, "success", "Planta criada com sucesso", plant)
@@ -84,7 +98,7 @@ func (h *PlantHandler) DeletePlantHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
@@ -92,24 +106,158 @@ func (h *PlantHandler) DeletePlantHandler(w http.ResponseWriter, r *http.Request
 	input.UserID = userId
 	err = h.DeletePlantUseCase.Execute(r.Context(), input)
 	if err != nil {
+		utils.JsonResponse(w, writeErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Planta movida para a lixeira", nil)
+}
+
+// SetAutoTasksPlantHandler atende PUT /api/v1/plant/auto-tasks com {"id": "...", "auto_tasks": false}.
+func (h *PlantHandler) SetAutoTasksPlantHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_plant.SetAutoTasksPlantUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input.UserID = userId
+	plant, err := h.SetAutoTasksPlantUseCase.Execute(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, usecases_plant.ErrMissingAutoTasks) {
+			utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+			return
+		}
+		utils.JsonResponse(w, writeErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	setETag(w, plant.Version)
+	utils.JsonResponse(w, http.StatusOK, "success", "Tarefas automáticas da planta atualizadas", plant)
+}
+
+// LogCarePlantHandler atende POST /api/v1/plant/{id}/care com {"type": "irrigation", "amount": 500}
+// e devolve a planta atualizada.
+func (h *PlantHandler) LogCarePlantHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_plant.LogCarePlantUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input.UserID = userId
+	input.Ids = []string{chi.URLParam(r, "id")}
+	if _, err := h.LogCarePlantUseCase.Execute(r.Context(), input); err != nil {
+		utils.JsonResponse(w, careLogErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+
+	plant, err := h.FindByIdPlantUseCase.Execute(r.Context(), usecases_plant.FindByIdPlantUseCaseInputDTO{ID: input.Ids[0], UserID: userId})
+	if err != nil {
+		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		return
+	}
+	if plant != nil {
+		setETag(w, plant.Version)
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Cuidado registrado", plant)
+}
+
+// LogCareBulkPlantHandler atende POST /api/v1/plant/care com {"ids": [...], "type": "..."}; o
+// cuidado vale para todos os ids ou para nenhum.
+func (h *PlantHandler) LogCareBulkPlantHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_plant.LogCarePlantUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input.UserID = userId
+	output, err := h.LogCarePlantUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, careLogErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Cuidado registrado", output)
+}
+
+// FindGrowthPlantHandler atende GET /api/v1/plant/{id}/growth.
+func (h *PlantHandler) FindGrowthPlantHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input := usecases_plant.FindGrowthPlantUseCaseInputDTO{
+		Id:     chi.URLParam(r, "id"),
+		UserID: userId,
+	}
+	growth, err := h.FindGrowthPlantUseCase.Execute(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, entities.ErrPlantNotFound) {
+			utils.JsonResponse(w, http.StatusNotFound, "error", err.Error(), nil)
+			return
+		}
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
 		return
 	}
-	utils.JsonResponse(w, http.StatusOK, "success", "Planta deletada com sucesso", nil)
+	utils.JsonResponse(w, http.StatusOK, "success", "Análise de crescimento encontrada", growth)
+}
+
+// PredictHarvestPlantHandler atende GET /api/v1/plant/{id}/harvest-prediction.
+func (h *PlantHandler) PredictHarvestPlantHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input := usecases_plant.PredictHarvestPlantUseCaseInputDTO{
+		Id:     chi.URLParam(r, "id"),
+		UserID: userId,
+	}
+	prediction, err := h.PredictHarvestPlantUseCase.Execute(r.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrPlantNotFound), errors.Is(err, entities.ErrSpecieNotFound):
+			utils.JsonResponse(w, http.StatusNotFound, "error", err.Error(), nil)
+		case errors.Is(err, entities.ErrHarvestPredictionUnavailable):
+			utils.JsonResponse(w, http.StatusUnprocessableEntity, "error", err.Error(), nil)
+		default:
+			utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		}
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Previsão de colheita encontrada", prediction)
 }
 
 func (h *PlantHandler) FindAllPlantHandler(w http.ResponseWriter, r *http.Request) {
 	var input usecases_plant.FindAllPlantUseCaseInputDTO
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
+	input.Page = parsePageRequest(r)
 	plants, err := h.FindAllPlantUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Plantas encontradas", plants)
@@ -122,15 +270,16 @@ func (h *PlantHandler) FindByCategoryNamePlantHandler(w http.ResponseWriter, r *
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
+	input.Page = parsePageRequest(r)
 	plants, err := h.FindByCategoryNamePlantUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Plantas encontradas", plants)
@@ -143,7 +292,7 @@ func (h *PlantHandler) FindByIdPlantHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
@@ -154,6 +303,9 @@ func (h *PlantHandler) FindByIdPlantHandler(w http.ResponseWriter, r *http.Reque
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
 		return
 	}
+	if plant != nil {
+		setETag(w, plant.Version)
+	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Planta encontrada", plant)
 }
 
@@ -164,15 +316,16 @@ func (h *PlantHandler) FindByNamePlantHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
+	input.Page = parsePageRequest(r)
 	plant, err := h.FindByNamePlantUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Planta encontrada", plant)
@@ -185,15 +338,16 @@ func (h *PlantHandler) FindBySpecieNamePlantHandler(w http.ResponseWriter, r *ht
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
+	input.Page = parsePageRequest(r)
 	plants, err := h.FindBySpecieNamePlantUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Plantas encontradas", plants)
@@ -206,17 +360,32 @@ func (h *PlantHandler) UpdatePlantHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserID = userId
+	input.Version, err = ifMatchVersion(r, input.Version)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		return
+	}
 	plant, err := h.UpdatePlantUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		status := updateErrorStatus(err)
+		if errors.Is(err, entities.ErrSpecieNotFound) {
+			status = http.StatusBadRequest
+		}
+		if status == http.StatusConflict && plant != nil {
+			setETag(w, plant.Version)
+			utils.JsonResponse(w, status, "error", err.Error(), plant)
+			return
+		}
+		utils.JsonResponse(w, status, "error", err.Error(), nil)
 		return
 	}
+	setETag(w, plant.Version)
 	utils.JsonResponse(w, http.StatusOK, "success", "Planta atualizada com sucesso", plant)
 }
 
@@ -227,15 +396,42 @@ func (h *PlantHandler) FindAllHistoryPlantHandler(w http.ResponseWriter, r *http
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	_, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
+	input.UserID = userId
+	input.Page = parsePageRequest(r)
 	history, err := h.FindAllHistoryPlantUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Histórico encontrado", history)
 }
+
+func (h *PlantHandler) SearchPlantHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	filter, err := parsePlantFilter(r.URL.Query())
+	if err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		return
+	}
+	input := usecases_plant.SearchPlantUseCaseInputDTO{
+		UserId: userId,
+		Filter: filter,
+		Page:   parsePageRequest(r),
+	}
+	result, err := h.SearchPlantUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Plantas encontradas", result)
+}
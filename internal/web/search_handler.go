@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	services "github.com/lucasBiazon/botany-back/internal/service"
+	usecases_search "github.com/lucasBiazon/botany-back/internal/usecases/search"
+	"github.com/lucasBiazon/botany-back/internal/utils"
+)
+
+type SearchHandler struct {
+	SearchUseCase *usecases_search.SearchUseCase
+	JWTService    services.JWTService
+}
+
+func NewSearchHandler(searchUseCase *usecases_search.SearchUseCase, jwtService services.JWTService) *SearchHandler {
+	return &SearchHandler{
+		SearchUseCase: searchUseCase,
+		JWTService:    jwtService,
+	}
+}
+
+// GlobalSearchHandler atende GET /api/v1/search?q=...&mode=autocomplete&limit=...
+func (h *SearchHandler) GlobalSearchHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	mode := query.Get("mode")
+	if mode != "" && mode != usecases_search.ModeFull && mode != usecases_search.ModeAutocomplete {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Parâmetro mode inválido: use full ou autocomplete", nil)
+		return
+	}
+
+	input := usecases_search.SearchUseCaseInputDTO{
+		UserId: userId,
+		Query:  query.Get("q"),
+		Mode:   mode,
+		Limit:  utils.ParseQueryInt(query.Get("limit"), 0),
+	}
+	results, err := h.SearchUseCase.Execute(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, usecases_search.ErrEmptyQuery) {
+			utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+			return
+		}
+		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Resultados encontrados", results)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	usecases_specie "github.com/lucasBiazon/botany-back/internal/usecases/specie"
+	"github.com/lucasBiazon/botany-back/internal/utils"
+)
+
+// maxCatalogSize limita o corpo do import; o catálogo inteiro cabe com folga.
+const maxCatalogSize = 10 << 20
+
+type SpecieCatalogHandler struct {
+	ImportSpecieCatalogUseCase *usecases_specie.ImportSpecieCatalogUseCase
+	ExportSpecieCatalogUseCase *usecases_specie.ExportSpecieCatalogUseCase
+}
+
+func NewSpecieCatalogHandler(importSpecieCatalogUseCase *usecases_specie.ImportSpecieCatalogUseCase, exportSpecieCatalogUseCase *usecases_specie.ExportSpecieCatalogUseCase) *SpecieCatalogHandler {
+	return &SpecieCatalogHandler{
+		ImportSpecieCatalogUseCase: importSpecieCatalogUseCase,
+		ExportSpecieCatalogUseCase: exportSpecieCatalogUseCase,
+	}
+}
+
+// catalogFormat lê ?format=, senão deduz do Content-Type; o padrão é json.
+func catalogFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.ToLower(format)
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		return usecases_specie.CatalogFormatCSV
+	}
+	return usecases_specie.CatalogFormatJSON
+}
+
+// ImportSpecieCatalogHandler atende POST /api/v1/admin/species/import?format=csv|json&dry_run=true
+// com o arquivo no corpo. A resposta traz o diff com o catálogo atual.
+func (h *SpecieCatalogHandler) ImportSpecieCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	items, err := usecases_specie.DecodeSpecieCatalog(http.MaxBytesReader(w, r.Body, maxCatalogSize), catalogFormat(r))
+	if err != nil {
+		catalogErrorResponse(w, err)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	output, err := h.ImportSpecieCatalogUseCase.Execute(r.Context(), usecases_specie.ImportSpecieCatalogInputDTO{
+		Items:  items,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		catalogErrorResponse(w, err)
+		return
+	}
+	message := "Catálogo de espécies importado"
+	if dryRun {
+		message = "Simulação do import, nada foi gravado"
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", message, output)
+}
+
+// ExportSpecieCatalogHandler atende GET /api/v1/admin/species/export?format=csv|json
+// devolvendo o arquivo no formato que o import aceita.
+func (h *SpecieCatalogHandler) ExportSpecieCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	format := catalogFormat(r)
+	items, err := h.ExportSpecieCatalogUseCase.Execute(r.Context())
+	if err != nil {
+		catalogErrorResponse(w, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := usecases_specie.EncodeSpecieCatalog(&body, format, items); err != nil {
+		catalogErrorResponse(w, err)
+		return
+	}
+	contentType := "application/json"
+	if format == usecases_specie.CatalogFormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="species.%s"`, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+func catalogErrorResponse(w http.ResponseWriter, err error) {
+	var invalid *usecases_specie.CatalogValidationError
+	switch {
+	case errors.As(err, &invalid):
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Catálogo inválido", invalid.Problems)
+	case errors.Is(err, usecases_specie.ErrInvalidCatalogFormat):
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+	default:
+		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+	}
+}
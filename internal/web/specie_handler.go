@@ -1,10 +1,8 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
-	"os"
 
 	services "github.com/lucasBiazon/botany-back/internal/service"
 	usecases_specie "github.com/lucasBiazon/botany-back/internal/usecases/specie"
@@ -15,24 +13,26 @@ type SpecieHandler struct {
 	FindAllSpeciesUseCase   *usecases_specie.FindAllSpecieUseCase
 	FindByIdSpecieUseCase   *usecases_specie.FindByIdSpecieUseCase
 	FindByNameSpecieUseCase *usecases_specie.FindByNameSpecieUseCase
+	JWTService              services.JWTService
 }
 
-func NewSpecieHandler(findAllSpeciesUseCase *usecases_specie.FindAllSpecieUseCase, findByIdSpecieUseCase *usecases_specie.FindByIdSpecieUseCase, findByNameSpecieUseCase *usecases_specie.FindByNameSpecieUseCase) *SpecieHandler {
+func NewSpecieHandler(findAllSpeciesUseCase *usecases_specie.FindAllSpecieUseCase, findByIdSpecieUseCase *usecases_specie.FindByIdSpecieUseCase, findByNameSpecieUseCase *usecases_specie.FindByNameSpecieUseCase, jwtService services.JWTService) *SpecieHandler {
 	return &SpecieHandler{
 		FindAllSpeciesUseCase:   findAllSpeciesUseCase,
 		FindByIdSpecieUseCase:   findByIdSpecieUseCase,
 		FindByNameSpecieUseCase: findByNameSpecieUseCase,
+		JWTService:              jwtService,
 	}
 }
 
 func (h *SpecieHandler) FindAllSpeciesHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
-	_, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	_, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
-	species, err := h.FindAllSpeciesUseCase.Execute(context.Background())
+	species, err := h.FindAllSpeciesUseCase.Execute(r.Context())
 	if len(species) == 0 {
 		utils.JsonResponse(w, http.StatusNotFound, "error", "Nenhuma espécie encontrada", nil)
 		return
@@ -46,7 +46,7 @@ func (h *SpecieHandler) FindAllSpeciesHandler(w http.ResponseWriter, r *http.Req
 
 func (h *SpecieHandler) FindByIdSpecieHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
-	_, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	_, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
@@ -56,7 +56,7 @@ func (h *SpecieHandler) FindByIdSpecieHandler(w http.ResponseWriter, r *http.Req
 		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
 		return
 	}
-	species, err := h.FindByIdSpecieUseCase.Execute(context.Background(), input)
+	species, err := h.FindByIdSpecieUseCase.Execute(r.Context(), input)
 	if species == nil {
 		utils.JsonResponse(w, http.StatusNotFound, "error", "Espécie não encontrada", nil)
 		return
@@ -70,7 +70,7 @@ func (h *SpecieHandler) FindByIdSpecieHandler(w http.ResponseWriter, r *http.Req
 
 func (h *SpecieHandler) FindByNameSpecieHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
-	_, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	_, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
@@ -80,7 +80,7 @@ func (h *SpecieHandler) FindByNameSpecieHandler(w http.ResponseWriter, r *http.R
 		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
 		return
 	}
-	species, err := h.FindByNameSpecieUseCase.Execute(context.Background(), input)
+	species, err := h.FindByNameSpecieUseCase.Execute(r.Context(), input)
 	if len(species) == 0 {
 		utils.JsonResponse(w, http.StatusNotFound, "error", "Espécie não encontrada", nil)
 		return
@@ -1,10 +1,8 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
-	"os"
 
 	services "github.com/lucasBiazon/botany-back/internal/service"
 	usecases_task "github.com/lucasBiazon/botany-back/internal/usecases/task"
@@ -21,6 +19,8 @@ type TaskHandler struct {
 	FindByNameTaskUseCase         *usecases_task.FindByNameTaskUseCase
 	FindByStatusTaskUseCase       *usecases_task.FindByStatusTaskUseCase
 	FindByUrgencyLevelTaskUseCase *usecases_task.FindByUrgencyLevelTaskUseCase
+	SearchTaskUseCase             *usecases_task.SearchTaskUseCase
+	JWTService                    services.JWTService
 }
 
 func NewTaskHandler(
@@ -33,6 +33,8 @@ func NewTaskHandler(
 	findByNameTaskUseCase *usecases_task.FindByNameTaskUseCase,
 	findByStatusTaskUseCase *usecases_task.FindByStatusTaskUseCase,
 	findByUrgencyLevelTaskUseCase *usecases_task.FindByUrgencyLevelTaskUseCase,
+	searchTaskUseCase *usecases_task.SearchTaskUseCase,
+	jwtService services.JWTService,
 ) *TaskHandler {
 	return &TaskHandler{
 		CreateTaskUseCase:             createTaskUseCase,
@@ -44,6 +46,8 @@ func NewTaskHandler(
 		FindByNameTaskUseCase:         findByNameTaskUseCase,
 		FindByStatusTaskUseCase:       findByStatusTaskUseCase,
 		FindByUrgencyLevelTaskUseCase: findByUrgencyLevelTaskUseCase,
+		SearchTaskUseCase:             searchTaskUseCase,
+		JWTService:                    jwtService,
 	}
 }
 
@@ -54,15 +58,15 @@ func (h *TaskHandler) CreateTaskHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	task, err := h.CreateTaskUseCase.Execute(context.Background(), input)
+	task, err := h.CreateTaskUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, writeErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusCreated, "success", "Tarefa criada com sucesso", task)
@@ -75,18 +79,18 @@ func (h *TaskHandler) DeleteTaskHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	err = h.DeleteTaskUseCase.Execute(context.Background(), input)
+	err = h.DeleteTaskUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, writeErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
-	utils.JsonResponse(w, http.StatusOK, "success", "Tarefa deletada com sucesso", nil)
+	utils.JsonResponse(w, http.StatusOK, "success", "Tarefa movida para a lixeira", nil)
 }
 
 func (h *TaskHandler) FindAllTaskHandler(w http.ResponseWriter, r *http.Request) {
@@ -96,15 +100,16 @@ func (h *TaskHandler) FindAllTaskHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	tasks, err := h.FindAllTaskUseCase.Execute(context.Background(), input)
+	input.Page = parsePageRequest(r)
+	tasks, err := h.FindAllTaskUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Tarefas encontradas com sucesso", tasks)
@@ -117,15 +122,16 @@ func (h *TaskHandler) FindByCategoryNameTaskHandler(w http.ResponseWriter, r *ht
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	tasks, err := h.FindByCategoryNameTaskUseCase.Execute(context.Background(), input)
+	input.Page = parsePageRequest(r)
+	tasks, err := h.FindByCategoryNameTaskUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Tarefas encontradas com sucesso", tasks)
@@ -138,17 +144,20 @@ func (h *TaskHandler) FindByIdTaskHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	task, err := h.FindByIdTaskUseCase.Execute(context.Background(), input)
+	task, err := h.FindByIdTaskUseCase.Execute(r.Context(), input)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
 		return
 	}
+	if task != nil {
+		setETag(w, task.Version)
+	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Tarefa encontrada com sucesso", task)
 }
 
@@ -159,17 +168,29 @@ func (h *TaskHandler) UpdateTaskHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	task, err := h.UpdateTaskUseCase.Execute(context.Background(), input)
+	input.Version, err = ifMatchVersion(r, input.Version)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
 		return
 	}
+	task, err := h.UpdateTaskUseCase.Execute(r.Context(), input)
+	if err != nil {
+		status := updateErrorStatus(err)
+		if status == http.StatusConflict && task != nil {
+			setETag(w, task.Version)
+			utils.JsonResponse(w, status, "error", err.Error(), task)
+			return
+		}
+		utils.JsonResponse(w, status, "error", err.Error(), nil)
+		return
+	}
+	setETag(w, task.Version)
 	utils.JsonResponse(w, http.StatusOK, "success", "Tarefa atualizada com sucesso", task)
 }
 
@@ -180,15 +201,16 @@ func (h *TaskHandler) FindByNameTaskHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	tasks, err := h.FindByNameTaskUseCase.Execute(context.Background(), input)
+	input.Page = parsePageRequest(r)
+	tasks, err := h.FindByNameTaskUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Tarefas encontradas com sucesso", tasks)
@@ -201,15 +223,16 @@ func (h *TaskHandler) FindByStatusTaskHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	tasks, err := h.FindByStatusTaskUseCase.Execute(context.Background(), input)
+	input.Page = parsePageRequest(r)
+	tasks, err := h.FindByStatusTaskUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Tarefas encontradas com sucesso", tasks)
@@ -222,17 +245,42 @@ func (h *TaskHandler) FindByUrgencyLevelTaskHandler(w http.ResponseWriter, r *ht
 		return
 	}
 	auth := r.Header.Get("Authorization")
-	userId, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
 	input.UserId = userId
-	tasks, err := h.FindByUrgencyLevelTaskUseCase.Execute(context.Background(), input)
+	input.Page = parsePageRequest(r)
+	tasks, err := h.FindByUrgencyLevelTaskUseCase.Execute(r.Context(), input)
 	if err != nil {
-		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
 		return
 	}
 	utils.JsonResponse(w, http.StatusOK, "success", "Tarefas encontradas com sucesso", tasks)
 }
 
+func (h *TaskHandler) SearchTaskHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	filter, err := parseTaskFilter(r.URL.Query())
+	if err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		return
+	}
+	input := usecases_task.SearchTaskUseCaseInputDTO{
+		UserId: userId,
+		Filter: filter,
+		Page:   parsePageRequest(r),
+	}
+	result, err := h.SearchTaskUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, listErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Tarefas encontradas com sucesso", result)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+	services "github.com/lucasBiazon/botany-back/internal/service"
+	usecases_trash "github.com/lucasBiazon/botany-back/internal/usecases/trash"
+	"github.com/lucasBiazon/botany-back/internal/utils"
+)
+
+type TrashHandler struct {
+	ListTrashUseCase    *usecases_trash.ListTrashUseCase
+	RestoreTrashUseCase *usecases_trash.RestoreTrashUseCase
+	JWTService          services.JWTService
+}
+
+func NewTrashHandler(listTrashUseCase *usecases_trash.ListTrashUseCase, restoreTrashUseCase *usecases_trash.RestoreTrashUseCase, jwtService services.JWTService) *TrashHandler {
+	return &TrashHandler{
+		ListTrashUseCase:    listTrashUseCase,
+		RestoreTrashUseCase: restoreTrashUseCase,
+		JWTService:          jwtService,
+	}
+}
+
+// ListTrashHandler atende GET /api/v1/trash?type=...
+func (h *TrashHandler) ListTrashHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+
+	input := usecases_trash.ListTrashUseCaseInputDTO{
+		UserId: userId,
+		Type:   r.URL.Query().Get("type"),
+	}
+	items, err := h.ListTrashUseCase.Execute(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, usecases_trash.ErrInvalidType) {
+			utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+			return
+		}
+		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Itens da lixeira encontrados", items)
+}
+
+// RestoreTrashHandler atende POST /api/v1/trash/restore com {"type": "...", "id": "..."}.
+func (h *TrashHandler) RestoreTrashHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_trash.RestoreTrashUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	userId, err := services.ExtractUserIDFromToken(auth, h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return
+	}
+	input.UserId = userId
+
+	if err := h.RestoreTrashUseCase.Execute(r.Context(), input); err != nil {
+		switch {
+		case errors.Is(err, usecases_trash.ErrInvalidType), errors.Is(err, usecases_trash.ErrMissingId):
+			utils.JsonResponse(w, http.StatusBadRequest, "error", err.Error(), nil)
+		case errors.Is(err, entities.ErrTrashItemNotFound):
+			utils.JsonResponse(w, http.StatusNotFound, "error", err.Error(), nil)
+		default:
+			utils.JsonResponse(w, writeErrorStatus(err), "error", err.Error(), nil)
+		}
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Item restaurado com sucesso", nil)
+}
@@ -1,10 +1,8 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
-	"os"
 
 	services "github.com/lucasBiazon/botany-back/internal/service"
 	usecases "github.com/lucasBiazon/botany-back/internal/usecases/user"
@@ -19,11 +17,12 @@ type UserHandlers struct {
 	UpdateUserUseCase               *usecases.UpdateUserUseCase
 	RequestPasswordResetUserUseCase *usecases.RequestPasswordResetUserUseCase
 	ResetPasswordUserUseCase        *usecases.ResetPasswordUserUseCase
+	JWTService                      services.JWTService
 }
 
 func NewUserHandlers(registerUserUseCase *usecases.RegisterUserUseCase, loginUserUseCase *usecases.LoginUserUseCase,
 	findUserByIdUseCase *usecases.FindUserByIdUseCase, deleteUserUseCase *usecases.DeleteUserUseCase, updateUserUseCase *usecases.UpdateUserUseCase,
-	requestPasswordResetUserUseCase *usecases.RequestPasswordResetUserUseCase, resetPasswordUserUseCase *usecases.ResetPasswordUserUseCase) *UserHandlers {
+	requestPasswordResetUserUseCase *usecases.RequestPasswordResetUserUseCase, resetPasswordUserUseCase *usecases.ResetPasswordUserUseCase, jwtService services.JWTService) *UserHandlers {
 	return &UserHandlers{
 		RegisterUserUseCase:             registerUserUseCase,
 		LoginUserUseCase:                loginUserUseCase,
@@ -32,6 +31,7 @@ func NewUserHandlers(registerUserUseCase *usecases.RegisterUserUseCase, loginUse
 		UpdateUserUseCase:               updateUserUseCase,
 		RequestPasswordResetUserUseCase: requestPasswordResetUserUseCase,
 		ResetPasswordUserUseCase:        resetPasswordUserUseCase,
+		JWTService:                      jwtService,
 	}
 }
 
@@ -41,7 +41,7 @@ func (h *UserHandlers) RegisterUserHandler(w http.ResponseWriter, r *http.Reques
 		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
 		return
 	}
-	if err := h.RegisterUserUseCase.StartRegistration(context.Background(), input); err != nil {
+	if err := h.RegisterUserUseCase.StartRegistration(r.Context(), input); err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao registrar usuário", err.Error())
 		return
 	}
@@ -54,7 +54,7 @@ func (h *UserHandlers) ConfirmEmailHandler(w http.ResponseWriter, r *http.Reques
 		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
 		return
 	}
-	if err := h.RegisterUserUseCase.ConfirmEmail(context.Background(), input); err != nil {
+	if err := h.RegisterUserUseCase.ConfirmEmail(r.Context(), input); err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao confirmar email", err.Error())
 		return
 	}
@@ -67,7 +67,7 @@ func (h *UserHandlers) ResendTokenHandler(w http.ResponseWriter, r *http.Request
 		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
 		return
 	}
-	if err := h.RegisterUserUseCase.ResendToken(context.Background(), input); err != nil {
+	if err := h.RegisterUserUseCase.ResendToken(r.Context(), input); err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao reenviar código", err.Error())
 		return
 	}
@@ -80,7 +80,7 @@ func (h *UserHandlers) LoginUserHandler(w http.ResponseWriter, r *http.Request)
 		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
 		return
 	}
-	token, err := h.LoginUserUseCase.Execute(context.Background(), input)
+	token, err := h.LoginUserUseCase.Execute(r.Context(), input)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao logar", err.Error())
 		return
@@ -90,12 +90,12 @@ func (h *UserHandlers) LoginUserHandler(w http.ResponseWriter, r *http.Request)
 
 func (h *UserHandlers) FindByIdUserHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
-	userID, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userID, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
-	user, err := h.FindUserByIdUseCase.Execute(context.Background(), usecases.FindUserByIdInputDTO{Id: userID})
+	user, err := h.FindUserByIdUseCase.Execute(r.Context(), usecases.FindUserByIdInputDTO{Id: userID})
 	if err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao buscar usuário", err.Error())
 		return
@@ -109,12 +109,12 @@ func (h *UserHandlers) FindByIdUserHandler(w http.ResponseWriter, r *http.Reques
 
 func (h *UserHandlers) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
-	userID, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userID, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
 	}
-	if err := h.DeleteUserUseCase.Execute(context.Background(), usecases.DeleteUserInputDTO{Id: userID}); err != nil {
+	if err := h.DeleteUserUseCase.Execute(r.Context(), usecases.DeleteUserInputDTO{Id: userID}); err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao deletar usuário", err.Error())
 		return
 	}
@@ -123,7 +123,7 @@ func (h *UserHandlers) DeleteUserHandler(w http.ResponseWriter, r *http.Request)
 
 func (h *UserHandlers) UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
-	userID, err := services.ExtractUserIDFromToken(auth, services.NewJWTService(os.Getenv("JWT_SECRET_KEY")))
+	userID, err := services.ExtractUserIDFromToken(auth, h.JWTService)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
 		return
@@ -134,7 +134,7 @@ func (h *UserHandlers) UpdateUserHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	input.Id = userID
-	user, err := h.UpdateUserUseCase.Execute(context.Background(), input)
+	user, err := h.UpdateUserUseCase.Execute(r.Context(), input)
 	if err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao atualizar usuário", err.Error())
 		return
@@ -152,7 +152,7 @@ func (h *UserHandlers) RequestPasswordResetUserHandler(w http.ResponseWriter, r
 		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
 		return
 	}
-	if err := h.RequestPasswordResetUserUseCase.Execute(context.Background(), input); err != nil {
+	if err := h.RequestPasswordResetUserUseCase.Execute(r.Context(), input); err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao solicitar reset de senha", err.Error())
 		return
 	}
@@ -165,7 +165,7 @@ func (h *UserHandlers) ResetPasswordUserHandler(w http.ResponseWriter, r *http.R
 		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
 		return
 	}
-	if err := h.ResetPasswordUserUseCase.Execute(context.Background(), input); err != nil {
+	if err := h.ResetPasswordUserUseCase.Execute(r.Context(), input); err != nil {
 		utils.JsonResponse(w, http.StatusInternalServerError, "error", "Erro ao resetar senha", err.Error())
 		return
 	}
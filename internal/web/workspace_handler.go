@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lucasBiazon/botany-back/internal/entities"
+	services "github.com/lucasBiazon/botany-back/internal/service"
+	usecases_workspace "github.com/lucasBiazon/botany-back/internal/usecases/workspace"
+	"github.com/lucasBiazon/botany-back/internal/utils"
+)
+
+// writeErrorStatus traduz as recusas de autorização por workspace; o resto é falha interna.
+func writeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrWorkspaceForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, entities.ErrWorkspaceNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// workspaceErrorStatus estende writeErrorStatus com as regras de membros e convites.
+func workspaceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecases_workspace.ErrMissingId),
+		errors.Is(err, entities.ErrInvalidWorkspaceName),
+		errors.Is(err, entities.ErrInvalidWorkspaceRole),
+		errors.Is(err, entities.ErrInvalidInviteEmail):
+		return http.StatusBadRequest
+	case errors.Is(err, entities.ErrMemberNotFound), errors.Is(err, entities.ErrInvitationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrPersonalWorkspace), errors.Is(err, entities.ErrLastWorkspaceOwner):
+		return http.StatusConflict
+	}
+	return writeErrorStatus(err)
+}
+
+type WorkspaceHandler struct {
+	CreateWorkspaceUseCase           *usecases_workspace.CreateWorkspaceUseCase
+	FindAllWorkspaceUseCase          *usecases_workspace.FindAllWorkspaceUseCase
+	UpdateWorkspaceUseCase           *usecases_workspace.UpdateWorkspaceUseCase
+	DeleteWorkspaceUseCase           *usecases_workspace.DeleteWorkspaceUseCase
+	FindMembersWorkspaceUseCase      *usecases_workspace.FindMembersWorkspaceUseCase
+	UpdateMemberWorkspaceUseCase     *usecases_workspace.UpdateMemberWorkspaceUseCase
+	RemoveMemberWorkspaceUseCase     *usecases_workspace.RemoveMemberWorkspaceUseCase
+	InviteWorkspaceUseCase           *usecases_workspace.InviteWorkspaceUseCase
+	FindInvitationsWorkspaceUseCase  *usecases_workspace.FindInvitationsWorkspaceUseCase
+	AcceptInvitationWorkspaceUseCase *usecases_workspace.AcceptInvitationWorkspaceUseCase
+	JWTService                       services.JWTService
+}
+
+func NewWorkspaceHandler(
+	createWorkspaceUseCase *usecases_workspace.CreateWorkspaceUseCase,
+	findAllWorkspaceUseCase *usecases_workspace.FindAllWorkspaceUseCase,
+	updateWorkspaceUseCase *usecases_workspace.UpdateWorkspaceUseCase,
+	deleteWorkspaceUseCase *usecases_workspace.DeleteWorkspaceUseCase,
+	findMembersWorkspaceUseCase *usecases_workspace.FindMembersWorkspaceUseCase,
+	updateMemberWorkspaceUseCase *usecases_workspace.UpdateMemberWorkspaceUseCase,
+	removeMemberWorkspaceUseCase *usecases_workspace.RemoveMemberWorkspaceUseCase,
+	inviteWorkspaceUseCase *usecases_workspace.InviteWorkspaceUseCase,
+	findInvitationsWorkspaceUseCase *usecases_workspace.FindInvitationsWorkspaceUseCase,
+	acceptInvitationWorkspaceUseCase *usecases_workspace.AcceptInvitationWorkspaceUseCase,
+	jwtService services.JWTService,
+) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		CreateWorkspaceUseCase:           createWorkspaceUseCase,
+		FindAllWorkspaceUseCase:          findAllWorkspaceUseCase,
+		UpdateWorkspaceUseCase:           updateWorkspaceUseCase,
+		DeleteWorkspaceUseCase:           deleteWorkspaceUseCase,
+		FindMembersWorkspaceUseCase:      findMembersWorkspaceUseCase,
+		UpdateMemberWorkspaceUseCase:     updateMemberWorkspaceUseCase,
+		RemoveMemberWorkspaceUseCase:     removeMemberWorkspaceUseCase,
+		InviteWorkspaceUseCase:           inviteWorkspaceUseCase,
+		FindInvitationsWorkspaceUseCase:  findInvitationsWorkspaceUseCase,
+		AcceptInvitationWorkspaceUseCase: acceptInvitationWorkspaceUseCase,
+		JWTService:                       jwtService,
+	}
+}
+
+// userId lê o usuário do token, respondendo 401 quando não dá.
+func (h *WorkspaceHandler) userId(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userId, err := services.ExtractUserIDFromToken(r.Header.Get("Authorization"), h.JWTService)
+	if err != nil {
+		utils.JsonResponse(w, http.StatusUnauthorized, "error", "Token inválido ou expirado", nil)
+		return "", false
+	}
+	return userId, true
+}
+
+// CreateWorkspaceHandler atende POST /api/v1/workspace com {"name": "..."}.
+func (h *WorkspaceHandler) CreateWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_workspace.CreateWorkspaceUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+	input.UserId = userId
+
+	workspace, err := h.CreateWorkspaceUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, workspaceErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusCreated, "success", "Workspace criado com sucesso", workspace)
+}
+
+// FindAllWorkspaceHandler atende GET /api/v1/workspace.
+func (h *WorkspaceHandler) FindAllWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+
+	workspaces, err := h.FindAllWorkspaceUseCase.Execute(r.Context(), usecases_workspace.FindAllWorkspaceUseCaseInputDTO{UserId: userId})
+	if err != nil {
+		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Workspaces encontrados", workspaces)
+}
+
+// UpdateWorkspaceHandler atende PUT /api/v1/workspace com {"id": "...", "name": "..."}.
+func (h *WorkspaceHandler) UpdateWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_workspace.UpdateWorkspaceUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+	input.UserId = userId
+
+	workspace, err := h.UpdateWorkspaceUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, workspaceErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Workspace atualizado com sucesso", workspace)
+}
+
+// DeleteWorkspaceHandler atende DELETE /api/v1/workspace com {"id": "..."}.
+func (h *WorkspaceHandler) DeleteWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_workspace.DeleteWorkspaceUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+	input.UserId = userId
+
+	if err := h.DeleteWorkspaceUseCase.Execute(r.Context(), input); err != nil {
+		utils.JsonResponse(w, workspaceErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Workspace excluído com sucesso", nil)
+}
+
+// FindMembersWorkspaceHandler atende GET /api/v1/workspace/members?id=...
+func (h *WorkspaceHandler) FindMembersWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+	input := usecases_workspace.FindMembersWorkspaceUseCaseInputDTO{
+		UserId:      userId,
+		WorkspaceId: r.URL.Query().Get("id"),
+	}
+
+	members, err := h.FindMembersWorkspaceUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, workspaceErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Membros encontrados", members)
+}
+
+// UpdateMemberWorkspaceHandler atende PUT /api/v1/workspace/members com
+// {"workspace_id": "...", "member_id": "...", "role": "editor"}.
+func (h *WorkspaceHandler) UpdateMemberWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_workspace.UpdateMemberWorkspaceUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+	input.UserId = userId
+
+	members, err := h.UpdateMemberWorkspaceUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, workspaceErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Papel do membro atualizado com sucesso", members)
+}
+
+// RemoveMemberWorkspaceHandler atende DELETE /api/v1/workspace/members com
+// {"workspace_id": "...", "member_id": "..."}; sem member_id, o usuário sai do workspace.
+func (h *WorkspaceHandler) RemoveMemberWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_workspace.RemoveMemberWorkspaceUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+	input.UserId = userId
+
+	if err := h.RemoveMemberWorkspaceUseCase.Execute(r.Context(), input); err != nil {
+		utils.JsonResponse(w, workspaceErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Membro removido do workspace", nil)
+}
+
+// InviteWorkspaceHandler atende POST /api/v1/workspace/invitations com
+// {"workspace_id": "...", "email": "...", "role": "viewer"}.
+func (h *WorkspaceHandler) InviteWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_workspace.InviteWorkspaceUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+	input.UserId = userId
+
+	invitation, err := h.InviteWorkspaceUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, workspaceErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusCreated, "success", "Convite enviado com sucesso", invitation)
+}
+
+// FindInvitationsWorkspaceHandler atende GET /api/v1/workspace/invitations: os
+// convites pendentes para o email do usuário.
+func (h *WorkspaceHandler) FindInvitationsWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.FindInvitationsWorkspaceUseCase.Execute(r.Context(), usecases_workspace.FindInvitationsWorkspaceUseCaseInputDTO{UserId: userId})
+	if err != nil {
+		utils.JsonResponse(w, http.StatusInternalServerError, "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Convites encontrados", invitations)
+}
+
+// AcceptInvitationWorkspaceHandler atende POST /api/v1/workspace/invitations/accept
+// com {"invitation_id": "..."}.
+func (h *WorkspaceHandler) AcceptInvitationWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	var input usecases_workspace.AcceptInvitationWorkspaceUseCaseInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JsonResponse(w, http.StatusBadRequest, "error", "Erro ao decodificar a requisição", nil)
+		return
+	}
+	userId, ok := h.userId(w, r)
+	if !ok {
+		return
+	}
+	input.UserId = userId
+
+	workspace, err := h.AcceptInvitationWorkspaceUseCase.Execute(r.Context(), input)
+	if err != nil {
+		utils.JsonResponse(w, workspaceErrorStatus(err), "error", err.Error(), nil)
+		return
+	}
+	utils.JsonResponse(w, http.StatusOK, "success", "Convite aceito", workspace)
+}